@@ -0,0 +1,36 @@
+package clink
+
+import "net/http"
+
+// Future is the pending result of a request sent with DoAsync.
+type Future struct {
+	done chan struct{}
+	resp *http.Response
+	err  error
+}
+
+// DoAsync sends the request like Do in a new goroutine and returns a future for its result.
+// The caller must close the body of the response returned by Wait.
+func (c *Client) DoAsync(req *http.Request) *Future {
+	f := &Future{done: make(chan struct{})}
+
+	go func() {
+		defer close(f.done)
+		f.resp, f.err = c.Do(req)
+	}()
+
+	return f
+}
+
+// Done returns a channel that is closed once the request has completed.
+func (f *Future) Done() <-chan struct{} {
+	return f.done
+}
+
+// Wait blocks until the request has completed and returns its response and error.
+// It can be called multiple times and returns the same result each time.
+func (f *Future) Wait() (*http.Response, error) {
+	<-f.done
+
+	return f.resp, f.err
+}
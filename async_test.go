@@ -0,0 +1,82 @@
+package clink_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/davesavic/clink"
+)
+
+func TestClient_DoAsync(t *testing.T) {
+	testCases := []struct {
+		name       string
+		handler    http.HandlerFunc
+		resultFunc func(*testing.T, *clink.Future)
+	}{
+		{
+			name: "successful request",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				time.Sleep(10 * time.Millisecond)
+				w.WriteHeader(http.StatusCreated)
+			},
+			resultFunc: func(t *testing.T, f *clink.Future) {
+				select {
+				case <-f.Done():
+					t.Error("expected the future not to be done before the response")
+				default:
+				}
+
+				resp, err := f.Wait()
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				defer resp.Body.Close()
+
+				if resp.StatusCode != http.StatusCreated {
+					t.Errorf("expected status 201, got: %d", resp.StatusCode)
+				}
+
+				select {
+				case <-f.Done():
+				default:
+					t.Error("expected the future to be done after Wait")
+				}
+
+				again, _ := f.Wait()
+				if again != resp {
+					t.Error("expected Wait to return the same response")
+				}
+			},
+		},
+		{
+			name: "failed request",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusInternalServerError)
+			},
+			resultFunc: func(t *testing.T, f *clink.Future) {
+				<-f.Done()
+
+				_, err := f.Wait()
+				if err == nil {
+					t.Error("expected an error")
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(tc.handler)
+			defer server.Close()
+
+			c := clink.NewClient(clink.WithErrorOnStatus(func(resp *http.Response) bool {
+				return resp.StatusCode >= http.StatusInternalServerError
+			}))
+			req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+
+			tc.resultFunc(t, c.DoAsync(req))
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package clink
+
+import (
+	"maps"
+	"math/rand/v2"
+	"net/http"
+	"sync"
+	"sync/atomic"
+)
+
+// Endpoint describes an available endpoint to a Balancer.
+type Endpoint struct {
+	URL string
+	// Pending is the number of requests to the endpoint waiting for a response.
+	Pending int
+}
+
+// Balancer selects the endpoint set with WithEndpoints that a request is sent to.
+// Implementations must be safe for concurrent use.
+type Balancer interface {
+	// Pick returns the index of the endpoint the request is sent to. Endpoints that recently
+	// failed are not passed to Pick. If the request fails over, the other endpoints are tried
+	// in the order they were configured.
+	Pick(req *http.Request, endpoints []Endpoint) int
+}
+
+// WithBalancer spreads the requests across the endpoints set with WithEndpoints using the balancer,
+// instead of sending them to the primary endpoint first.
+func WithBalancer(balancer Balancer) Option {
+	return func(c *Client) {
+		c.endpointSet().balancer = balancer
+	}
+}
+
+// RoundRobin is a Balancer using the endpoints in turn.
+type RoundRobin struct {
+	next atomic.Uint64
+}
+
+func (b *RoundRobin) Pick(_ *http.Request, endpoints []Endpoint) int {
+	return int((b.next.Add(1) - 1) % uint64(len(endpoints)))
+}
+
+// LeastPending is a Balancer using the endpoint with the fewest pending requests, and the first
+// such endpoint on ties.
+type LeastPending struct{}
+
+func (LeastPending) Pick(_ *http.Request, endpoints []Endpoint) int {
+	best := 0
+	for i, e := range endpoints {
+		if e.Pending < endpoints[best].Pending {
+			best = i
+		}
+	}
+
+	return best
+}
+
+// Random is a Balancer using a random endpoint for each request.
+type Random struct{}
+
+func (Random) Pick(_ *http.Request, endpoints []Endpoint) int {
+	return rand.IntN(len(endpoints))
+}
+
+// Weighted is a Balancer picking endpoints at random in proportion to their weight, for example to
+// send 95% of the requests to a stable endpoint and 5% to a canary. Weights are keyed by the
+// endpoint URLs set with WithEndpoints, and can be changed at any time to shift traffic gradually.
+// Endpoints without a positive weight are only used when no weighted endpoint is available.
+type Weighted struct {
+	mu      sync.RWMutex
+	weights map[string]int
+}
+
+// NewWeighted returns a Weighted balancer with the given endpoint weights.
+func NewWeighted(weights map[string]int) *Weighted {
+	return &Weighted{weights: maps.Clone(weights)}
+}
+
+// SetWeights replaces the endpoint weights.
+func (b *Weighted) SetWeights(weights map[string]int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.weights = maps.Clone(weights)
+}
+
+// SetWeight sets the weight of an endpoint.
+func (b *Weighted) SetWeight(url string, weight int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.weights == nil {
+		b.weights = make(map[string]int)
+	}
+	b.weights[url] = weight
+}
+
+func (b *Weighted) Pick(_ *http.Request, endpoints []Endpoint) int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	total := 0
+	for _, e := range endpoints {
+		total += max(b.weights[e.URL], 0)
+	}
+
+	if total == 0 {
+		return 0
+	}
+
+	n := rand.IntN(total)
+	for i, e := range endpoints {
+		n -= max(b.weights[e.URL], 0)
+		if n < 0 {
+			return i
+		}
+	}
+
+	return 0
+}
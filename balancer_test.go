@@ -0,0 +1,141 @@
+package clink_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/davesavic/clink"
+)
+
+func TestBalancers(t *testing.T) {
+	endpoints := []clink.Endpoint{
+		{URL: "http://a.example.com", Pending: 3},
+		{URL: "http://b.example.com", Pending: 1},
+		{URL: "http://c.example.com", Pending: 1},
+	}
+
+	testCases := []struct {
+		name       string
+		balancer   clink.Balancer
+		resultFunc func(*testing.T, []int)
+	}{
+		{
+			name:     "round robin",
+			balancer: &clink.RoundRobin{},
+			resultFunc: func(t *testing.T, picks []int) {
+				for i, pick := range picks {
+					if pick != i%3 {
+						t.Errorf("expected the endpoints in turn, got: %v", picks)
+						return
+					}
+				}
+			},
+		},
+		{
+			name:     "least pending",
+			balancer: clink.LeastPending{},
+			resultFunc: func(t *testing.T, picks []int) {
+				for _, pick := range picks {
+					if pick != 1 {
+						t.Errorf("expected the first endpoint with the fewest pending requests, got: %v", picks)
+						return
+					}
+				}
+			},
+		},
+		{
+			name:     "random",
+			balancer: clink.Random{},
+			resultFunc: func(t *testing.T, picks []int) {
+				for _, pick := range picks {
+					if pick < 0 || pick > 2 {
+						t.Errorf("expected valid endpoint indexes, got: %v", picks)
+						return
+					}
+				}
+			},
+		},
+		{
+			name:     "weighted",
+			balancer: clink.NewWeighted(map[string]int{"http://b.example.com": 1, "http://c.example.com": 0}),
+			resultFunc: func(t *testing.T, picks []int) {
+				for _, pick := range picks {
+					if pick != 1 {
+						t.Errorf("expected the only weighted endpoint, got: %v", picks)
+						return
+					}
+				}
+			},
+		},
+		{
+			name: "weighted shifted",
+			balancer: func() clink.Balancer {
+				b := clink.NewWeighted(map[string]int{"http://a.example.com": 95, "http://c.example.com": 5})
+				b.SetWeight("http://a.example.com", 0)
+				return b
+			}(),
+			resultFunc: func(t *testing.T, picks []int) {
+				for _, pick := range picks {
+					if pick != 2 {
+						t.Errorf("expected the traffic to be shifted to the last endpoint, got: %v", picks)
+						return
+					}
+				}
+			},
+		},
+		{
+			name:     "weighted without weights",
+			balancer: clink.NewWeighted(nil),
+			resultFunc: func(t *testing.T, picks []int) {
+				for _, pick := range picks {
+					if pick != 0 {
+						t.Errorf("expected the first endpoint, got: %v", picks)
+						return
+					}
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var picks []int
+			for i := 0; i < 6; i++ {
+				picks = append(picks, tc.balancer.Pick(nil, endpoints))
+			}
+
+			tc.resultFunc(t, picks)
+		})
+	}
+}
+
+func TestWithBalancer(t *testing.T) {
+	newServer := func(name string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(name))
+		}))
+	}
+
+	a, b := newServer("a"), newServer("b")
+	defer a.Close()
+	defer b.Close()
+
+	c := clink.NewClient(clink.WithBalancer(&clink.RoundRobin{}), clink.WithEndpoints(a.URL, b.URL))
+
+	var got string
+	for i := 0; i < 4; i++ {
+		resp, err := c.Get("/")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		got += string(body)
+	}
+
+	if got != "abab" {
+		t.Errorf("expected the requests to alternate between the endpoints, got: %s", got)
+	}
+}
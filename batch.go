@@ -0,0 +1,187 @@
+package clink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// DefaultBatchConcurrency is the number of requests sent concurrently by DoBatch unless configured.
+const DefaultBatchConcurrency = 10
+
+// BatchOptions configures DoBatch.
+type BatchOptions struct {
+	// Concurrency is the maximum number of requests in flight. DefaultBatchConcurrency is used if it is not positive.
+	Concurrency int
+	// StopOnError stops sending the remaining requests after the first failed one.
+	StopOnError bool
+	// Handle, if set, is called with each response from the worker that sent the request, while
+	// the batch is still running. The body of the response is closed when Handle returns, and the
+	// error returned by Handle is the error of the result, whose Response is then nil.
+	Handle func(index int, resp *http.Response) error
+}
+
+// ErrBatchAborted is returned for the requests of a batch that weren't sent because an earlier
+// request failed and BatchOptions.StopOnError is set.
+var ErrBatchAborted = errors.New("batch aborted after a failed request")
+
+// BatchResult is the outcome of one request of a batch. Either Response or Err is set, unless
+// BatchOptions.Handle is set, in which case Response is always nil.
+type BatchResult struct {
+	Response *http.Response
+	Err      error
+}
+
+// DoBatch sends the requests concurrently, with at most opts.Concurrency in flight, and returns
+// their results in the same order. The requests share the client's rate limiter and retries. Each
+// request keeps the values of its own context, such as the options set with ConfigureRequest, and
+// is cancelled when ctx is done. Requests not yet sent when ctx is done fail with its error, and
+// with ErrBatchAborted after a failure if opts.StopOnError is set. Callers must close the bodies
+// of the returned responses.
+//
+// An unclosed body holds its connection, and its slot of WithMaxConcurrency or WithBulkhead, until
+// it is closed. Without opts.Handle, a batch larger than these limits therefore stalls once they
+// are reached: use opts.Handle to consume each response while the batch runs.
+func (c *Client) DoBatch(ctx context.Context, reqs []*http.Request, opts BatchOptions) []BatchResult {
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultBatchConcurrency
+	}
+
+	results := make([]BatchResult, len(reqs))
+	sem := make(chan struct{}, concurrency)
+
+	var mu sync.Mutex
+	var abortErr error
+	aborted := func() error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if abortErr != nil {
+			return abortErr
+		}
+
+		return ctx.Err()
+	}
+
+	var wg sync.WaitGroup
+	for i, req := range reqs {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			results[i].Err = ctx.Err()
+			continue
+		}
+
+		if err := aborted(); err != nil {
+			<-sem
+			results[i].Err = err
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			reqCtx, cancel := batchContext(req.Context(), ctx)
+			resp, err := c.Do(req.WithContext(reqCtx))
+			if err != nil {
+				cancel()
+			} else {
+				resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+				if opts.Handle != nil {
+					err = opts.Handle(i, resp)
+					_ = resp.Body.Close()
+					resp = nil
+				}
+			}
+			results[i] = BatchResult{Response: resp, Err: err}
+
+			if err != nil && opts.StopOnError {
+				mu.Lock()
+				if abortErr == nil {
+					abortErr = fmt.Errorf("%w: %w", ErrBatchAborted, err)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	return results
+}
+
+// batchContext returns a context carrying the values of parent, which is done when either parent
+// or ctx is done. The returned function releases its resources.
+func batchContext(parent, ctx context.Context) (context.Context, context.CancelFunc) {
+	merged, cancel := context.WithCancelCause(parent)
+
+	cancelDeadline := context.CancelFunc(func() {})
+	if deadline, ok := ctx.Deadline(); ok {
+		merged, cancelDeadline = context.WithDeadline(merged, deadline)
+	}
+
+	stop := context.AfterFunc(ctx, func() { cancel(context.Cause(ctx)) })
+
+	return merged, func() {
+		stop()
+		cancelDeadline()
+		cancel(nil)
+	}
+}
+
+// Result is the outcome of fetching and decoding one URL. Err is set if the request or decoding failed.
+type Result[T any] struct {
+	URL   string
+	Value T
+	Err   error
+}
+
+// GetAll fetches the URLs concurrently with the client, with at most concurrency requests in flight,
+// and decodes each response into a T using the client's codecs as soon as it is received. The
+// Accept header is set as by Pages. Responses with a status other than 2xx fail with an *HTTPError.
+// Results are returned in the order of the URLs, with a per-item error for failed ones. The
+// returned error is only set if ctx is done before every URL has been fetched.
+func GetAll[T any](ctx context.Context, client *Client, urls []string, concurrency int) ([]Result[T], error) {
+	results := make([]Result[T], len(urls))
+	reqs := make([]*http.Request, 0, len(urls))
+	indexes := make([]int, 0, len(urls))
+
+	var zero T
+	accept := acceptHeader(client.config().Codecs, &zero)
+
+	for i, url := range urls {
+		results[i].URL = url
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			results[i].Err = fmt.Errorf("failed to create request: %w", err)
+			continue
+		}
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+
+		reqs = append(reqs, req)
+		indexes = append(indexes, i)
+	}
+
+	handle := func(j int, resp *http.Response) error {
+		if !isSuccessStatus(resp) {
+			return newHTTPError(resp, client.config().Redactor)
+		}
+
+		return client.Decode(resp, &results[indexes[j]].Value)
+	}
+
+	batch := client.DoBatch(ctx, reqs, BatchOptions{Concurrency: concurrency, Handle: handle})
+	for j, batchResult := range batch {
+		results[indexes[j]].Err = batchResult.Err
+	}
+
+	return results, ctx.Err()
+}
@@ -0,0 +1,283 @@
+package clink_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/davesavic/clink"
+)
+
+func TestClient_DoBatch(t *testing.T) {
+	testCases := []struct {
+		name       string
+		count      int
+		opts       clink.BatchOptions
+		ctx        func() context.Context
+		resultFunc func(t *testing.T, results []clink.BatchResult, maxInFlight int64)
+	}{
+		{
+			name:  "returns results in order with bounded concurrency",
+			count: 20,
+			opts:  clink.BatchOptions{Concurrency: 3},
+			ctx:   context.Background,
+			resultFunc: func(t *testing.T, results []clink.BatchResult, maxInFlight int64) {
+				for i, result := range results {
+					if result.Err != nil {
+						t.Fatalf("unexpected error for request %d: %v", i, result.Err)
+					}
+					body, _ := io.ReadAll(result.Response.Body)
+					_ = result.Response.Body.Close()
+					if string(body) != strconv.Itoa(i) {
+						t.Errorf("expected result %d, got: %s", i, body)
+					}
+				}
+				if maxInFlight > 3 {
+					t.Errorf("expected at most 3 requests in flight, got: %d", maxInFlight)
+				}
+			},
+		},
+		{
+			name:  "stops after first error",
+			count: 5,
+			opts:  clink.BatchOptions{Concurrency: 1, StopOnError: true},
+			ctx:   context.Background,
+			resultFunc: func(t *testing.T, results []clink.BatchResult, _ int64) {
+				if results[0].Err != nil || results[1].Err == nil {
+					t.Fatalf("expected second request to fail, got: %v, %v", results[0].Err, results[1].Err)
+				}
+				_ = results[0].Response.Body.Close()
+				for _, result := range results[2:] {
+					if !errors.Is(result.Err, clink.ErrBatchAborted) {
+						t.Errorf("expected aborted error, got: %v", result.Err)
+					}
+				}
+			},
+		},
+		{
+			name:  "fails remaining requests when context is cancelled",
+			count: 3,
+			opts:  clink.BatchOptions{Concurrency: 1},
+			ctx: func() context.Context {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+				return ctx
+			},
+			resultFunc: func(t *testing.T, results []clink.BatchResult, _ int64) {
+				for _, result := range results {
+					if !errors.Is(result.Err, context.Canceled) {
+						t.Errorf("expected canceled error, got: %v", result.Err)
+					}
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var inFlight, maxInFlight atomic.Int64
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := inFlight.Add(1)
+				defer inFlight.Add(-1)
+				for {
+					max := maxInFlight.Load()
+					if n <= max || maxInFlight.CompareAndSwap(max, n) {
+						break
+					}
+				}
+				time.Sleep(5 * time.Millisecond)
+
+				if r.URL.Query().Get("i") == "1" && tc.opts.StopOnError {
+					w.WriteHeader(http.StatusInternalServerError)
+					return
+				}
+				_, _ = w.Write([]byte(r.URL.Query().Get("i")))
+			}))
+			defer server.Close()
+
+			c := clink.NewClient(clink.WithErrorOnStatus(nil))
+
+			reqs := make([]*http.Request, tc.count)
+			for i := range reqs {
+				reqs[i], _ = http.NewRequest(http.MethodGet, server.URL+"?i="+strconv.Itoa(i), nil)
+			}
+
+			tc.resultFunc(t, c.DoBatch(tc.ctx(), reqs, tc.opts), maxInFlight.Load())
+		})
+	}
+}
+
+func TestGetAll(t *testing.T) {
+	type item struct {
+		ID int `json:"id"`
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":` + r.URL.Path[1:] + `}`))
+	}))
+	defer server.Close()
+
+	c := clink.NewClient(clink.WithErrorOnStatus(nil))
+	urls := []string{server.URL + "/1", server.URL + "/missing", server.URL + "/3", "://invalid"}
+
+	results, err := clink.GetAll[item](context.Background(), c, urls, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	testCases := []struct {
+		name       string
+		result     clink.Result[item]
+		resultFunc func(clink.Result[item]) bool
+	}{
+		{
+			name:   "decodes successful response",
+			result: results[0],
+			resultFunc: func(r clink.Result[item]) bool {
+				return r.Err == nil && r.Value.ID == 1 && r.URL == urls[0]
+			},
+		},
+		{
+			name:   "reports failed response",
+			result: results[1],
+			resultFunc: func(r clink.Result[item]) bool {
+				var httpErr *clink.HTTPError
+				return errors.As(r.Err, &httpErr) && httpErr.StatusCode == http.StatusNotFound
+			},
+		},
+		{
+			name:   "keeps order",
+			result: results[2],
+			resultFunc: func(r clink.Result[item]) bool {
+				return r.Err == nil && r.Value.ID == 3
+			},
+		},
+		{
+			name:   "reports invalid url",
+			result: results[3],
+			resultFunc: func(r clink.Result[item]) bool {
+				return r.Err != nil
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if !tc.resultFunc(tc.result) {
+				t.Errorf("unexpected result: %+v", tc.result)
+			}
+		})
+	}
+}
+
+func TestClient_DoBatch_RequestOptions(t *testing.T) {
+	var attempts atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	always := func(*http.Request, *http.Response, error) bool { return true }
+	c := clink.NewClient(clink.WithTestMode(), clink.WithErrorOnStatus(nil))
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	results := c.DoBatch(context.Background(), []*http.Request{clink.ConfigureRequest(req, clink.Retries(2, always))}, clink.BatchOptions{})
+
+	if results[0].Err == nil {
+		t.Errorf("expected the request to fail")
+	}
+
+	if attempts.Load() != 3 {
+		t.Errorf("expected the per-request retries to be used, got %d attempts", attempts.Load())
+	}
+}
+
+func TestClient_DoBatch_Handle(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.URL.Query().Get("i")))
+	}))
+	defer server.Close()
+
+	c := clink.NewClient(clink.WithMaxConcurrency(2))
+
+	reqs := make([]*http.Request, 6)
+	for i := range reqs {
+		reqs[i], _ = http.NewRequest(http.MethodGet, server.URL+"?i="+strconv.Itoa(i), nil)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	bodies := make([]string, len(reqs))
+	results := c.DoBatch(ctx, reqs, clink.BatchOptions{
+		Concurrency: 4,
+		Handle: func(i int, resp *http.Response) error {
+			body, err := io.ReadAll(resp.Body)
+			bodies[i] = string(body)
+			return err
+		},
+	})
+
+	for i, result := range results {
+		if result.Err != nil || result.Response != nil {
+			t.Fatalf("expected request %d to be handled, got: %+v", i, result)
+		}
+		if bodies[i] != strconv.Itoa(i) {
+			t.Errorf("expected body %d, got: %s", i, bodies[i])
+		}
+	}
+}
+
+func TestGetAll_MaxConcurrency(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(r.URL.Path[1:]))
+	}))
+	defer server.Close()
+
+	c := clink.NewClient(clink.WithMaxConcurrency(2))
+	urls := []string{server.URL + "/1", server.URL + "/2", server.URL + "/3", server.URL + "/4"}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	results, err := clink.GetAll[int](ctx, c, urls, 4)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for i, result := range results {
+		if result.Err != nil || result.Value != i+1 {
+			t.Errorf("expected result %d to be decoded, got: %+v", i+1, result)
+		}
+	}
+}
+
+func TestGetAll_ErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	results, err := clink.GetAll[int](context.Background(), clink.NewClient(), []string{server.URL}, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var httpErr *clink.HTTPError
+	if !errors.As(results[0].Err, &httpErr) || httpErr.StatusCode != http.StatusNotFound {
+		t.Errorf("expected a 404 HTTPError, got: %+v", results[0])
+	}
+}
@@ -0,0 +1,153 @@
+package clink
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned when a request isn't sent because its circuit breaker is open.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// Default settings of CircuitBreaker.
+const (
+	DefaultCircuitFailureThreshold = 5
+	DefaultCircuitOpenTimeout      = 30 * time.Second
+)
+
+// CircuitBreaker configures the circuit breaker of WithCircuitBreaker. Zero fields are set to their default.
+type CircuitBreaker struct {
+	// FailureThreshold is the number of consecutive failed requests after which a circuit opens.
+	// DefaultCircuitFailureThreshold is used if it is not positive.
+	FailureThreshold int
+	// OpenTimeout is how long a circuit stays open before a trial request is let through.
+	// DefaultCircuitOpenTimeout is used if it is not positive.
+	OpenTimeout time.Duration
+	// Key returns the circuit a request belongs to. Requests with different keys have independent
+	// circuits. CircuitPerHost is used if it is nil.
+	Key func(*http.Request) string
+}
+
+// CircuitPerHost is a CircuitBreaker key giving each destination host its own circuit.
+func CircuitPerHost(req *http.Request) string {
+	return req.URL.Host
+}
+
+// CircuitPerRoute is a CircuitBreaker key giving each destination host and path its own circuit.
+func CircuitPerRoute(req *http.Request) string {
+	return req.URL.Host + req.URL.Path
+}
+
+// WithCircuitBreaker stops sending requests to a destination after consecutive failures: once the
+// circuit of a destination opens, its requests fail with ErrCircuitOpen without being sent or
+// retried. After the open timeout, a single trial request is sent, which closes the circuit if it
+// succeeds or opens it again if it fails. A request fails if it can't be sent or the server
+// responds with a 5xx status. Each host has its own circuit, unless set otherwise with the Key of
+// the circuit breaker, so a failing dependency doesn't affect the others.
+func WithCircuitBreaker(breaker CircuitBreaker) Option {
+	return func(c *Client) {
+		if breaker.FailureThreshold <= 0 {
+			breaker.FailureThreshold = DefaultCircuitFailureThreshold
+		}
+
+		if breaker.OpenTimeout <= 0 {
+			breaker.OpenTimeout = DefaultCircuitOpenTimeout
+		}
+
+		if breaker.Key == nil {
+			breaker.Key = CircuitPerHost
+		}
+
+		c.breaker = &circuitBreaker{config: breaker, circuits: make(map[string]*circuit)}
+	}
+}
+
+// breakRoundTrip sends the request with the HTTP client if its circuit is closed, and records the result.
+func (c *Client) breakRoundTrip(req *http.Request) (*http.Response, error) {
+	if c.breaker == nil {
+		return c.HttpClient.Do(req)
+	}
+
+	key := c.breaker.config.Key(req)
+	if err := c.breaker.allow(key, c.clock.Now()); err != nil {
+		return nil, err
+	}
+
+	resp, err := c.HttpClient.Do(req)
+	if c.breaker.record(key, req, resp, err, c.clock.Now()) {
+		c.notifyCircuitOpened(req, key, c.breaker.config.OpenTimeout)
+	}
+
+	return resp, err
+}
+
+type circuitBreaker struct {
+	config CircuitBreaker
+
+	mu       sync.Mutex
+	circuits map[string]*circuit
+}
+
+// circuit is the state of the requests with the same key. Closed circuits without failures are
+// removed from the breaker.
+type circuit struct {
+	failures  int
+	openUntil time.Time
+	trial     bool
+}
+
+// allow returns ErrCircuitOpen if the request can't be sent because its circuit is open.
+func (b *circuitBreaker) allow(key string, now time.Time) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	state, ok := b.circuits[key]
+	if !ok || state.openUntil.IsZero() {
+		return nil
+	}
+
+	if now.Before(state.openUntil) || state.trial {
+		return fmt.Errorf("%w for %s", ErrCircuitOpen, key)
+	}
+
+	state.trial = true
+
+	return nil
+}
+
+// record updates the circuit with the result of a request, and reports whether the circuit opened.
+func (b *circuitBreaker) record(key string, req *http.Request, resp *http.Response, err error, now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	state, ok := b.circuits[key]
+	if req.Context().Err() != nil {
+		if ok {
+			state.trial = false
+		}
+		return false
+	}
+
+	if err == nil && resp.StatusCode < http.StatusInternalServerError {
+		delete(b.circuits, key)
+		return false
+	}
+
+	if !ok {
+		state = &circuit{}
+		b.circuits[key] = state
+	}
+
+	state.failures++
+	if !state.trial && state.failures < b.config.FailureThreshold {
+		return false
+	}
+
+	state.failures = 0
+	state.trial = false
+	state.openUntil = now.Add(b.config.OpenTimeout)
+
+	return true
+}
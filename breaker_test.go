@@ -0,0 +1,125 @@
+package clink_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/davesavic/clink"
+)
+
+func TestWithCircuitBreaker(t *testing.T) {
+	newServer := func(status *atomic.Int64, hits *atomic.Int64) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			hits.Add(1)
+			w.WriteHeader(int(status.Load()))
+		}))
+	}
+
+	testCases := []struct {
+		name       string
+		breaker    clink.CircuitBreaker
+		resultFunc func(t *testing.T, c *clink.Client, failing, healthy string, failingHits *atomic.Int64, failingStatus *atomic.Int64)
+	}{
+		{
+			name:    "circuit opens per host",
+			breaker: clink.CircuitBreaker{FailureThreshold: 2, OpenTimeout: time.Hour},
+			resultFunc: func(t *testing.T, c *clink.Client, failing, healthy string, failingHits, _ *atomic.Int64) {
+				for i := 0; i < 2; i++ {
+					if _, err := c.Get(failing + "/a"); err != nil {
+						t.Fatalf("unexpected error: %v", err)
+					}
+				}
+
+				if _, err := c.Get(failing + "/b"); !errors.Is(err, clink.ErrCircuitOpen) {
+					t.Errorf("expected ErrCircuitOpen, got: %v", err)
+				}
+
+				if failingHits.Load() != 2 {
+					t.Errorf("expected the open circuit to stop requests, got %d requests", failingHits.Load())
+				}
+
+				if resp, err := c.Get(healthy); err != nil || resp.StatusCode != http.StatusOK {
+					t.Errorf("expected the other host to be unaffected, got: %v", err)
+				}
+			},
+		},
+		{
+			name:    "circuit per route",
+			breaker: clink.CircuitBreaker{FailureThreshold: 2, OpenTimeout: time.Hour, Key: clink.CircuitPerRoute},
+			resultFunc: func(t *testing.T, c *clink.Client, failing, _ string, _, _ *atomic.Int64) {
+				for i := 0; i < 2; i++ {
+					_, _ = c.Get(failing + "/a")
+				}
+
+				if _, err := c.Get(failing + "/a"); !errors.Is(err, clink.ErrCircuitOpen) {
+					t.Errorf("expected ErrCircuitOpen, got: %v", err)
+				}
+
+				if _, err := c.Get(failing + "/b"); err != nil {
+					t.Errorf("expected the other route to be unaffected, got: %v", err)
+				}
+			},
+		},
+		{
+			name:    "circuit closes after successful trial",
+			breaker: clink.CircuitBreaker{FailureThreshold: 1, OpenTimeout: 20 * time.Millisecond},
+			resultFunc: func(t *testing.T, c *clink.Client, failing, _ string, _, failingStatus *atomic.Int64) {
+				_, _ = c.Get(failing)
+				if _, err := c.Get(failing); !errors.Is(err, clink.ErrCircuitOpen) {
+					t.Fatalf("expected ErrCircuitOpen, got: %v", err)
+				}
+
+				failingStatus.Store(http.StatusOK)
+				time.Sleep(30 * time.Millisecond)
+
+				for i := 0; i < 2; i++ {
+					if _, err := c.Get(failing); err != nil {
+						t.Errorf("expected the circuit to close, got: %v", err)
+					}
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var failingStatus, failingHits, healthyStatus, healthyHits atomic.Int64
+			failingStatus.Store(http.StatusInternalServerError)
+			healthyStatus.Store(http.StatusOK)
+
+			failing := newServer(&failingStatus, &failingHits)
+			defer failing.Close()
+			healthy := newServer(&healthyStatus, &healthyHits)
+			defer healthy.Close()
+
+			c := clink.NewClient(clink.WithCircuitBreaker(tc.breaker))
+
+			tc.resultFunc(t, c, failing.URL, healthy.URL, &failingHits, &failingStatus)
+		})
+	}
+}
+
+func TestWithCircuitBreaker_Event(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	var opened []clink.CircuitOpened
+	c := clink.NewClient(
+		clink.WithCircuitBreaker(clink.CircuitBreaker{FailureThreshold: 1, OpenTimeout: time.Minute}),
+		clink.WithEventHandler(clink.OnEvent(func(e clink.CircuitOpened) {
+			opened = append(opened, e)
+		})),
+	)
+
+	_, _ = c.Get(server.URL)
+
+	if len(opened) != 1 || opened[0].Key != server.Listener.Addr().String() || opened[0].Timeout != time.Minute {
+		t.Errorf("expected a CircuitOpened event for the host, got: %+v", opened)
+	}
+}
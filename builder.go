@@ -0,0 +1,131 @@
+package clink
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// ClientBuilder builds a client with chained setters, an alternative to passing options to
+// NewClient that reads well when the configuration depends on conditions. Each setter applies the
+// option of the same name; options without a setter are added with Option.
+type ClientBuilder struct {
+	opts []Option
+}
+
+// Builder returns a builder of a client.
+//
+//	client, err := clink.Builder().
+//		BaseURL("https://api.example.com").
+//		Timeout(10 * time.Second).
+//		Build()
+func Builder() *ClientBuilder {
+	return &ClientBuilder{}
+}
+
+// Build creates the client with the options set on the builder, in the order they were set. Like
+// NewClientE, it returns an error wrapping ErrInvalidOption if an option is invalid.
+func (b *ClientBuilder) Build() (*Client, error) {
+	return NewClientE(b.opts...)
+}
+
+// Option adds options to the builder.
+func (b *ClientBuilder) Option(opts ...Option) *ClientBuilder {
+	b.opts = append(b.opts, opts...)
+	return b
+}
+
+// Client sets the http client (see WithClient).
+func (b *ClientBuilder) Client(client *http.Client) *ClientBuilder {
+	return b.Option(WithClient(client))
+}
+
+// Transport sets the transport (see WithTransport).
+func (b *ClientBuilder) Transport(transport http.RoundTripper) *ClientBuilder {
+	return b.Option(WithTransport(transport))
+}
+
+// BaseURL sets the base URL (see WithBaseURL).
+func (b *ClientBuilder) BaseURL(base string) *ClientBuilder {
+	return b.Option(WithBaseURL(base))
+}
+
+// Header sets a header (see WithHeader).
+func (b *ClientBuilder) Header(key, value string) *ClientBuilder {
+	return b.Option(WithHeader(key, value))
+}
+
+// Headers sets headers (see WithHeaders).
+func (b *ClientBuilder) Headers(headers map[string]string) *ClientBuilder {
+	return b.Option(WithHeaders(headers))
+}
+
+// QueryParam sets a query parameter (see WithQueryParam).
+func (b *ClientBuilder) QueryParam(key, value string) *ClientBuilder {
+	return b.Option(WithQueryParam(key, value))
+}
+
+// UserAgent sets the user agent (see WithUserAgent).
+func (b *ClientBuilder) UserAgent(ua string) *ClientBuilder {
+	return b.Option(WithUserAgent(ua))
+}
+
+// BasicAuth sets the basic auth header (see WithBasicAuth).
+func (b *ClientBuilder) BasicAuth(username, password string) *ClientBuilder {
+	return b.Option(WithBasicAuth(username, password))
+}
+
+// BearerAuth sets the bearer auth header (see WithBearerAuth).
+func (b *ClientBuilder) BearerAuth(token string) *ClientBuilder {
+	return b.Option(WithBearerAuth(token))
+}
+
+// Timeout sets the timeout of requests (see WithTimeout).
+func (b *ClientBuilder) Timeout(timeout time.Duration) *ClientBuilder {
+	return b.Option(WithTimeout(timeout))
+}
+
+// Retries sets the retry count and function (see WithRetries).
+func (b *ClientBuilder) Retries(count int, retryFunc func(*http.Request, *http.Response, error) bool) *ClientBuilder {
+	return b.Option(WithRetries(count, retryFunc))
+}
+
+// RateLimit sets the rate limit in requests per minute (see WithRateLimit).
+func (b *ClientBuilder) RateLimit(rpm int) *ClientBuilder {
+	return b.Option(WithRateLimit(rpm))
+}
+
+// MaxConcurrency limits the requests in flight (see WithMaxConcurrency).
+func (b *ClientBuilder) MaxConcurrency(n int) *ClientBuilder {
+	return b.Option(WithMaxConcurrency(n))
+}
+
+// CircuitBreaker sets the circuit breaker (see WithCircuitBreaker).
+func (b *ClientBuilder) CircuitBreaker(breaker CircuitBreaker) *ClientBuilder {
+	return b.Option(WithCircuitBreaker(breaker))
+}
+
+// ErrorOnStatus returns errors for failure responses (see WithErrorOnStatus).
+func (b *ClientBuilder) ErrorOnStatus(predicate func(*http.Response) bool) *ClientBuilder {
+	return b.Option(WithErrorOnStatus(predicate))
+}
+
+// Logger sets the logger (see WithLogger).
+func (b *ClientBuilder) Logger(logger *slog.Logger) *ClientBuilder {
+	return b.Option(WithLogger(logger))
+}
+
+// Metrics sets the metrics collector (see WithMetrics).
+func (b *ClientBuilder) Metrics(metrics Metrics) *ClientBuilder {
+	return b.Option(WithMetrics(metrics))
+}
+
+// Cache sets the cache store (see WithCache).
+func (b *ClientBuilder) Cache(store CacheStore) *ClientBuilder {
+	return b.Option(WithCache(store))
+}
+
+// Config adds the options of the config (see Config.Options).
+func (b *ClientBuilder) Config(cfg Config) *ClientBuilder {
+	return b.Option(cfg.Options()...)
+}
@@ -0,0 +1,79 @@
+package clink_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/davesavic/clink"
+)
+
+func TestBuilder(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.Header.Get("Authorization") + "|" + r.URL.Query().Get("tenant")))
+	}))
+	defer server.Close()
+
+	testCases := []struct {
+		name       string
+		builder    func() *clink.ClientBuilder
+		resultFunc func(*clink.Client, error) bool
+	}{
+		{
+			name: "chained setters",
+			builder: func() *clink.ClientBuilder {
+				return clink.Builder().
+					BaseURL(server.URL).
+					BearerAuth("token").
+					QueryParam("tenant", "acme").
+					Timeout(time.Second)
+			},
+			resultFunc: func(c *clink.Client, err error) bool {
+				if err != nil {
+					return false
+				}
+				resp, err := doWrapped(c, "/")
+				return err == nil && resp.String() == "Bearer token|acme"
+			},
+		},
+		{
+			name: "conditional configuration",
+			builder: func() *clink.ClientBuilder {
+				b := clink.Builder().BaseURL(server.URL)
+				if authenticated := false; authenticated {
+					b.BearerAuth("token")
+				} else {
+					b.Option(clink.WithQueryParam("tenant", "public"))
+				}
+				return b
+			},
+			resultFunc: func(c *clink.Client, err error) bool {
+				if err != nil {
+					return false
+				}
+				resp, err := doWrapped(c, "/")
+				return err == nil && resp.String() == "|public"
+			},
+		},
+		{
+			name: "invalid option",
+			builder: func() *clink.ClientBuilder {
+				return clink.Builder().RateLimit(0)
+			},
+			resultFunc: func(c *clink.Client, err error) bool {
+				return c == nil && errors.Is(err, clink.ErrInvalidOption)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, err := tc.builder().Build()
+			if !tc.resultFunc(c, err) {
+				t.Errorf("unexpected result: %v", err)
+			}
+		})
+	}
+}
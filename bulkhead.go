@@ -0,0 +1,83 @@
+package clink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+)
+
+// ErrBulkheadFull is returned when a request is rejected because its bulkhead has no free slot and
+// its queue of waiting requests is full.
+var ErrBulkheadFull = errors.New("bulkhead is full")
+
+// WithBulkhead adds a named bulkhead, an isolated pool allowing at most maxConcurrent requests in
+// flight and maxQueued more waiting for a slot. Requests are assigned to a bulkhead with the Tags
+// request option, so that an overloaded dependency can't use up the concurrency of the requests to
+// other dependencies. Requests beyond the queue limit fail with ErrBulkheadFull.
+func WithBulkhead(name string, maxConcurrent, maxQueued int) Option {
+	return func(c *Client) {
+		if maxConcurrent <= 0 {
+			c.addConfigError("WithBulkhead: max concurrent requests of %q must be positive", name)
+			return
+		}
+
+		if c.bulkheads == nil {
+			c.bulkheads = make(map[string]*bulkhead)
+		}
+		c.bulkheads[name] = &bulkhead{
+			name:      name,
+			slots:     make(chan struct{}, maxConcurrent),
+			maxQueued: int64(max(maxQueued, 0)),
+		}
+	}
+}
+
+// bulkheadFor returns the bulkhead of the request, or nil if it has none.
+func (c *Client) bulkheadFor(ctx context.Context) *bulkhead {
+	if len(c.bulkheads) == 0 {
+		return nil
+	}
+
+	for _, tag := range requestOptionsFrom(ctx).tags {
+		if b, ok := c.bulkheads[tag]; ok {
+			return b
+		}
+	}
+
+	return nil
+}
+
+type bulkhead struct {
+	name      string
+	slots     chan struct{}
+	maxQueued int64
+	queued    atomic.Int64
+}
+
+// acquire takes a slot of the bulkhead, waiting in its queue if there is room, and returns the
+// function releasing it.
+func (b *bulkhead) acquire(ctx context.Context) (func(), error) {
+	select {
+	case b.slots <- struct{}{}:
+		return b.release, nil
+	default:
+	}
+
+	if b.queued.Add(1) > b.maxQueued {
+		b.queued.Add(-1)
+		return nil, fmt.Errorf("failed to acquire bulkhead %q: %w", b.name, ErrBulkheadFull)
+	}
+	defer b.queued.Add(-1)
+
+	select {
+	case b.slots <- struct{}{}:
+		return b.release, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (b *bulkhead) release() {
+	<-b.slots
+}
@@ -0,0 +1,79 @@
+package clink_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/davesavic/clink"
+)
+
+func TestWithBulkhead(t *testing.T) {
+	unblock := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/search" {
+			select {
+			case <-unblock:
+			case <-r.Context().Done():
+			}
+		}
+	}))
+	defer server.Close()
+
+	c := clink.NewClient(clink.WithBulkhead("search", 1, 1), clink.WithBulkhead("billing", 1, 0))
+
+	newRequest := func(path, tag string) *http.Request {
+		req, _ := http.NewRequest(http.MethodGet, server.URL+path, nil)
+		return clink.ConfigureRequest(req, clink.Tags(tag))
+	}
+
+	// One search request is in flight and one waits in the queue.
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := c.Do(newRequest("/search", "search"))
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+			_ = resp.Body.Close()
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	_, err := c.Do(newRequest("/search", "search"))
+	if !errors.Is(err, clink.ErrBulkheadFull) {
+		t.Errorf("expected ErrBulkheadFull when the queue is full, got: %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		resp, err := c.Do(newRequest("/billing", "billing"))
+		if err != nil {
+			t.Fatalf("expected the billing bulkhead to be unaffected, got: %v", err)
+		}
+		_ = resp.Body.Close()
+	}
+
+	resp, err := c.Do(newRequest("/other", "untagged"))
+	if err != nil {
+		t.Fatalf("expected requests without a bulkhead to be unaffected, got: %v", err)
+	}
+	_ = resp.Body.Close()
+
+	close(unblock)
+	wg.Wait()
+}
+
+func TestWithBulkhead_InvalidLimit(t *testing.T) {
+	c := clink.NewClient(clink.WithBulkhead("search", 0, 10))
+
+	_, err := c.Get("http://example.com")
+	if !errors.Is(err, clink.ErrInvalidOption) {
+		t.Errorf("expected ErrInvalidOption, got: %v", err)
+	}
+}
@@ -0,0 +1,510 @@
+package clink
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// CacheStore stores cached responses. Implementations must be safe for concurrent use,
+// and can be backed by memory, Redis or any other store shared between replicas.
+// Cache errors never fail requests: a failing Get is treated as a cache miss.
+type CacheStore interface {
+	// Get returns the value stored for the key and whether it was found.
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	// Set stores the value for the key. The value should be evicted after ttl, or kept
+	// until deleted if ttl is zero.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// Delete removes the value stored for the key.
+	Delete(ctx context.Context, key string) error
+}
+
+// MemoryCache is an unbounded in-memory CacheStore.
+type MemoryCache struct {
+	mu      sync.RWMutex
+	entries map[string]memoryCacheEntry
+	clock   Clock
+}
+
+type memoryCacheEntry struct {
+	value     []byte
+	expiresAt time.Time
+}
+
+// NewMemoryCache creates a new in-memory cache store.
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{entries: make(map[string]memoryCacheEntry), clock: realClock{}}
+}
+
+func (m *MemoryCache) Get(_ context.Context, key string) ([]byte, bool, error) {
+	m.mu.RLock()
+	entry, ok := m.entries[key]
+	now := m.clock.Now()
+	m.mu.RUnlock()
+
+	if !ok {
+		return nil, false, nil
+	}
+
+	if !entry.expiresAt.IsZero() && now.After(entry.expiresAt) {
+		m.mu.Lock()
+		delete(m.entries, key)
+		m.mu.Unlock()
+		return nil, false, nil
+	}
+
+	return entry.value, true, nil
+}
+
+func (m *MemoryCache) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry := memoryCacheEntry{value: value}
+	if ttl > 0 {
+		entry.expiresAt = m.clock.Now().Add(ttl)
+	}
+
+	m.entries[key] = entry
+	return nil
+}
+
+func (m *MemoryCache) useClock(clock Clock) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.clock = clock
+}
+
+func (m *MemoryCache) Delete(_ context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.entries, key)
+	return nil
+}
+
+// WithCache enables HTTP caching of GET and HEAD responses in the given store.
+// Responses are cached according to standard HTTP caching semantics: they are stored only when they
+// carry explicit freshness information (Cache-Control max-age or Expires) or validators (ETag or
+// Last-Modified) and are not marked no-store, cached variants are selected using the Vary header,
+// and successful unsafe requests (POST, PUT, ...) invalidate the cached responses for their URL.
+// Stale responses with validators are revalidated with If-None-Match and If-Modified-Since,
+// and served from the cache when the server replies with 304 Not Modified.
+// The store is treated as a cache shared between users: responses marked private, and responses
+// to requests with an Authorization header that don't allow it with public, s-maxage or
+// must-revalidate, are not stored. Cached responses go through the same checks as responses
+// received from the server, such as WithErrorOnStatus. Freshness is computed with the client clock
+// (see WithClock), which is also used by MemoryCache and LRUCache stores to expire values.
+func WithCache(store CacheStore) Option {
+	return func(c *Client) {
+		c.Cache = store
+	}
+}
+
+// CacheStats holds the cache counters of a client.
+type CacheStats struct {
+	// Hits is the number of responses served from the cache without contacting the server.
+	Hits int64
+	// Misses is the number of cacheable requests without a matching cached response.
+	Misses int64
+	// Stale is the number of cached responses that were too old to be served without revalidation.
+	Stale int64
+	// Revalidated is the number of stale responses served from the cache after a 304 Not Modified.
+	Revalidated int64
+}
+
+type cacheCounters struct {
+	hits, misses, stale, revalidated atomic.Int64
+}
+
+// CacheStats returns the cache counters of the client.
+func (c *Client) CacheStats() CacheStats {
+	return CacheStats{
+		Hits:        c.cacheStats.hits.Load(),
+		Misses:      c.cacheStats.misses.Load(),
+		Stale:       c.cacheStats.stale.Load(),
+		Revalidated: c.cacheStats.revalidated.Load(),
+	}
+}
+
+// staleCacheRetention is how long entries with validators are kept after they become stale,
+// so that they can still be revalidated.
+const staleCacheRetention = 24 * time.Hour
+
+// cacheableStatuses are the status codes that can be cached when the response has explicit freshness.
+var cacheableStatuses = map[int]bool{
+	http.StatusOK:                   true,
+	http.StatusNonAuthoritativeInfo: true,
+	http.StatusNoContent:            true,
+	http.StatusMultipleChoices:      true,
+	http.StatusMovedPermanently:     true,
+	http.StatusNotFound:             true,
+	http.StatusMethodNotAllowed:     true,
+	http.StatusGone:                 true,
+	http.StatusRequestURITooLong:    true,
+	http.StatusNotImplemented:       true,
+}
+
+// cacheEntry is the serialised form of a cached response.
+type cacheEntry struct {
+	StatusCode int         `json:"status_code"`
+	Status     string      `json:"status"`
+	Header     http.Header `json:"header"`
+	Body       []byte      `json:"body"`
+	Vary       http.Header `json:"vary,omitempty"`
+	StoredAt   time.Time   `json:"stored_at"`
+	// TTL overrides the freshness lifetime of the response when set.
+	TTL time.Duration `json:"ttl,omitempty"`
+}
+
+func cacheKey(method, url string) string {
+	return method + " " + url
+}
+
+func isCacheableRequest(req *http.Request) bool {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		return false
+	}
+
+	_, noStore := parseCacheControl(req.Header)["no-store"]
+	return !noStore
+}
+
+// lookupCache returns the entry cached for the request to cacheURL, if any,
+// and whether it is fresh enough to be served without revalidation.
+func (c *Client) lookupCache(req *http.Request, cacheURL string) (*cacheEntry, bool) {
+	opts := requestOptionsFrom(req.Context())
+	if !isCacheableRequest(req) || opts.bypassCache {
+		return nil, false
+	}
+
+	entry := c.loadCacheEntry(req.Context(), cacheKey(req.Method, cacheURL))
+	if entry == nil || !entry.matchesVary(req) {
+		c.cacheStats.misses.Add(1)
+		return nil, false
+	}
+
+	if opts.revalidate || !entry.isFresh(c.clock.Now(), parseCacheControl(req.Header)) {
+		c.cacheStats.stale.Add(1)
+		return entry, false
+	}
+
+	c.cacheStats.hits.Add(1)
+	return entry, true
+}
+
+// cachedResponse returns the fresh cached entry as the response to the request, after checking
+// that the request is allowed to reach its destination like a request sent over the network.
+func (c *Client) cachedResponse(req *http.Request, entry *cacheEntry) (*http.Response, error) {
+	if err := c.checkDestination(req.URL); err != nil {
+		return nil, err
+	}
+
+	return entry.response(req, c.clock.Now()), nil
+}
+
+// prepareRevalidation adds conditional headers for the stale entry to the request.
+// It returns nil if the entry cannot be revalidated or the caller already sent conditional headers.
+func prepareRevalidation(req *http.Request, entry *cacheEntry) *cacheEntry {
+	if entry == nil || req.Header.Get("If-None-Match") != "" || req.Header.Get("If-Modified-Since") != "" {
+		return nil
+	}
+
+	etag := entry.Header.Get("ETag")
+	lastModified := entry.Header.Get("Last-Modified")
+	if etag == "" && lastModified == "" {
+		return nil
+	}
+
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+
+	if lastModified != "" {
+		req.Header.Set("If-Modified-Since", lastModified)
+	}
+
+	return entry
+}
+
+// finishRevalidation removes the conditional headers added by prepareRevalidation.
+func finishRevalidation(req *http.Request) {
+	req.Header.Del("If-None-Match")
+	req.Header.Del("If-Modified-Since")
+}
+
+// updateCache stores the response under cacheURL if it is cacheable, or invalidates cached responses
+// for cacheURL if the request used an unsafe method. If the response is a 304 Not Modified
+// for a revalidated entry, the entry is refreshed and returned as the response instead.
+func (c *Client) updateCache(req *http.Request, cacheURL string, resp *http.Response, revalidated *cacheEntry) (*http.Response, error) {
+	if !isSafeMethod(req.Method) && resp.StatusCode < http.StatusBadRequest {
+		_ = c.Cache.Delete(req.Context(), cacheKey(http.MethodGet, cacheURL))
+		_ = c.Cache.Delete(req.Context(), cacheKey(http.MethodHead, cacheURL))
+		return resp, nil
+	}
+
+	if revalidated != nil && resp.StatusCode == http.StatusNotModified {
+		_ = DrainAndClose(resp)
+		c.cacheStats.revalidated.Add(1)
+
+		now := c.clock.Now()
+		revalidated.refresh(resp.Header, now)
+		c.storeCacheEntry(req.Context(), cacheKey(req.Method, cacheURL), revalidated)
+
+		return revalidated.response(req, now), nil
+	}
+
+	opts := requestOptionsFrom(req.Context())
+	if !isCacheableRequest(req) || opts.bypassCache || !isStorableResponse(req, resp, opts.cacheTTL, c.clock.Now()) {
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	entry := cacheEntry{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		Header:     resp.Header.Clone(),
+		Body:       body,
+		Vary:       varyHeaders(req, resp.Header),
+		StoredAt:   c.clock.Now(),
+		TTL:        opts.cacheTTL,
+	}
+
+	c.storeCacheEntry(req.Context(), cacheKey(req.Method, cacheURL), &entry)
+
+	return resp, nil
+}
+
+func (c *Client) loadCacheEntry(ctx context.Context, key string) *cacheEntry {
+	data, ok, err := c.Cache.Get(ctx, key)
+	if err != nil || !ok {
+		return nil
+	}
+
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		_ = c.Cache.Delete(ctx, key)
+		return nil
+	}
+
+	return &entry
+}
+
+// storeCacheEntry stores the entry for as long as it is fresh, or longer if it can be revalidated.
+// Storing is best effort, errors are ignored.
+func (c *Client) storeCacheEntry(ctx context.Context, key string, entry *cacheEntry) {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+
+	ttl := entry.lifetime() - entry.age(entry.StoredAt)
+	if entry.Header.Get("ETag") != "" || entry.Header.Get("Last-Modified") != "" {
+		ttl = max(ttl, 0) + staleCacheRetention
+	}
+
+	if ttl <= 0 {
+		return
+	}
+
+	_ = c.Cache.Set(ctx, key, data, ttl)
+}
+
+// isStorableResponse reports whether the response can be stored in the cache. The store is treated
+// as a shared cache (RFC 9111 section 3.5), since it can be shared between clients and replicas:
+// responses marked private are not stored, and neither are responses to requests with an
+// Authorization header, unless the response explicitly allows it with public, s-maxage or
+// must-revalidate.
+func isStorableResponse(req *http.Request, resp *http.Response, pinnedTTL time.Duration, now time.Time) bool {
+	if !cacheableStatuses[resp.StatusCode] {
+		return false
+	}
+
+	directives := parseCacheControl(resp.Header)
+	if _, noStore := directives["no-store"]; noStore {
+		return false
+	}
+
+	if _, private := directives["private"]; private {
+		return false
+	}
+
+	if req.Header.Get("Authorization") != "" && !allowsAuthorizedStorage(directives) {
+		return false
+	}
+
+	if resp.Header.Get("Vary") == "*" {
+		return false
+	}
+
+	hasValidators := resp.Header.Get("ETag") != "" || resp.Header.Get("Last-Modified") != ""
+
+	return hasValidators || pinnedTTL > 0 || freshnessLifetime(resp.Header, now) > 0
+}
+
+// allowsAuthorizedStorage reports whether the Cache-Control directives of a response to a request
+// with an Authorization header allow a shared cache to store it.
+func allowsAuthorizedStorage(directives map[string]string) bool {
+	for _, directive := range []string{"public", "s-maxage", "must-revalidate"} {
+		if _, ok := directives[directive]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// varyHeaders returns the request header values selected by the response Vary header.
+func varyHeaders(req *http.Request, header http.Header) http.Header {
+	vary := http.Header{}
+	for _, name := range headerTokens(header, "Vary") {
+		vary[http.CanonicalHeaderKey(name)] = req.Header.Values(name)
+	}
+	return vary
+}
+
+func (e *cacheEntry) matchesVary(req *http.Request) bool {
+	for name, values := range e.Vary {
+		if strings.Join(values, ",") != strings.Join(req.Header.Values(name), ",") {
+			return false
+		}
+	}
+	return true
+}
+
+// age returns the current age of the cached response.
+func (e *cacheEntry) age(now time.Time) time.Duration {
+	age := now.Sub(e.StoredAt)
+	if seconds, err := strconv.Atoi(e.Header.Get("Age")); err == nil && seconds > 0 {
+		age += time.Duration(seconds) * time.Second
+	}
+	return age
+}
+
+func (e *cacheEntry) isFresh(now time.Time, requestCacheControl map[string]string) bool {
+	if _, noCache := requestCacheControl["no-cache"]; noCache {
+		return false
+	}
+
+	if _, noCache := parseCacheControl(e.Header)["no-cache"]; noCache && e.TTL == 0 {
+		return false
+	}
+
+	age := e.age(now)
+
+	if maxAge, ok := requestCacheControl["max-age"]; ok {
+		if seconds, err := strconv.Atoi(maxAge); err == nil && age > time.Duration(seconds)*time.Second {
+			return false
+		}
+	}
+
+	return age < e.lifetime()
+}
+
+// lifetime returns how long the entry is fresh for.
+func (e *cacheEntry) lifetime() time.Duration {
+	if e.TTL > 0 {
+		return e.TTL
+	}
+	return freshnessLifetime(e.Header, e.StoredAt)
+}
+
+// refresh updates the entry with the headers of a 304 Not Modified response.
+func (e *cacheEntry) refresh(header http.Header, now time.Time) {
+	for key, values := range header {
+		switch key {
+		case "Content-Length", "Content-Encoding", "Transfer-Encoding":
+			continue
+		}
+		e.Header[key] = values
+	}
+
+	if header.Get("Age") == "" {
+		e.Header.Del("Age")
+	}
+
+	e.StoredAt = now
+}
+
+func (e *cacheEntry) response(req *http.Request, now time.Time) *http.Response {
+	header := e.Header.Clone()
+	header.Set("Age", strconv.Itoa(int(e.age(now).Seconds())))
+
+	return &http.Response{
+		Status:        e.Status,
+		StatusCode:    e.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}
+}
+
+// freshnessLifetime returns how long a response is fresh for, based on
+// Cache-Control max-age or the Expires header.
+func freshnessLifetime(header http.Header, responseTime time.Time) time.Duration {
+	if maxAge, ok := parseCacheControl(header)["max-age"]; ok {
+		seconds, err := strconv.Atoi(maxAge)
+		if err != nil {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+
+	if expires := header.Get("Expires"); expires != "" {
+		expiresAt, err := http.ParseTime(expires)
+		if err != nil {
+			return 0
+		}
+
+		date := responseTime
+		if d, err := http.ParseTime(header.Get("Date")); err == nil {
+			date = d
+		}
+
+		return expiresAt.Sub(date)
+	}
+
+	return 0
+}
+
+// parseCacheControl parses the Cache-Control header into a map of lower-cased directives to values.
+func parseCacheControl(header http.Header) map[string]string {
+	directives := make(map[string]string)
+	for _, directive := range headerTokens(header, "Cache-Control") {
+		key, value, _ := strings.Cut(directive, "=")
+		directives[strings.ToLower(strings.TrimSpace(key))] = strings.Trim(strings.TrimSpace(value), `"`)
+	}
+	return directives
+}
+
+// headerTokens returns the comma separated, trimmed values of the header.
+func headerTokens(header http.Header, name string) []string {
+	var tokens []string
+	for _, value := range header.Values(name) {
+		for _, token := range strings.Split(value, ",") {
+			if token = strings.TrimSpace(token); token != "" {
+				tokens = append(tokens, token)
+			}
+		}
+	}
+	return tokens
+}
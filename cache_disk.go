@@ -0,0 +1,193 @@
+package clink
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// DiskCache is a CacheStore that persists cached responses on disk, so that they survive
+// process restarts. Values are stored in content-addressed files (named by their SHA-256 digest)
+// and an index file maps keys to their content and expiry time.
+type DiskCache struct {
+	dir   string
+	mu    sync.Mutex
+	clock Clock
+	index map[string]diskCacheEntry
+}
+
+type diskCacheEntry struct {
+	Digest    string    `json:"digest"`
+	ExpiresAt time.Time `json:"expires_at,omitempty"`
+}
+
+const diskCacheIndexFile = "index.json"
+
+// NewDiskCache creates a disk cache store in the given directory, loading any existing index.
+func NewDiskCache(dir string) (*DiskCache, error) {
+	if err := os.MkdirAll(filepath.Join(dir, "objects"), 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	d := &DiskCache{dir: dir, clock: realClock{}, index: make(map[string]diskCacheEntry)}
+
+	data, err := os.ReadFile(filepath.Join(dir, diskCacheIndexFile))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read cache index: %w", err)
+	}
+
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &d.index); err != nil {
+			return nil, fmt.Errorf("failed to decode cache index: %w", err)
+		}
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := d.clock.Now()
+	for key, entry := range d.index {
+		if entry.expired(now) {
+			d.removeLocked(key)
+		}
+	}
+
+	return d, nil
+}
+
+func (d *DiskCache) Get(_ context.Context, key string) ([]byte, bool, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	entry, ok := d.index[key]
+	if !ok {
+		return nil, false, nil
+	}
+
+	if entry.expired(d.clock.Now()) {
+		d.removeLocked(key)
+		return nil, false, d.saveIndexLocked()
+	}
+
+	value, err := os.ReadFile(d.objectPath(entry.Digest))
+	if errors.Is(err, fs.ErrNotExist) {
+		delete(d.index, key)
+		return nil, false, d.saveIndexLocked()
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read cached value: %w", err)
+	}
+
+	return value, true, nil
+}
+
+func (d *DiskCache) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	sum := sha256.Sum256(value)
+	digest := hex.EncodeToString(sum[:])
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if _, err := os.Stat(d.objectPath(digest)); errors.Is(err, fs.ErrNotExist) {
+		if err := writeFileAtomic(d.objectPath(digest), value); err != nil {
+			return fmt.Errorf("failed to write cached value: %w", err)
+		}
+	}
+
+	d.removeLocked(key)
+
+	entry := diskCacheEntry{Digest: digest}
+	if ttl > 0 {
+		entry.ExpiresAt = d.clock.Now().Add(ttl)
+	}
+	d.index[key] = entry
+
+	return d.saveIndexLocked()
+}
+
+func (d *DiskCache) useClock(clock Clock) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.clock = clock
+}
+
+func (d *DiskCache) Delete(_ context.Context, key string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if _, ok := d.index[key]; !ok {
+		return nil
+	}
+
+	d.removeLocked(key)
+	return d.saveIndexLocked()
+}
+
+// removeLocked removes the key from the index and deletes its content if no other key references it.
+func (d *DiskCache) removeLocked(key string) {
+	entry, ok := d.index[key]
+	if !ok {
+		return
+	}
+	delete(d.index, key)
+
+	for _, other := range d.index {
+		if other.Digest == entry.Digest {
+			return
+		}
+	}
+
+	_ = os.Remove(d.objectPath(entry.Digest))
+}
+
+func (d *DiskCache) saveIndexLocked() error {
+	data, err := json.Marshal(d.index)
+	if err != nil {
+		return fmt.Errorf("failed to encode cache index: %w", err)
+	}
+
+	if err := writeFileAtomic(filepath.Join(d.dir, diskCacheIndexFile), data); err != nil {
+		return fmt.Errorf("failed to write cache index: %w", err)
+	}
+
+	return nil
+}
+
+func (d *DiskCache) objectPath(digest string) string {
+	return filepath.Join(d.dir, "objects", digest)
+}
+
+func (e diskCacheEntry) expired(now time.Time) bool {
+	return !e.ExpiresAt.IsZero() && now.After(e.ExpiresAt)
+}
+
+// writeFileAtomic writes the data to a temporary file and renames it to path,
+// so that readers never observe a partially written file.
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,96 @@
+package clink_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/davesavic/clink"
+)
+
+func TestDiskCache(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	store, err := clink.NewDiskCache(dir)
+	if err != nil {
+		t.Fatalf("failed to create disk cache: %v", err)
+	}
+
+	_ = store.Set(ctx, "first", []byte("shared"), 0)
+	_ = store.Set(ctx, "second", []byte("shared"), 0)
+	_ = store.Set(ctx, "expiring", []byte("value"), time.Millisecond)
+
+	objects, _ := os.ReadDir(filepath.Join(dir, "objects"))
+	if len(objects) != 2 {
+		t.Errorf("expected identical values to share content, got %d objects", len(objects))
+	}
+
+	time.Sleep(5 * time.Millisecond)
+
+	reopened, err := clink.NewDiskCache(dir)
+	if err != nil {
+		t.Fatalf("failed to reopen disk cache: %v", err)
+	}
+
+	if value, ok, err := reopened.Get(ctx, "first"); err != nil || !ok || string(value) != "shared" {
+		t.Errorf("expected value to survive restart, got: %s, %v, %v", value, ok, err)
+	}
+
+	if _, ok, _ := reopened.Get(ctx, "expiring"); ok {
+		t.Errorf("expected expired value to be pruned")
+	}
+
+	_ = reopened.Delete(ctx, "first")
+	if value, ok, _ := reopened.Get(ctx, "second"); !ok || string(value) != "shared" {
+		t.Errorf("expected shared content to be kept while referenced")
+	}
+
+	_ = reopened.Delete(ctx, "second")
+	objects, _ = os.ReadDir(filepath.Join(dir, "objects"))
+	if len(objects) != 0 {
+		t.Errorf("expected unreferenced content to be removed, got %d objects", len(objects))
+	}
+}
+
+func TestDiskCache_WithClient(t *testing.T) {
+	var requestCount int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestCount++
+		w.Header().Set("Cache-Control", "max-age=60")
+		_, _ = w.Write([]byte("response"))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	for i := 0; i < 2; i++ {
+		store, err := clink.NewDiskCache(dir)
+		if err != nil {
+			t.Fatalf("failed to create disk cache: %v", err)
+		}
+
+		client := clink.NewClient(clink.WithClient(server.Client()), clink.WithCache(store))
+		resp, err := client.DoWrapped(mustRequest(t, http.MethodGet, server.URL))
+		if err != nil || resp.String() != "response" {
+			t.Fatalf("unexpected response: %v", err)
+		}
+	}
+
+	if requestCount != 1 {
+		t.Errorf("expected cached response to be reused by a new client, got %d requests", requestCount)
+	}
+}
+
+func mustRequest(t *testing.T, method, url string) *http.Request {
+	t.Helper()
+
+	req, err := http.NewRequest(method, url, nil)
+	if err != nil {
+		t.Fatalf("failed to create request: %v", err)
+	}
+	return req
+}
@@ -0,0 +1,141 @@
+package clink
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// LRUCache is a bounded in-memory CacheStore that evicts the least recently used
+// values when the maximum number of entries or total bytes is exceeded.
+type LRUCache struct {
+	maxEntries int
+	maxBytes   int64
+
+	mu    sync.Mutex
+	ll    *list.List
+	items map[string]*list.Element
+	bytes int64
+	stats LRUCacheStats
+	clock Clock
+}
+
+// LRUCacheStats holds usage statistics of an LRUCache.
+type LRUCacheStats struct {
+	Hits      int64
+	Misses    int64
+	Evictions int64
+	Entries   int
+	Bytes     int64
+}
+
+type lruCacheItem struct {
+	key       string
+	value     []byte
+	expiresAt time.Time
+}
+
+// NewLRUCache creates an LRU cache store bounded to maxEntries values and maxBytes total value size.
+// A limit of zero means no limit.
+func NewLRUCache(maxEntries int, maxBytes int64) *LRUCache {
+	return &LRUCache{
+		maxEntries: maxEntries,
+		maxBytes:   maxBytes,
+		ll:         list.New(),
+		items:      make(map[string]*list.Element),
+		clock:      realClock{},
+	}
+}
+
+// WithMemoryCache enables HTTP caching in a bounded in-memory LRU store (see WithCache and NewLRUCache).
+func WithMemoryCache(maxEntries int, maxBytes int64) Option {
+	return WithCache(NewLRUCache(maxEntries, maxBytes))
+}
+
+func (l *LRUCache) Get(_ context.Context, key string) ([]byte, bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	element, ok := l.items[key]
+	if !ok {
+		l.stats.Misses++
+		return nil, false, nil
+	}
+
+	item := element.Value.(*lruCacheItem)
+	if !item.expiresAt.IsZero() && l.clock.Now().After(item.expiresAt) {
+		l.removeLocked(element)
+		l.stats.Misses++
+		return nil, false, nil
+	}
+
+	l.ll.MoveToFront(element)
+	l.stats.Hits++
+
+	return item.value, true, nil
+}
+
+func (l *LRUCache) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if element, ok := l.items[key]; ok {
+		l.removeLocked(element)
+	}
+
+	if l.maxBytes > 0 && int64(len(value)) > l.maxBytes {
+		return nil
+	}
+
+	item := &lruCacheItem{key: key, value: value}
+	if ttl > 0 {
+		item.expiresAt = l.clock.Now().Add(ttl)
+	}
+
+	l.items[key] = l.ll.PushFront(item)
+	l.bytes += int64(len(value))
+
+	for l.maxEntries > 0 && l.ll.Len() > l.maxEntries || l.maxBytes > 0 && l.bytes > l.maxBytes {
+		l.removeLocked(l.ll.Back())
+		l.stats.Evictions++
+	}
+
+	return nil
+}
+
+func (l *LRUCache) Delete(_ context.Context, key string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if element, ok := l.items[key]; ok {
+		l.removeLocked(element)
+	}
+
+	return nil
+}
+
+func (l *LRUCache) useClock(clock Clock) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.clock = clock
+}
+
+// Stats returns the usage statistics of the cache.
+func (l *LRUCache) Stats() LRUCacheStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	stats := l.stats
+	stats.Entries = l.ll.Len()
+	stats.Bytes = l.bytes
+
+	return stats
+}
+
+func (l *LRUCache) removeLocked(element *list.Element) {
+	item := l.ll.Remove(element).(*lruCacheItem)
+	delete(l.items, item.key)
+	l.bytes -= int64(len(item.value))
+}
@@ -0,0 +1,120 @@
+package clink_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/davesavic/clink"
+)
+
+func TestLRUCache(t *testing.T) {
+	ctx := context.Background()
+
+	testCases := []struct {
+		name       string
+		cache      *clink.LRUCache
+		operations func(*clink.LRUCache)
+		resultFunc func(*clink.LRUCache) bool
+	}{
+		{
+			name:  "evicts least recently used entry",
+			cache: clink.NewLRUCache(2, 0),
+			operations: func(c *clink.LRUCache) {
+				_ = c.Set(ctx, "a", []byte("1"), 0)
+				_ = c.Set(ctx, "b", []byte("2"), 0)
+				_, _, _ = c.Get(ctx, "a")
+				_ = c.Set(ctx, "c", []byte("3"), 0)
+			},
+			resultFunc: func(c *clink.LRUCache) bool {
+				_, okA, _ := c.Get(ctx, "a")
+				_, okB, _ := c.Get(ctx, "b")
+				_, okC, _ := c.Get(ctx, "c")
+				return okA && !okB && okC && c.Stats().Evictions == 1
+			},
+		},
+		{
+			name:  "evicts entries over byte limit",
+			cache: clink.NewLRUCache(0, 10),
+			operations: func(c *clink.LRUCache) {
+				_ = c.Set(ctx, "a", []byte("12345"), 0)
+				_ = c.Set(ctx, "b", []byte("12345"), 0)
+				_ = c.Set(ctx, "c", []byte("123"), 0)
+			},
+			resultFunc: func(c *clink.LRUCache) bool {
+				stats := c.Stats()
+				return stats.Entries == 2 && stats.Bytes == 8
+			},
+		},
+		{
+			name:  "ignores values larger than byte limit",
+			cache: clink.NewLRUCache(0, 4),
+			operations: func(c *clink.LRUCache) {
+				_ = c.Set(ctx, "a", []byte("12345"), 0)
+			},
+			resultFunc: func(c *clink.LRUCache) bool {
+				return c.Stats().Entries == 0
+			},
+		},
+		{
+			name:  "expired entries are misses",
+			cache: clink.NewLRUCache(0, 0),
+			operations: func(c *clink.LRUCache) {
+				_ = c.Set(ctx, "a", []byte("1"), time.Millisecond)
+				time.Sleep(5 * time.Millisecond)
+			},
+			resultFunc: func(c *clink.LRUCache) bool {
+				_, ok, _ := c.Get(ctx, "a")
+				return !ok && c.Stats().Misses == 1 && c.Stats().Entries == 0
+			},
+		},
+		{
+			name:  "counts hits and misses",
+			cache: clink.NewLRUCache(0, 0),
+			operations: func(c *clink.LRUCache) {
+				_ = c.Set(ctx, "a", []byte("1"), 0)
+				_, _, _ = c.Get(ctx, "a")
+				_, _, _ = c.Get(ctx, "a")
+				_, _, _ = c.Get(ctx, "b")
+				_ = c.Delete(ctx, "a")
+			},
+			resultFunc: func(c *clink.LRUCache) bool {
+				stats := c.Stats()
+				return stats.Hits == 2 && stats.Misses == 1 && stats.Entries == 0 && stats.Bytes == 0
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.operations(tc.cache)
+			if !tc.resultFunc(tc.cache) {
+				t.Errorf("unexpected cache state: %+v", tc.cache.Stats())
+			}
+		})
+	}
+}
+
+func TestWithMemoryCache(t *testing.T) {
+	var requestCount int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestCount++
+		w.Header().Set("Cache-Control", "max-age=60")
+	}))
+	defer server.Close()
+
+	client := clink.NewClient(clink.WithClient(server.Client()), clink.WithMemoryCache(100, 1<<20))
+
+	for i := 0; i < 3; i++ {
+		if _, err := client.Get(server.URL); err != nil {
+			t.Fatalf("failed to make request: %v", err)
+		}
+	}
+
+	stats := client.Cache.(*clink.LRUCache).Stats()
+	if requestCount != 1 || stats.Hits != 2 {
+		t.Errorf("expected 1 request and 2 cache hits, got: %d, %+v", requestCount, stats)
+	}
+}
@@ -0,0 +1,534 @@
+package clink_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/davesavic/clink"
+)
+
+func TestCache(t *testing.T) {
+	get := func(client *clink.Client, url string, header http.Header) string {
+		req, _ := http.NewRequest(http.MethodGet, url, nil)
+		for key, values := range header {
+			req.Header[key] = values
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("failed to make request: %v", err)
+		}
+
+		body, _ := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		return string(body)
+	}
+
+	testCases := []struct {
+		name     string
+		header   func(http.Header)
+		requests func(client *clink.Client, url string)
+		expected int
+	}{
+		{
+			name: "fresh response is served from cache",
+			header: func(h http.Header) {
+				h.Set("Cache-Control", "max-age=60")
+			},
+			requests: func(client *clink.Client, url string) {
+				if get(client, url, nil) != "response" || get(client, url, nil) != "response" {
+					t.Errorf("expected cached body to match")
+				}
+			},
+			expected: 1,
+		},
+		{
+			name: "expires header is honored",
+			header: func(h http.Header) {
+				h.Set("Expires", time.Now().Add(time.Minute).UTC().Format(http.TimeFormat))
+			},
+			requests: func(client *clink.Client, url string) {
+				get(client, url, nil)
+				get(client, url, nil)
+			},
+			expected: 1,
+		},
+		{
+			name: "no-store response is not cached",
+			header: func(h http.Header) {
+				h.Set("Cache-Control", "no-store, max-age=60")
+			},
+			requests: func(client *clink.Client, url string) {
+				get(client, url, nil)
+				get(client, url, nil)
+			},
+			expected: 2,
+		},
+		{
+			name:   "response without freshness is not cached",
+			header: func(h http.Header) {},
+			requests: func(client *clink.Client, url string) {
+				get(client, url, nil)
+				get(client, url, nil)
+			},
+			expected: 2,
+		},
+		{
+			name: "request no-cache bypasses cache",
+			header: func(h http.Header) {
+				h.Set("Cache-Control", "max-age=60")
+			},
+			requests: func(client *clink.Client, url string) {
+				get(client, url, nil)
+				get(client, url, http.Header{"Cache-Control": {"no-cache"}})
+			},
+			expected: 2,
+		},
+		{
+			name: "vary selects variants",
+			header: func(h http.Header) {
+				h.Set("Cache-Control", "max-age=60")
+				h.Set("Vary", "Accept")
+			},
+			requests: func(client *clink.Client, url string) {
+				get(client, url, http.Header{"Accept": {"application/json"}})
+				get(client, url, http.Header{"Accept": {"application/json"}})
+				get(client, url, http.Header{"Accept": {"text/plain"}})
+			},
+			expected: 2,
+		},
+		{
+			name: "unsafe request invalidates cache",
+			header: func(h http.Header) {
+				h.Set("Cache-Control", "max-age=60")
+			},
+			requests: func(client *clink.Client, url string) {
+				get(client, url, nil)
+				if _, err := client.Post(url, nil); err != nil {
+					t.Fatalf("failed to make request: %v", err)
+				}
+				get(client, url, nil)
+			},
+			expected: 3,
+		},
+		{
+			name: "private response is not cached",
+			header: func(h http.Header) {
+				h.Set("Cache-Control", "private, max-age=60")
+			},
+			requests: func(client *clink.Client, url string) {
+				get(client, url, nil)
+				get(client, url, nil)
+			},
+			expected: 2,
+		},
+		{
+			name: "authorized response is not cached",
+			header: func(h http.Header) {
+				h.Set("Cache-Control", "max-age=60")
+			},
+			requests: func(client *clink.Client, url string) {
+				get(client, url, http.Header{"Authorization": {"Bearer a"}})
+				get(client, url, http.Header{"Authorization": {"Bearer b"}})
+			},
+			expected: 2,
+		},
+		{
+			name: "public authorized response is cached",
+			header: func(h http.Header) {
+				h.Set("Cache-Control", "public, max-age=60")
+			},
+			requests: func(client *clink.Client, url string) {
+				get(client, url, http.Header{"Authorization": {"Bearer a"}})
+				get(client, url, http.Header{"Authorization": {"Bearer a"}})
+			},
+			expected: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var requestCount int
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requestCount++
+				tc.header(w.Header())
+				_, _ = w.Write([]byte("response"))
+			}))
+			defer server.Close()
+
+			client := clink.NewClient(clink.WithClient(server.Client()), clink.WithCache(clink.NewMemoryCache()))
+
+			tc.requests(client, server.URL)
+
+			if requestCount != tc.expected {
+				t.Errorf("expected %d requests to reach the server, got: %d", tc.expected, requestCount)
+			}
+		})
+	}
+}
+
+func TestCache_AgeHeader(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "max-age=60")
+		w.Header().Set("Age", "10")
+	}))
+	defer server.Close()
+
+	client := clink.NewClient(clink.WithClient(server.Client()), clink.WithCache(clink.NewMemoryCache()))
+
+	if _, err := client.Get(server.URL); err != nil {
+		t.Fatalf("failed to make request: %v", err)
+	}
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("failed to make request: %v", err)
+	}
+
+	if resp.Header.Get("Age") != "10" {
+		t.Errorf("expected cached response age to be 10, got: %s", resp.Header.Get("Age"))
+	}
+}
+
+func TestCache_Revalidation(t *testing.T) {
+	testCases := []struct {
+		name      string
+		validator func(w http.ResponseWriter, r *http.Request) bool
+	}{
+		{
+			name: "etag",
+			validator: func(w http.ResponseWriter, r *http.Request) bool {
+				w.Header().Set("ETag", `"v1"`)
+				return r.Header.Get("If-None-Match") == `"v1"`
+			},
+		},
+		{
+			name: "last modified",
+			validator: func(w http.ResponseWriter, r *http.Request) bool {
+				lastModified := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).Format(http.TimeFormat)
+				w.Header().Set("Last-Modified", lastModified)
+				return r.Header.Get("If-Modified-Since") == lastModified
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var fullResponses, notModified int
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Cache-Control", "no-cache")
+				if tc.validator(w, r) {
+					notModified++
+					w.Header().Set("X-Revalidated", "true")
+					w.WriteHeader(http.StatusNotModified)
+					return
+				}
+				fullResponses++
+				_, _ = w.Write([]byte("response"))
+			}))
+			defer server.Close()
+
+			client := clink.NewClient(clink.WithClient(server.Client()), clink.WithCache(clink.NewMemoryCache()))
+
+			for i := 0; i < 3; i++ {
+				resp, err := client.Get(server.URL)
+				if err != nil {
+					t.Fatalf("failed to make request: %v", err)
+				}
+
+				body, _ := io.ReadAll(resp.Body)
+				if resp.StatusCode != http.StatusOK || string(body) != "response" {
+					t.Errorf("expected cached response to be served, got: %d %s", resp.StatusCode, body)
+				}
+
+				if i > 0 && resp.Header.Get("X-Revalidated") != "true" {
+					t.Errorf("expected cached headers to be refreshed")
+				}
+
+				if resp.Request.Header.Get("If-None-Match") != "" || resp.Request.Header.Get("If-Modified-Since") != "" {
+					t.Errorf("expected conditional headers to be removed from the request")
+				}
+			}
+
+			if fullResponses != 1 || notModified != 2 {
+				t.Errorf("expected 1 full response and 2 revalidations, got: %d, %d", fullResponses, notModified)
+			}
+		})
+	}
+}
+
+func TestCache_CallerConditionalRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"v1"`)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		_, _ = w.Write([]byte("response"))
+	}))
+	defer server.Close()
+
+	client := clink.NewClient(clink.WithClient(server.Client()), clink.WithCache(clink.NewMemoryCache()))
+
+	if _, err := client.Get(server.URL); err != nil {
+		t.Fatalf("failed to make request: %v", err)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	req.Header.Set("If-None-Match", `"v1"`)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("failed to make request: %v", err)
+	}
+
+	if resp.StatusCode != http.StatusNotModified {
+		t.Errorf("expected caller conditional request to receive 304, got: %d", resp.StatusCode)
+	}
+}
+
+// recordingStore is a CacheStore that records the TTL of stored values.
+type recordingStore struct {
+	*clink.MemoryCache
+	ttls map[string]time.Duration
+}
+
+func (s *recordingStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	s.ttls[key] = ttl
+	return s.MemoryCache.Set(ctx, key, value, ttl)
+}
+
+func TestCache_StoreTTL(t *testing.T) {
+	testCases := []struct {
+		name     string
+		header   map[string]string
+		expected time.Duration
+	}{
+		{
+			name:     "fresh response is stored for its lifetime",
+			header:   map[string]string{"Cache-Control": "max-age=60", "Age": "10"},
+			expected: 50 * time.Second,
+		},
+		{
+			name:     "response with validators is kept for revalidation",
+			header:   map[string]string{"Cache-Control": "max-age=60", "ETag": `"v1"`},
+			expected: 60*time.Second + 24*time.Hour,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				for key, value := range tc.header {
+					w.Header().Set(key, value)
+				}
+			}))
+			defer server.Close()
+
+			store := &recordingStore{MemoryCache: clink.NewMemoryCache(), ttls: map[string]time.Duration{}}
+			client := clink.NewClient(clink.WithClient(server.Client()), clink.WithCache(store))
+
+			if _, err := client.Get(server.URL); err != nil {
+				t.Fatalf("failed to make request: %v", err)
+			}
+
+			ttl, ok := store.ttls[http.MethodGet+" "+server.URL]
+			if !ok || ttl != tc.expected {
+				t.Errorf("expected ttl %s, got: %s", tc.expected, ttl)
+			}
+		})
+	}
+}
+
+// failingStore is a CacheStore whose operations always fail.
+type failingStore struct{}
+
+func (failingStore) Get(context.Context, string) ([]byte, bool, error) {
+	return nil, false, errors.New("store unavailable")
+}
+
+func (failingStore) Set(context.Context, string, []byte, time.Duration) error {
+	return errors.New("store unavailable")
+}
+
+func (failingStore) Delete(context.Context, string) error {
+	return errors.New("store unavailable")
+}
+
+func TestCache_StoreErrorsDoNotFailRequests(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "max-age=60")
+	}))
+	defer server.Close()
+
+	client := clink.NewClient(clink.WithClient(server.Client()), clink.WithCache(failingStore{}))
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.Get(server.URL); err != nil {
+			t.Fatalf("expected cache errors to be ignored, got: %v", err)
+		}
+	}
+}
+
+func TestMemoryCache(t *testing.T) {
+	ctx := context.Background()
+	store := clink.NewMemoryCache()
+
+	_ = store.Set(ctx, "persistent", []byte("value"), 0)
+	_ = store.Set(ctx, "expiring", []byte("value"), time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+
+	if value, ok, _ := store.Get(ctx, "persistent"); !ok || string(value) != "value" {
+		t.Errorf("expected value without ttl to be kept")
+	}
+
+	if _, ok, _ := store.Get(ctx, "expiring"); ok {
+		t.Errorf("expected value to expire")
+	}
+
+	_ = store.Delete(ctx, "persistent")
+	if _, ok, _ := store.Get(ctx, "persistent"); ok {
+		t.Errorf("expected value to be deleted")
+	}
+}
+
+func TestCache_RequestOptions(t *testing.T) {
+	testCases := []struct {
+		name     string
+		header   string
+		opts     [][]clink.RequestOption
+		expected int
+		stats    clink.CacheStats
+	}{
+		{
+			name:     "bypass cache",
+			header:   "max-age=60",
+			opts:     [][]clink.RequestOption{nil, {clink.BypassCache()}, nil},
+			expected: 2,
+			stats:    clink.CacheStats{Hits: 1, Misses: 1},
+		},
+		{
+			name:     "force revalidation",
+			header:   "max-age=60",
+			opts:     [][]clink.RequestOption{nil, {clink.Revalidate()}, nil},
+			expected: 2,
+			stats:    clink.CacheStats{Hits: 1, Misses: 1, Stale: 1, Revalidated: 1},
+		},
+		{
+			name:     "pinned ttl",
+			header:   "no-cache",
+			opts:     [][]clink.RequestOption{{clink.CacheTTL(time.Minute)}, nil, nil},
+			expected: 1,
+			stats:    clink.CacheStats{Hits: 2, Misses: 1},
+		},
+		{
+			name:     "pinned ttl does not override no-store",
+			header:   "no-store",
+			opts:     [][]clink.RequestOption{{clink.CacheTTL(time.Minute)}, nil},
+			expected: 2,
+			stats:    clink.CacheStats{Misses: 2},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var requestCount int
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requestCount++
+				w.Header().Set("Cache-Control", tc.header)
+				w.Header().Set("ETag", `"v1"`)
+				if r.Header.Get("If-None-Match") == `"v1"` {
+					w.WriteHeader(http.StatusNotModified)
+				}
+			}))
+			defer server.Close()
+
+			client := clink.NewClient(clink.WithClient(server.Client()), clink.WithCache(clink.NewMemoryCache()))
+
+			for _, opts := range tc.opts {
+				req := clink.ConfigureRequest(mustRequest(t, http.MethodGet, server.URL), opts...)
+				if _, err := client.Do(req); err != nil {
+					t.Fatalf("failed to make request: %v", err)
+				}
+			}
+
+			if requestCount != tc.expected {
+				t.Errorf("expected %d requests to reach the server, got: %d", tc.expected, requestCount)
+			}
+
+			if stats := client.CacheStats(); stats != tc.stats {
+				t.Errorf("expected cache stats %+v, got: %+v", tc.stats, stats)
+			}
+		})
+	}
+}
+
+func TestCache_CachedResponseChecks(t *testing.T) {
+	var requestCount atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestCount.Add(1)
+		w.Header().Set("Cache-Control", "max-age=60")
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client := clink.NewClient(
+		clink.WithClient(server.Client()),
+		clink.WithCache(clink.NewMemoryCache()),
+		clink.WithErrorOnStatus(nil),
+	)
+
+	for i := 0; i < 2; i++ {
+		var httpErr *clink.HTTPError
+		if _, err := client.Get(server.URL); !errors.As(err, &httpErr) || httpErr.StatusCode != http.StatusNotFound {
+			t.Errorf("expected request %d to fail with the status, got: %v", i+1, err)
+		}
+	}
+
+	if requestCount.Load() != 1 {
+		t.Errorf("expected the second response to be served from the cache, got %d requests", requestCount.Load())
+	}
+}
+
+func TestCache_Clock(t *testing.T) {
+	var requestCount atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestCount.Add(1)
+		w.Header().Set("Cache-Control", "max-age=60")
+	}))
+	defer server.Close()
+
+	clock := &manualClock{now: time.Now()}
+	client := clink.NewClient(
+		clink.WithClient(server.Client()),
+		clink.WithCache(clink.NewLRUCache(0, 0)),
+		clink.WithClock(clock),
+	)
+
+	get := func() {
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			t.Fatalf("failed to make request: %v", err)
+		}
+		_ = resp.Body.Close()
+	}
+
+	get()
+	clock.advance(30 * time.Second)
+	get()
+	if requestCount.Load() != 1 {
+		t.Errorf("expected a fresh response to be served from the cache")
+	}
+
+	clock.advance(time.Minute)
+	get()
+	if requestCount.Load() != 2 {
+		t.Errorf("expected the response to be stale after the clock advanced")
+	}
+}
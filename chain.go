@@ -0,0 +1,126 @@
+package clink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// StepFunc builds the request of a chain step from the response of the previous step, which is nil
+// for the first step. The request should use ctx as its context. The previous response's body is
+// closed once the step's request has been built, so it must be read (for example with
+// Response.JSON) in the StepFunc.
+type StepFunc func(ctx context.Context, prev *Response) (*http.Request, error)
+
+// ErrInvalidPollInterval is returned by Chain.Run, wrapped in a *ChainError, for a Poll step whose
+// interval isn't positive.
+var ErrInvalidPollInterval = errors.New("poll interval must be positive")
+
+// Chain runs a sequence of dependent requests, such as create, poll and fetch, where each request
+// is built from the response of the previous one. The chain stops at the first failing step.
+type Chain struct {
+	client *Client
+	steps  []chainStep
+}
+
+type chainStep struct {
+	name     string
+	build    StepFunc
+	opts     []RequestOption
+	done     func(*Response) (bool, error)
+	interval time.Duration
+}
+
+// ChainError is returned by Chain.Run when a step fails.
+type ChainError struct {
+	// Step is the name of the failed step.
+	Step string
+	Err  error
+}
+
+func (e *ChainError) Error() string {
+	return fmt.Sprintf("chain step %q failed: %v", e.Step, e.Err)
+}
+
+func (e *ChainError) Unwrap() error {
+	return e.Err
+}
+
+// Chain returns an empty chain of requests sent by the client.
+func (c *Client) Chain() *Chain {
+	return &Chain{client: c}
+}
+
+// Then adds a step sending the request built by build. The request options, such as Retries,
+// apply to the step's request only.
+func (ch *Chain) Then(name string, build StepFunc, opts ...RequestOption) *Chain {
+	ch.steps = append(ch.steps, chainStep{name: name, build: build, opts: opts})
+	return ch
+}
+
+// Poll adds a step sending the request built by build every interval until done reports true for
+// its response, or returns an error. The request is rebuilt from the previous step's response for
+// every attempt. The interval must be positive, otherwise the chain fails with
+// ErrInvalidPollInterval before sending any request.
+func (ch *Chain) Poll(name string, build StepFunc, interval time.Duration, done func(*Response) (bool, error), opts ...RequestOption) *Chain {
+	ch.steps = append(ch.steps, chainStep{name: name, build: build, opts: opts, done: done, interval: interval})
+	return ch
+}
+
+// Run runs the steps in order with the given context and returns the response of the last step,
+// whose body must be closed by the caller. If a step fails, a *ChainError is returned.
+func (ch *Chain) Run(ctx context.Context) (*Response, error) {
+	for _, step := range ch.steps {
+		if step.done != nil && step.interval <= 0 {
+			return nil, &ChainError{Step: step.name, Err: fmt.Errorf("%w, got %s", ErrInvalidPollInterval, step.interval)}
+		}
+	}
+
+	var prev *Response
+
+	for _, step := range ch.steps {
+		resp, err := ch.runStep(ctx, step, prev)
+		if prev != nil {
+			_ = prev.Body.Close()
+		}
+		if err != nil {
+			return nil, &ChainError{Step: step.name, Err: err}
+		}
+
+		prev = resp
+	}
+
+	return prev, nil
+}
+
+func (ch *Chain) runStep(ctx context.Context, step chainStep, prev *Response) (*Response, error) {
+	for {
+		req, err := step.build(ctx, prev)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build request: %w", err)
+		}
+
+		resp, err := ch.client.DoWrapped(ConfigureRequest(req, step.opts...))
+		if err != nil || step.done == nil {
+			return resp, err
+		}
+
+		done, err := step.done(resp)
+		if err != nil {
+			_ = resp.Body.Close()
+			return nil, err
+		}
+
+		if done {
+			return resp, nil
+		}
+
+		_ = DrainAndClose(resp.Response)
+
+		if err := ch.client.sleep(ctx, step.interval); err != nil {
+			return nil, err
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package clink_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/davesavic/clink"
+)
+
+type job struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+	Result string `json:"result"`
+}
+
+func newJobServer(t *testing.T, failCreate bool) (*httptest.Server, *atomic.Int64) {
+	t.Helper()
+
+	var polls, createAttempts atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/jobs":
+			if failCreate || createAttempts.Add(1) == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			_ = json.NewEncoder(w).Encode(job{ID: "42", Status: "pending"})
+		case "/jobs/42":
+			status := "pending"
+			if polls.Add(1) >= 3 {
+				status = "done"
+			}
+			_ = json.NewEncoder(w).Encode(job{ID: "42", Status: status})
+		case "/jobs/42/result":
+			_ = json.NewEncoder(w).Encode(job{ID: "42", Result: "ok"})
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &polls
+}
+
+func TestChain(t *testing.T) {
+	retryUnavailable := func(_ *http.Request, resp *http.Response, _ error) bool {
+		return resp != nil && resp.StatusCode == http.StatusServiceUnavailable
+	}
+
+	testCases := []struct {
+		name       string
+		failCreate bool
+		resultFunc func(t *testing.T, resp *clink.Response, err error, polls int64)
+	}{
+		{
+			name: "runs create, poll and fetch",
+			resultFunc: func(t *testing.T, resp *clink.Response, err error, polls int64) {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				defer resp.Body.Close()
+
+				var result job
+				if err := resp.JSON(&result); err != nil || result.Result != "ok" {
+					t.Errorf("unexpected result: %+v, %v", result, err)
+				}
+				if polls != 3 {
+					t.Errorf("expected 3 polls, got: %d", polls)
+				}
+			},
+		},
+		{
+			name:       "aborts on failed step",
+			failCreate: true,
+			resultFunc: func(t *testing.T, _ *clink.Response, err error, polls int64) {
+				var chainErr *clink.ChainError
+				if !errors.As(err, &chainErr) || chainErr.Step != "create" {
+					t.Errorf("expected create step error, got: %v", err)
+				}
+				if polls != 0 {
+					t.Errorf("expected no polls after failure, got: %d", polls)
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server, polls := newJobServer(t, tc.failCreate)
+			c := clink.NewClient(clink.WithBaseURL(server.URL), clink.WithErrorOnStatus(nil))
+
+			var created job
+			resp, err := c.Chain().
+				Then("create", func(ctx context.Context, _ *clink.Response) (*http.Request, error) {
+					return http.NewRequestWithContext(ctx, http.MethodPost, "/jobs", nil)
+				}, clink.Retries(1, retryUnavailable)).
+				Poll("poll", func(ctx context.Context, prev *clink.Response) (*http.Request, error) {
+					if created.ID == "" {
+						if err := prev.JSON(&created); err != nil {
+							return nil, err
+						}
+					}
+					return http.NewRequestWithContext(ctx, http.MethodGet, "/jobs/"+created.ID, nil)
+				}, time.Millisecond, func(resp *clink.Response) (bool, error) {
+					var status job
+					err := resp.JSON(&status)
+					return status.Status == "done", err
+				}).
+				Then("fetch", func(ctx context.Context, _ *clink.Response) (*http.Request, error) {
+					return http.NewRequestWithContext(ctx, http.MethodGet, "/jobs/"+created.ID+"/result", nil)
+				}).
+				Run(context.Background())
+
+			tc.resultFunc(t, resp, err, polls.Load())
+		})
+	}
+}
+
+func TestChain_InvalidPollInterval(t *testing.T) {
+	var requests atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		requests.Add(1)
+	}))
+	defer server.Close()
+
+	build := func(ctx context.Context, _ *clink.Response) (*http.Request, error) {
+		return http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	}
+
+	_, err := clink.NewClient().Chain().
+		Then("create", build).
+		Poll("poll", build, 0, func(*clink.Response) (bool, error) { return false, nil }).
+		Run(context.Background())
+
+	var chainErr *clink.ChainError
+	if !errors.As(err, &chainErr) || chainErr.Step != "poll" || !errors.Is(err, clink.ErrInvalidPollInterval) {
+		t.Errorf("expected invalid poll interval error, got: %v", err)
+	}
+	if n := requests.Load(); n != 0 {
+		t.Errorf("expected no requests, got: %d", n)
+	}
+}
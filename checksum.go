@@ -0,0 +1,211 @@
+package clink
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"math"
+	"net/http"
+	"strings"
+)
+
+// Checksum algorithms supported by ExpectChecksum.
+const (
+	SHA256 = "sha256"
+	SHA512 = "sha512"
+)
+
+// ErrMissingDigest is returned when a response has no supported digest header and one is required
+// by VerifyDigestHeader.
+var ErrMissingDigest = errors.New("response has no supported digest header")
+
+// ChecksumMismatchError is returned when reading the end of a response body whose digest doesn't
+// match the expected one. The digests are hex encoded.
+type ChecksumMismatchError struct {
+	Algorithm string
+	Expected  string
+	Actual    string
+}
+
+func (e *ChecksumMismatchError) Error() string {
+	return fmt.Sprintf("%s checksum mismatch: expected %s, got %s", e.Algorithm, e.Expected, e.Actual)
+}
+
+// ExpectChecksum verifies the response body against the hex encoded digest computed with the
+// algorithm (SHA256 or SHA512). The body is verified while it is read: reading its end returns a
+// *ChecksumMismatchError instead of io.EOF if the digest doesn't match.
+func ExpectChecksum(algorithm, digest string) RequestOption {
+	return func(o *requestOptions) {
+		o.checksum = &expectedChecksum{algorithm: algorithm, digest: digest}
+	}
+}
+
+// VerifyDigestHeader verifies the response body against the digest sent by the server in the
+// Content-Digest or Digest header, like ExpectChecksum. If required is true, responses without a
+// sha-256 or sha-512 digest fail with ErrMissingDigest. Bodies decompressed by the transport are not
+// verified, as their digest applies to the compressed content.
+func VerifyDigestHeader(required bool) RequestOption {
+	return func(o *requestOptions) {
+		o.digestHeader = &required
+	}
+}
+
+type expectedChecksum struct {
+	algorithm string
+	digest    string
+}
+
+// verifyChecksums wraps the response body to verify the checksums expected for the request.
+// The body is closed if an error is returned.
+func verifyChecksums(req *http.Request, resp *http.Response) error {
+	o := requestOptionsFrom(req.Context())
+
+	if o.checksum != nil {
+		expected, err := hex.DecodeString(o.checksum.digest)
+		if err != nil {
+			_ = resp.Body.Close()
+			return fmt.Errorf("failed to decode expected checksum: %w", err)
+		}
+
+		if err := wrapDigestBody(resp, o.checksum.algorithm, expected); err != nil {
+			_ = resp.Body.Close()
+			return err
+		}
+	}
+
+	if o.digestHeader != nil && !resp.Uncompressed {
+		algorithm, expected, ok := parseDigestHeader(resp.Header)
+		if !ok && *o.digestHeader {
+			_ = resp.Body.Close()
+			return ErrMissingDigest
+		}
+
+		if ok {
+			if err := wrapDigestBody(resp, algorithm, expected); err != nil {
+				_ = resp.Body.Close()
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func wrapDigestBody(resp *http.Response, algorithm string, expected []byte) error {
+	h, err := newChecksumHash(algorithm)
+	if err != nil {
+		return err
+	}
+
+	resp.Body = &digestBody{ReadCloser: resp.Body, hash: h, algorithm: algorithm, expected: expected}
+
+	return nil
+}
+
+func newChecksumHash(algorithm string) (hash.Hash, error) {
+	switch algorithm {
+	case SHA256:
+		return sha256.New(), nil
+	case SHA512:
+		return sha512.New(), nil
+	}
+
+	return nil, fmt.Errorf("unsupported checksum algorithm: %s", algorithm)
+}
+
+// verifyFileChecksum verifies the content of the file against the hex encoded digest.
+func verifyFileChecksum(file io.ReaderAt, algorithm, digest string) error {
+	expected, err := hex.DecodeString(digest)
+	if err != nil {
+		return fmt.Errorf("failed to decode expected checksum: %w", err)
+	}
+
+	return verifyFileDigest(file, algorithm, expected)
+}
+
+// verifyFileDigest verifies the content of the file against the digest, and returns a
+// *ChecksumMismatchError if it doesn't match.
+func verifyFileDigest(file io.ReaderAt, algorithm string, expected []byte) error {
+	h, err := newChecksumHash(algorithm)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(h, io.NewSectionReader(file, 0, math.MaxInt64)); err != nil {
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+
+	if actual := h.Sum(nil); !bytes.Equal(actual, expected) {
+		return &ChecksumMismatchError{Algorithm: algorithm, Expected: hex.EncodeToString(expected), Actual: hex.EncodeToString(actual)}
+	}
+
+	return nil
+}
+
+// parseDigestHeader returns the strongest supported digest of the Content-Digest (RFC 9530)
+// or Digest (RFC 3230) header.
+func parseDigestHeader(header http.Header) (string, []byte, bool) {
+	digests := make(map[string][]byte)
+
+	for _, name := range []string{"Digest", "Content-Digest"} {
+		for _, value := range header.Values(name) {
+			for _, item := range strings.Split(value, ",") {
+				key, encoded, found := strings.Cut(strings.TrimSpace(item), "=")
+				if !found {
+					continue
+				}
+
+				digest, err := base64.StdEncoding.DecodeString(strings.Trim(encoded, ":"))
+				if err != nil {
+					continue
+				}
+
+				switch strings.ToLower(key) {
+				case "sha-256":
+					digests[SHA256] = digest
+				case "sha-512":
+					digests[SHA512] = digest
+				}
+			}
+		}
+	}
+
+	for _, algorithm := range []string{SHA512, SHA256} {
+		if digest, ok := digests[algorithm]; ok {
+			return algorithm, digest, true
+		}
+	}
+
+	return "", nil, false
+}
+
+// digestBody hashes a response body as it is read and verifies the digest at its end.
+type digestBody struct {
+	io.ReadCloser
+	hash      hash.Hash
+	algorithm string
+	expected  []byte
+}
+
+func (b *digestBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.hash.Write(p[:n])
+
+	if err == io.EOF {
+		if actual := b.hash.Sum(nil); !bytes.Equal(actual, b.expected) {
+			return n, &ChecksumMismatchError{
+				Algorithm: b.algorithm,
+				Expected:  hex.EncodeToString(b.expected),
+				Actual:    hex.EncodeToString(actual),
+			}
+		}
+	}
+
+	return n, err
+}
@@ -0,0 +1,120 @@
+package clink_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/davesavic/clink"
+)
+
+func TestChecksumVerification(t *testing.T) {
+	content := []byte("downloaded content")
+	sha256Sum := sha256.Sum256(content)
+	sha512Sum := sha512.Sum512(content)
+
+	testCases := []struct {
+		name       string
+		header     map[string]string
+		opts       []clink.RequestOption
+		resultFunc func(*testing.T, int64, error)
+	}{
+		{
+			name: "expected sha256 checksum",
+			opts: []clink.RequestOption{clink.ExpectChecksum(clink.SHA256, hex.EncodeToString(sha256Sum[:]))},
+			resultFunc: func(t *testing.T, n int64, err error) {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+
+				if n != int64(len(content)) {
+					t.Errorf("expected %d bytes, got: %d", len(content), n)
+				}
+			},
+		},
+		{
+			name: "checksum mismatch",
+			opts: []clink.RequestOption{clink.ExpectChecksum(clink.SHA512, hex.EncodeToString(make([]byte, 64)))},
+			resultFunc: func(t *testing.T, _ int64, err error) {
+				var mismatch *clink.ChecksumMismatchError
+				if !errors.As(err, &mismatch) {
+					t.Fatalf("expected a *ChecksumMismatchError, got: %v", err)
+				}
+
+				if mismatch.Algorithm != clink.SHA512 || mismatch.Actual != hex.EncodeToString(sha512Sum[:]) {
+					t.Errorf("unexpected mismatch details: %+v", mismatch)
+				}
+			},
+		},
+		{
+			name: "unsupported algorithm",
+			opts: []clink.RequestOption{clink.ExpectChecksum("md5", "00")},
+			resultFunc: func(t *testing.T, _ int64, err error) {
+				if err == nil {
+					t.Error("expected an error")
+				}
+			},
+		},
+		{
+			name:   "content digest header",
+			header: map[string]string{"Content-Digest": "sha-512=:" + base64.StdEncoding.EncodeToString(sha512Sum[:]) + ":"},
+			opts:   []clink.RequestOption{clink.VerifyDigestHeader(true)},
+			resultFunc: func(t *testing.T, _ int64, err error) {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+			},
+		},
+		{
+			name:   "digest header mismatch",
+			header: map[string]string{"Digest": "SHA-256=" + base64.StdEncoding.EncodeToString(make([]byte, 32))},
+			opts:   []clink.RequestOption{clink.VerifyDigestHeader(false)},
+			resultFunc: func(t *testing.T, _ int64, err error) {
+				var mismatch *clink.ChecksumMismatchError
+				if !errors.As(err, &mismatch) || mismatch.Algorithm != clink.SHA256 {
+					t.Errorf("expected a sha256 *ChecksumMismatchError, got: %v", err)
+				}
+			},
+		},
+		{
+			name: "missing optional digest header",
+			opts: []clink.RequestOption{clink.VerifyDigestHeader(false)},
+			resultFunc: func(t *testing.T, _ int64, err error) {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+			},
+		},
+		{
+			name: "missing required digest header",
+			opts: []clink.RequestOption{clink.VerifyDigestHeader(true)},
+			resultFunc: func(t *testing.T, _ int64, err error) {
+				if !errors.Is(err, clink.ErrMissingDigest) {
+					t.Errorf("expected ErrMissingDigest, got: %v", err)
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				for key, value := range tc.header {
+					w.Header().Set(key, value)
+				}
+				_, _ = w.Write(content)
+			}))
+			defer server.Close()
+
+			n, err := clink.NewClient().GetInto(context.Background(), server.URL, io.Discard, tc.opts...)
+			tc.resultFunc(t, n, err)
+		})
+	}
+}
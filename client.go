@@ -2,26 +2,93 @@ package clink
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
-	"encoding/json"
+	"encoding/xml"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
+	"net"
 	"net/http"
+	"net/url"
+	"sync"
 	"time"
 
 	"golang.org/x/time/rate"
+	"gopkg.in/yaml.v3"
 )
 
 // Client is a wrapper around http.Client with additional functionality.
 type Client struct {
-	HttpClient      *http.Client
-	Headers         map[string]string
-	RateLimiter     *rate.Limiter
-	MaxRetries      int
-	ShouldRetryFunc func(*http.Request, *http.Response, error) bool
+	HttpClient             *http.Client
+	BaseURL                string
+	Headers                map[string]string
+	QueryParams            map[string]string
+	RateLimiter            *rate.Limiter
+	MaxRetries             int
+	ShouldRetryFunc        func(*http.Request, *http.Response, error) bool
+	IdempotencyKeyHeader   string
+	RequestIDHeader        string
+	RequestIDGenerator     func() string
+	CorrelationIDHeader    string
+	CorrelationIDExtractor func(context.Context) string
+	Codecs                 map[string]Codec
+	IsErrorStatusFunc      func(*http.Response) bool
+	ErrorDecoder           func(*http.Response) error
+	MaxResponseBytes       int64
+	MaxRequestBytes        int64
+	Cache                  CacheStore
+	Logger                 *slog.Logger
+	LogLevels              LogLevels
+	Redactor               *Redactor
+	Metrics                Metrics
+	Tracing                bool
+	TraceFunc              func(*http.Request, Timings)
+	Timeout                time.Duration
+	MaxRedirects           int
+	FollowRedirects        bool
+	CheckRedirectFunc      func(*http.Request, []*http.Request) error
+	RedirectHeaderPolicy   RedirectHeaderPolicy
+	ReferrerPolicy         ReferrerPolicy
+
+	inflight       *flightGroup
+	cacheStats     *cacheCounters
+	debug          *debugDumper
+	events         *eventBus
+	stats          *statsCounters
+	dialer         *net.Dialer
+	transport      *http.Transport
+	configErrs     []error
+	rootCAs        *rootCAs
+	unixSocket     string
+	dnsCache       *dnsCache
+	ipFamily       IPFamily
+	queue          *workQueue
+	deliveries     *deliveryQueues
+	concurrency    *concurrencyLimiter
+	bulkheads      map[string]*bulkhead
+	breaker        *circuitBreaker
+	hostPolicy     *hostPolicy
+	httpsOnly      bool
+	ssrfProtection bool
+	// transportOptions are the options that configured the client's own transport.
+	transportOptions []string
+	// redirectOptions are the options that installed the redirect hook on the http client.
+	redirectOptions []string
+	compression     *requestCompression
+	decompression   *decompression
+	redirectHook    bool
+	immutable       bool
+	frozen          *Client
+	endpoints       *endpointSet
+	health          *healthChecker
+	clock           Clock
+	insecureWarning *sync.Once
 }
 
 // NewClient creates a new client with the given options.
+// If an option is invalid, every call of the client fails with ErrInvalidOption.
 func NewClient(opts ...Option) *Client {
 	c := defaultClient()
 
@@ -29,75 +96,387 @@ func NewClient(opts ...Option) *Client {
 		opt(c)
 	}
 
+	c.bindClock()
+
+	if c.immutable {
+		c.freeze()
+	}
+
 	return c
 }
 
+// NewClientE creates a new client with the given options like NewClient, but returns an error
+// wrapping ErrInvalidOption if an option is invalid or conflicts with another.
+func NewClientE(opts ...Option) (*Client, error) {
+	c := NewClient(opts...)
+	if err := c.configError(); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
 func defaultClient() *Client {
-	return &Client{
-		HttpClient: http.DefaultClient,
-		Headers:    make(map[string]string),
+	c := &Client{
+		Headers:         make(map[string]string),
+		QueryParams:     make(map[string]string),
+		Codecs:          newCodecRegistry(JSONCodec{}, XMLCodec{}, YAMLCodec{}, FormCodec{}, TextCodec{}),
+		LogLevels:       DefaultLogLevels,
+		MaxRedirects:    DefaultMaxRedirects,
+		FollowRedirects: true,
+		cacheStats:      &cacheCounters{},
+		debug:           newDebugDumper(),
+		events:          &eventBus{},
+		stats:           &statsCounters{},
+		dialer:          newDialer(),
+		queue:           newWorkQueue(DefaultQueueWorkers, DefaultQueueSize),
+		deliveries:      &deliveryQueues{},
+		clock:           realClock{},
 	}
+
+	c.transport = newTransport(c.dialContext, &c.stats.openConns)
+	c.HttpClient = &http.Client{Transport: c.transport, CheckRedirect: c.checkRedirect}
+	c.redirectHook = true
+
+	return c
 }
 
 // Do sends the given request and returns the response.
 // If the request is rate limited, the client will wait for the rate limiter to allow the request.
 // If the request fails, the client will retry the request the number of times specified by MaxRetries.
 func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Response, nil
+}
+
+// DoWrapped sends the given request like Do and returns the response wrapped in a *Response.
+func (c *Client) DoWrapped(req *http.Request) (*Response, error) {
+	return c.do(req)
+}
+
+func (c *Client) do(req *http.Request) (*Response, error) {
+	c = c.config()
+	start := c.clock.Now()
+
+	if err := c.configError(); err != nil {
+		return nil, err
+	}
+
+	if c.insecureWarning != nil {
+		c.warnInsecureTLS()
+	}
+
+	var ref *url.URL
+	if c.endpoints != nil && c.endpoints.enabled() && !req.URL.IsAbs() {
+		u := *req.URL
+		ref = &u
+	}
+
+	req, err := c.prepareRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	req, cancel := c.withTimeout(req)
+
+	c.notifyStarted(req)
+
+	var trace *requestTrace
+	if c.Tracing {
+		req, trace = withTrace(req)
+	}
+
+	// The response is cached under the URL of the prepared request, so that it is found again when
+	// the request fails over to another endpoint.
+	cacheURL := req.URL.String()
+	var cached *cacheEntry
+	var fresh bool
+	if c.Cache != nil {
+		cached, fresh = c.lookupCache(req, cacheURL)
+	}
+
+	fetch := func() (*http.Response, int, error) {
+		if ref != nil {
+			return c.fetchEndpoints(req, ref, cacheURL, cached)
+		}
+		return c.fetch(req, cacheURL, cached)
+	}
+
+	var resp *http.Response
+	var attempts int
+	switch {
+	case fresh:
+		resp, err = c.cachedResponse(req, cached)
+		trace = nil
+	case c.inflight != nil && req.Method == http.MethodGet && !requestOptionsFrom(req.Context()).unbuffered:
+		resp, attempts, err = c.inflight.do(req, []string{c.RequestIDHeader, c.IdempotencyKeyHeader}, fetch)
+	default:
+		resp, attempts, err = fetch()
+	}
+	switch {
+	case errors.Is(err, errRetriesExhausted):
+		err = c.statusError(resp, true)
+	case err == nil:
+		err = c.checkResponse(resp)
+	}
+	if err == nil {
+		err = verifyChecksums(req, resp)
+	}
+	c.notifyFinished(req, resp, attempts, c.elapsed(start), err)
+	if err != nil {
+		if trace != nil {
+			trace.finish(req, c.TraceFunc)
+		}
+		cancel()
+		return nil, err
+	}
+
+	resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+	if !fresh {
+		resp.Body = &countingBody{ReadCloser: resp.Body, count: &c.stats.bytesReceived}
+	}
+	if trace != nil {
+		resp.Body = &tracedBody{ReadCloser: resp.Body, finish: func() { trace.finish(req, c.TraceFunc) }}
+	}
+
+	return &Response{Response: resp, duration: c.elapsed(start), attempts: attempts, trace: trace}, nil
+}
+
+// fetch sends the request, revalidating the cached entry if there is one, and updates the cache
+// entries of cacheURL.
+func (c *Client) fetch(req *http.Request, cacheURL string, cached *cacheEntry) (*http.Response, int, error) {
+	revalidated := prepareRevalidation(req, cached)
+
+	resp, attempts, err := c.send(req)
+	if revalidated != nil {
+		finishRevalidation(req)
+	}
+	if err != nil && !errors.Is(err, errRetriesExhausted) {
+		return nil, attempts, err
+	}
+	exhausted := err
+
+	if c.MaxResponseBytes > 0 {
+		if resp.ContentLength > c.MaxResponseBytes {
+			_ = resp.Body.Close()
+			return nil, attempts, &ResponseTooLargeError{Limit: c.MaxResponseBytes}
+		}
+		resp.Body = newLimitedBody(resp.Body, c.MaxResponseBytes)
+	}
+
+	if c.Cache != nil {
+		resp, err = c.updateCache(req, cacheURL, resp, revalidated)
+		if err != nil {
+			return nil, attempts, err
+		}
+	}
+
+	return resp, attempts, exhausted
+}
+
+// prepareRequest applies the client configuration (base URL, headers, query parameters, ...) to the request,
+// and returns an *InvalidHeaderError if one of its headers is invalid.
+func (c *Client) prepareRequest(req *http.Request) (*http.Request, error) {
+	if c.BaseURL != "" && !req.URL.IsAbs() {
+		u, err := resolveURL(c.BaseURL, req.URL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve request url: %w", err)
+		}
+		req.URL = u
+		req.Host = u.Host
+	}
+
 	for key, value := range c.Headers {
 		req.Header.Set(key, value)
 	}
 
+	if accept := requestOptionsFrom(req.Context()).accept; len(accept) > 0 {
+		value, err := acceptHeaderValue(accept)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Accept", value)
+	}
+
+	if len(c.QueryParams) > 0 {
+		query := req.URL.Query()
+		for key, value := range c.QueryParams {
+			if !query.Has(key) {
+				query.Set(key, value)
+			}
+		}
+		req.URL.RawQuery = query.Encode()
+	}
+
+	c.ReferrerPolicy.apply(req)
+
+	if codec, ok := c.Codecs["application/json"].(jsonFuncCodec); ok {
+		req = req.WithContext(contextWithJSONCodec(req.Context(), codec))
+	}
+
+	if c.CorrelationIDExtractor != nil && req.Header.Get(c.CorrelationIDHeader) == "" {
+		if id := c.CorrelationIDExtractor(req.Context()); id != "" {
+			req.Header.Set(c.CorrelationIDHeader, id)
+		}
+	}
+
+	if c.IdempotencyKeyHeader != "" && !isSafeMethod(req.Method) && req.Header.Get(c.IdempotencyKeyHeader) == "" {
+		// The key is set on a copy, so that a request sent again gets a new key.
+		req = req.Clone(req.Context())
+		req.Header.Set(c.IdempotencyKeyHeader, newUUID())
+	}
+
+	if c.RequestIDHeader != "" && c.RequestIDGenerator != nil {
+		id := req.Header.Get(c.RequestIDHeader)
+		if id == "" {
+			id = c.RequestIDGenerator()
+			req = req.Clone(req.Context())
+			req.Header.Set(c.RequestIDHeader, id)
+		}
+		req = req.WithContext(contextWithRequestID(req.Context(), id))
+	}
+
+	if err := checkHeaders(req.Header); err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// errRetriesExhausted is returned by send with the final response when the retries of the request
+// are exhausted on a failure status, which do turns into an *HTTPError.
+var errRetriesExhausted = errors.New("retries exhausted")
+
+// send waits for the rate limiter and sends the request, retrying it as configured.
+// It returns the final response and the number of attempts made.
+func (c *Client) send(req *http.Request) (*http.Response, int, error) {
+	if err := c.checkDestination(req.URL); err != nil {
+		return nil, 0, err
+	}
+
+	if err := c.checkProxiedDestination(req); err != nil {
+		return nil, 0, err
+	}
+
 	if c.RateLimiter != nil {
-		if err := c.RateLimiter.Wait(req.Context()); err != nil {
-			return nil, fmt.Errorf("failed to wait for rate limiter: %w", err)
+		waitStart := c.clock.Now()
+		if err := c.waitRateLimiter(req.Context()); err != nil {
+			return nil, 0, fmt.Errorf("failed to wait for rate limiter: %w", err)
 		}
+
+		c.notifyRateLimited(req, c.elapsed(waitStart))
 	}
 
 	var resp *http.Response
 	var body []byte
 	var err error
+	var attempts int
+
+	opts := requestOptionsFrom(req.Context())
+	source := opts.bodySource
+	if source != nil && req.Body != nil {
+		_ = req.Body.Close()
+	}
 
-	if req.Body != nil && req.Body != http.NoBody {
-		body, err = io.ReadAll(req.Body)
+	if source != nil {
+		if err := c.checkBodySourceSize(source); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	if source == nil && req.Body != nil && req.Body != http.NoBody {
+		body, err = c.readRequestBody(req)
 		if err != nil {
-			return nil, fmt.Errorf("failed to read request body: %w", err)
+			return nil, 0, err
 		}
+	}
 
-		err = req.Body.Close()
+	dumpBody := body
+	if c.compression != nil && len(body) > 0 {
+		req, body, err = c.compression.compress(req, body)
 		if err != nil {
-			return nil, fmt.Errorf("failed to close request body: %w", err)
+			return nil, 0, err
 		}
 	}
 
-	for attempt := 0; attempt <= c.MaxRetries; attempt++ {
+	maxRetries, shouldRetry := c.retryPolicy(req)
+	if _, ok := source.(*readerBody); ok {
+		maxRetries = 0
+	}
+
+	var exhausted bool
+	for attempt := 0; attempt <= maxRetries; attempt++ {
 		if len(body) > 0 {
 			req.Body = io.NopCloser(bytes.NewReader(body))
 		}
 
-		resp, err = c.HttpClient.Do(req)
+		if source != nil {
+			if err := c.openBody(req, source); err != nil {
+				return nil, attempts, fmt.Errorf("failed to open request body: %w", err)
+			}
+		}
+
+		if opts.progress != nil && (len(body) > 0 || source != nil) {
+			total := int64(len(body))
+			if source != nil {
+				total = -1
+			}
+			req.Body = newProgressBody(req.Body, opts.progress, total, attempts+1)
+		}
+
+		attemptStart := c.clock.Now()
+		c.debug.dumpRequest(req, dumpBody, c.Redactor)
+		resp, err = c.roundTrip(req)
+		c.debug.dumpResponse(resp, err, c.Redactor)
+		attempts++
+		c.notifyAttempt(req, attempts, len(body), resp, err, c.elapsed(attemptStart))
 
 		if req.Context().Err() != nil {
-			return nil, fmt.Errorf("request context error: %w", req.Context().Err())
+			_ = DrainAndClose(resp)
+			return nil, attempts, fmt.Errorf("request context error: %w", req.Context().Err())
 		}
 
-		if c.ShouldRetryFunc != nil && !c.ShouldRetryFunc(req, resp, err) {
+		if errors.Is(err, ErrCircuitOpen) || shouldRetry != nil && !shouldRetry(req, resp, err) {
 			break
 		}
 
-		if attempt < c.MaxRetries {
-			select {
-			case <-time.After(time.Duration(attempt) * time.Second):
-			case <-req.Context().Done():
-				return nil, req.Context().Err()
+		exhausted = attempt == maxRetries && maxRetries > 0
+
+		if attempt < maxRetries {
+			_ = DrainAndClose(resp)
+
+			delay := time.Duration(attempt) * time.Second
+			c.notifyRetry(req, attempts, resp, err, delay)
+
+			if err := c.sleep(req.Context(), delay); err != nil {
+				return nil, attempts, err
 			}
 		}
 	}
 
 	if err != nil {
-		return nil, fmt.Errorf("failed to do request: %w", err)
+		return nil, attempts, fmt.Errorf("failed to do request: %w", err)
+	}
+
+	if exhausted && isFailureStatus(resp) {
+		return resp, attempts, errRetriesExhausted
 	}
 
-	return resp, nil
+	return resp, attempts, nil
+}
+
+// retryPolicy returns the retry count and function for the request, set per request with Retries
+// or for the client with WithRetries.
+func (c *Client) retryPolicy(req *http.Request) (int, func(*http.Request, *http.Response, error) bool) {
+	if o := requestOptionsFrom(req.Context()); o.retries != nil {
+		return o.retries.count, o.retries.retryFunc
+	}
+
+	return c.MaxRetries, c.ShouldRetryFunc
 }
 
 // Head sends a HEAD request to the given URL.
@@ -165,32 +544,81 @@ func (c *Client) Delete(url string) (*http.Response, error) {
 
 type Option func(*Client)
 
-// WithClient sets the http client for the client.
+// WithClient sets the http client for the client, replacing the client's own transport and its
+// default timeouts. It can't be used after options configuring the client's own transport, such as
+// WithSSRFProtection or WithTLSConfig, or after options applied to redirects, such as
+// WithRedirectPolicy or WithHTTPSOnly, since their settings would be lost.
 func WithClient(client *http.Client) Option {
 	return func(c *Client) {
+		if client == nil {
+			c.addConfigError("WithClient: client must not be nil")
+			return
+		}
+
+		c.replaceTransport("WithClient")
+		c.replaceRedirectHook()
+
 		c.HttpClient = client
+		c.dialer = nil
+		c.transport = nil
+		c.redirectHook = false
 	}
 }
 
-// WithHeader sets a header for the client.
+// WithHeader sets a header for the client. An invalid header name or value is recorded as a
+// configuration error.
 func WithHeader(key, value string) Option {
 	return func(c *Client) {
+		if err := checkHeader(key, value); err != nil {
+			c.addConfigError("WithHeader: %w", err)
+			return
+		}
+
 		c.Headers[key] = value
 	}
 }
 
-// WithHeaders sets the headers for the client.
+// WithHeaders sets the headers for the client. An invalid header name or value is recorded as a
+// configuration error.
 func WithHeaders(headers map[string]string) Option {
 	return func(c *Client) {
 		for key, value := range headers {
+			if err := checkHeader(key, value); err != nil {
+				c.addConfigError("WithHeaders: %w", err)
+				continue
+			}
+
 			c.Headers[key] = value
 		}
 	}
 }
 
+// WithQueryParam sets a query parameter that is added to every request.
+// Query parameters already present on the request take precedence.
+func WithQueryParam(key, value string) Option {
+	return func(c *Client) {
+		c.QueryParams[key] = value
+	}
+}
+
+// WithQueryParams sets the query parameters that are added to every request.
+// Query parameters already present on the request take precedence.
+func WithQueryParams(params map[string]string) Option {
+	return func(c *Client) {
+		for key, value := range params {
+			c.QueryParams[key] = value
+		}
+	}
+}
+
 // WithRateLimit sets the rate limit for the client in requests per minute.
 func WithRateLimit(rpm int) Option {
 	return func(c *Client) {
+		if rpm <= 0 {
+			c.addConfigError("WithRateLimit: requests per minute must be positive, got %d", rpm)
+			return
+		}
+
 		interval := time.Minute / time.Duration(rpm)
 		c.RateLimiter = rate.NewLimiter(rate.Every(interval), 1)
 	}
@@ -208,6 +636,11 @@ func WithBasicAuth(username, password string) Option {
 // WithBearerAuth sets the bearer auth header for the client.
 func WithBearerAuth(token string) Option {
 	return func(c *Client) {
+		if err := checkHeader("Authorization", token); err != nil {
+			c.addConfigError("WithBearerAuth: %w", err)
+			return
+		}
+
 		c.Headers["Authorization"] = "Bearer " + token
 	}
 }
@@ -215,20 +648,75 @@ func WithBearerAuth(token string) Option {
 // WithUserAgent sets the user agent header for the client.
 func WithUserAgent(ua string) Option {
 	return func(c *Client) {
+		if err := checkHeader("User-Agent", ua); err != nil {
+			c.addConfigError("WithUserAgent: %w", err)
+			return
+		}
+
 		c.Headers["User-Agent"] = ua
 	}
 }
 
-// WithRetries sets the retry count and retry function for the client.
+// WithRetries sets the retry count and retry function for the client. When the retries are
+// exhausted on a response with a status of 400 or above, the client returns an *HTTPError.
 func WithRetries(count int, retryFunc func(*http.Request, *http.Response, error) bool) Option {
 	return func(c *Client) {
+		if count < 0 {
+			c.addConfigError("WithRetries: retry count must not be negative, got %d", count)
+			return
+		}
+
+		if count > 0 && retryFunc == nil {
+			c.addConfigError("WithRetries: retry function must not be nil")
+			return
+		}
+
 		c.MaxRetries = count
 		c.ShouldRetryFunc = retryFunc
 	}
 }
 
 // ResponseToJson decodes the response body into the target.
-func ResponseToJson[T any](response *http.Response, target *T) error {
+// DisallowUnknownFields and UseNumber can be passed to enable strict decoding.
+func ResponseToJson[T any](response *http.Response, target *T, opts ...DecodeOption) error {
+	if response == nil {
+		return fmt.Errorf("response is nil")
+	}
+
+	if response.Body == nil {
+		return fmt.Errorf("response body is nil")
+	}
+
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(response.Body)
+
+	return decodeJSON(response, response.Body, target, opts)
+}
+
+// ResponseToXml decodes the XML response body into the target.
+func ResponseToXml[T any](response *http.Response, target *T) error {
+	if response == nil {
+		return fmt.Errorf("response is nil")
+	}
+
+	if response.Body == nil {
+		return fmt.Errorf("response body is nil")
+	}
+
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(response.Body)
+
+	if err := xml.NewDecoder(response.Body).Decode(target); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return nil
+}
+
+// ResponseToYaml decodes the YAML response body into the target.
+func ResponseToYaml[T any](response *http.Response, target *T) error {
 	if response == nil {
 		return fmt.Errorf("response is nil")
 	}
@@ -241,7 +729,7 @@ func ResponseToJson[T any](response *http.Response, target *T) error {
 		_ = Body.Close()
 	}(response.Body)
 
-	if err := json.NewDecoder(response.Body).Decode(target); err != nil {
+	if err := yaml.NewDecoder(response.Body).Decode(target); err != nil {
 		return fmt.Errorf("failed to decode response: %w", err)
 	}
 
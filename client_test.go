@@ -17,6 +17,8 @@ import (
 )
 
 func TestNewClient(t *testing.T) {
+	customHTTPClient := &http.Client{}
+
 	testCases := []struct {
 		name   string
 		opts   []clink.Option
@@ -32,10 +34,10 @@ func TestNewClient(t *testing.T) {
 		{
 			name: "client with custom http client",
 			opts: []clink.Option{
-				clink.WithClient(nil),
+				clink.WithClient(customHTTPClient),
 			},
 			result: func(client *clink.Client) bool {
-				return client.HttpClient == nil
+				return client.HttpClient == customHTTPClient
 			},
 		},
 		{
@@ -56,6 +58,24 @@ func TestNewClient(t *testing.T) {
 				return client.Headers != nil && len(client.Headers) == 1
 			},
 		},
+		{
+			name: "client with custom query param",
+			opts: []clink.Option{
+				clink.WithQueryParam("key", "value"),
+			},
+			result: func(client *clink.Client) bool {
+				return client.QueryParams["key"] == "value"
+			},
+		},
+		{
+			name: "client with custom query params",
+			opts: []clink.Option{
+				clink.WithQueryParams(map[string]string{"key": "value", "other": "value"}),
+			},
+			result: func(client *clink.Client) bool {
+				return len(client.QueryParams) == 2
+			},
+		},
 		{
 			name: "client with custom rate limit",
 			opts: []clink.Option{
@@ -127,6 +147,42 @@ func TestNewClient(t *testing.T) {
 	}
 }
 
+func TestNewClientE(t *testing.T) {
+	retryAll := func(*http.Request, *http.Response, error) bool { return true }
+
+	testCases := []struct {
+		name  string
+		opts  []clink.Option
+		valid bool
+	}{
+		{name: "valid options", opts: []clink.Option{clink.WithRateLimit(60), clink.WithRetries(3, retryAll)}, valid: true},
+		{name: "no retries without retry function", opts: []clink.Option{clink.WithRetries(0, nil)}, valid: true},
+		{name: "zero rate limit", opts: []clink.Option{clink.WithRateLimit(0)}},
+		{name: "negative rate limit", opts: []clink.Option{clink.WithRateLimit(-1)}},
+		{name: "negative retries", opts: []clink.Option{clink.WithRetries(-1, retryAll)}},
+		{name: "retries without retry function", opts: []clink.Option{clink.WithRetries(3, nil)}},
+		{name: "negative timeout", opts: []clink.Option{clink.WithTimeout(-time.Second)}},
+		{name: "negative concurrency", opts: []clink.Option{clink.WithMaxConcurrency(-1)}},
+		{name: "negative redirects", opts: []clink.Option{clink.WithRedirectPolicy(-1, true)}},
+		{name: "conflicting options", opts: []clink.Option{clink.WithClient(&http.Client{}), clink.WithHTTP2(false)}},
+		{name: "nil client", opts: []clink.Option{clink.WithClient(nil), clink.WithHTTPSOnly(), clink.WithImmutable()}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client, err := clink.NewClientE(tc.opts...)
+
+			if tc.valid && (err != nil || client == nil) {
+				t.Errorf("expected a client, got error: %v", err)
+			}
+
+			if !tc.valid && (!errors.Is(err, clink.ErrInvalidOption) || client != nil) {
+				t.Errorf("expected ErrInvalidOption, got: %v", err)
+			}
+		})
+	}
+}
+
 func TestClient_Do(t *testing.T) {
 	testCases := []struct {
 		name        string
@@ -229,6 +285,22 @@ func TestClient_Do(t *testing.T) {
 				return target["key"] == "value"
 			},
 		},
+		{
+			name: "successful response with custom query params",
+			opts: []clink.Option{
+				clink.WithQueryParam("api-version", "2024-01-01"),
+			},
+			setupServer: func() *httptest.Server {
+				return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					if r.URL.Query().Get("api-version") != "2024-01-01" {
+						w.WriteHeader(http.StatusBadRequest)
+					}
+				}))
+			},
+			resultFunc: func(response *http.Response, err error) bool {
+				return err == nil && response.StatusCode == http.StatusOK
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -256,6 +328,36 @@ func TestClient_Do(t *testing.T) {
 	}
 }
 
+func TestQueryParamsMergedWithRequestQuery(t *testing.T) {
+	var query map[string][]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+	}))
+	defer server.Close()
+
+	client := clink.NewClient(
+		clink.WithClient(server.Client()),
+		clink.WithQueryParams(map[string]string{"api-version": "2024-01-01", "page": "1"}),
+	)
+
+	_, err := client.Get(server.URL + "?page=2&sort=asc")
+	if err != nil {
+		t.Fatalf("failed to make request: %v", err)
+	}
+
+	if query["api-version"][0] != "2024-01-01" {
+		t.Errorf("expected client query param to be added, got: %v", query)
+	}
+
+	if len(query["page"]) != 1 || query["page"][0] != "2" {
+		t.Errorf("expected request query param to take precedence, got: %v", query)
+	}
+
+	if query["sort"][0] != "asc" {
+		t.Errorf("expected request query param to be kept, got: %v", query)
+	}
+}
+
 func TestClient_Methods(t *testing.T) {
 	serverFunc := func() *httptest.Server {
 		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -402,6 +504,38 @@ func TestClient_ResponseToJson(t *testing.T) {
 				return er.Error() == "response body is nil"
 			},
 		},
+		{
+			name: "strict json decode error",
+			response: &http.Response{
+				Body: io.NopCloser(strings.NewReader(`{"key": "value", "unknown": "value"}`)),
+			},
+			resultFunc: func(response *http.Response, target any) bool {
+				var t struct {
+					Key string `json:"key"`
+				}
+				er := clink.ResponseToJson(response, &t, clink.DisallowUnknownFields())
+				if er == nil {
+					return false
+				}
+
+				return strings.Contains(er.Error(), "unknown field")
+			},
+		},
+		{
+			name: "json decode with numbers",
+			response: &http.Response{
+				Body: io.NopCloser(strings.NewReader(`{"key": 9007199254740993}`)),
+			},
+			resultFunc: func(response *http.Response, target any) bool {
+				var t map[string]any
+				er := clink.ResponseToJson(response, &t, clink.UseNumber())
+				if er != nil {
+					return false
+				}
+
+				return t["key"] == json.Number("9007199254740993")
+			},
+		},
 		{
 			name: "json decode error",
 			response: &http.Response{
@@ -429,6 +563,165 @@ func TestClient_ResponseToJson(t *testing.T) {
 	}
 }
 
+func TestClient_ResponseToXml(t *testing.T) {
+	type item struct {
+		Key string `xml:"key"`
+	}
+
+	testCases := []struct {
+		name       string
+		response   *http.Response
+		resultFunc func(*http.Response) bool
+	}{
+		{
+			name: "successful response with xml body",
+			response: &http.Response{
+				Body: io.NopCloser(strings.NewReader(`<item><key>value</key></item>`)),
+			},
+			resultFunc: func(response *http.Response) bool {
+				var t item
+				er := clink.ResponseToXml(response, &t)
+				if er != nil {
+					return false
+				}
+
+				return t.Key == "value"
+			},
+		},
+		{
+			name:     "response is nil",
+			response: nil,
+			resultFunc: func(response *http.Response) bool {
+				var t item
+				er := clink.ResponseToXml(response, &t)
+				if er == nil {
+					return false
+				}
+
+				return er.Error() == "response is nil"
+			},
+		},
+		{
+			name: "response body is nil",
+			response: &http.Response{
+				Body: nil,
+			},
+			resultFunc: func(response *http.Response) bool {
+				var t item
+				er := clink.ResponseToXml(response, &t)
+				if er == nil {
+					return false
+				}
+
+				return er.Error() == "response body is nil"
+			},
+		},
+		{
+			name: "xml decode error",
+			response: &http.Response{
+				Body: io.NopCloser(strings.NewReader(`<item><key>value</item>`)),
+			},
+			resultFunc: func(response *http.Response) bool {
+				var t item
+				er := clink.ResponseToXml(response, &t)
+				if er == nil {
+					return false
+				}
+
+				return strings.Contains(er.Error(), "failed to decode response")
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if !tc.resultFunc(tc.response) {
+				t.Errorf("expected result to be successful")
+			}
+		})
+	}
+}
+
+func TestClient_ResponseToYaml(t *testing.T) {
+	type item struct {
+		Key  string   `yaml:"key"`
+		List []string `yaml:"list"`
+	}
+
+	testCases := []struct {
+		name       string
+		response   *http.Response
+		resultFunc func(*http.Response) bool
+	}{
+		{
+			name: "successful response with yaml body",
+			response: &http.Response{
+				Body: io.NopCloser(strings.NewReader("key: value\nlist:\n  - a\n  - b\n")),
+			},
+			resultFunc: func(response *http.Response) bool {
+				var t item
+				er := clink.ResponseToYaml(response, &t)
+				if er != nil {
+					return false
+				}
+
+				return t.Key == "value" && len(t.List) == 2
+			},
+		},
+		{
+			name:     "response is nil",
+			response: nil,
+			resultFunc: func(response *http.Response) bool {
+				var t item
+				er := clink.ResponseToYaml(response, &t)
+				if er == nil {
+					return false
+				}
+
+				return er.Error() == "response is nil"
+			},
+		},
+		{
+			name: "response body is nil",
+			response: &http.Response{
+				Body: nil,
+			},
+			resultFunc: func(response *http.Response) bool {
+				var t item
+				er := clink.ResponseToYaml(response, &t)
+				if er == nil {
+					return false
+				}
+
+				return er.Error() == "response body is nil"
+			},
+		},
+		{
+			name: "yaml decode error",
+			response: &http.Response{
+				Body: io.NopCloser(strings.NewReader("key: [value")),
+			},
+			resultFunc: func(response *http.Response) bool {
+				var t item
+				er := clink.ResponseToYaml(response, &t)
+				if er == nil {
+					return false
+				}
+
+				return strings.Contains(er.Error(), "failed to decode response")
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if !tc.resultFunc(tc.response) {
+				t.Errorf("expected result to be successful")
+			}
+		})
+	}
+}
+
 func TestRateLimiter(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
@@ -487,8 +780,9 @@ func TestSuccessfulRetries(t *testing.T) {
 	}
 
 	_, err = client.Do(req)
-	if err != nil {
-		t.Fatalf("failed to make request: %v", err)
+	var httpErr *clink.HTTPError
+	if !errors.As(err, &httpErr) || httpErr.StatusCode != http.StatusInternalServerError {
+		t.Fatalf("expected an HTTPError once the retries are exhausted, got: %v", err)
 	}
 
 	if requestCount != retryCount+1 { // +1 for the initial request
@@ -570,8 +864,9 @@ func TestRequestBodyNotEmptyOnRetries(t *testing.T) {
 	}
 
 	_, err = client.Do(req)
-	if err != nil {
-		t.Fatalf("failed to make request: %v", err)
+	var httpErr *clink.HTTPError
+	if !errors.As(err, &httpErr) {
+		t.Fatalf("expected an HTTPError once the retries are exhausted, got: %v", err)
 	}
 
 	if requestCount != 2 {
@@ -0,0 +1,79 @@
+package clink
+
+import (
+	"crypto/tls"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// WithClientCertFiles presents the PEM encoded client certificate and key at the given paths to
+// servers requesting one. The files are checked before each TLS handshake and reloaded when they
+// change, so rotated certificates are used without restarting. If a reload fails, the previous
+// certificate is kept. The option can't be used with WithClient or WithTransport.
+func WithClientCertFiles(certPath, keyPath string) Option {
+	return func(c *Client) {
+		transport := c.ownTransport("WithClientCertFiles")
+		if transport == nil {
+			return
+		}
+
+		reloader := &certReloader{certPath: certPath, keyPath: keyPath}
+		if err := reloader.reload(); err != nil {
+			c.addConfigError("%v", err)
+			return
+		}
+
+		tlsConfig(transport).GetClientCertificate = reloader.getClientCertificate
+	}
+}
+
+// certReloader loads a client certificate and reloads it when its files change.
+type certReloader struct {
+	certPath, keyPath string
+
+	mu              sync.Mutex
+	cert            *tls.Certificate
+	certMod, keyMod time.Time
+}
+
+func (r *certReloader) getClientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	_ = r.reload()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.cert, nil
+}
+
+// reload loads the certificate if its files changed since it was last loaded.
+func (r *certReloader) reload() error {
+	certInfo, err := os.Stat(r.certPath)
+	if err != nil {
+		return fmt.Errorf("failed to read client certificate: %w", err)
+	}
+
+	keyInfo, err := os.Stat(r.keyPath)
+	if err != nil {
+		return fmt.Errorf("failed to read client key: %w", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.cert != nil && certInfo.ModTime().Equal(r.certMod) && keyInfo.ModTime().Equal(r.keyMod) {
+		return nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.certPath, r.keyPath)
+	if err != nil {
+		return fmt.Errorf("failed to load client certificate: %w", err)
+	}
+
+	r.cert = &cert
+	r.certMod = certInfo.ModTime()
+	r.keyMod = keyInfo.ModTime()
+
+	return nil
+}
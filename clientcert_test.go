@@ -0,0 +1,137 @@
+package clink_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/davesavic/clink"
+)
+
+// writeClientCert writes a self-signed certificate with the common name and its key to the paths.
+func writeClientCert(t *testing.T, commonName, certPath, keyPath string, modTime time.Time) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("failed to marshal key: %v", err)
+	}
+
+	files := map[string][]byte{
+		certPath: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		keyPath:  pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+	}
+	for path, data := range files {
+		if err := os.WriteFile(path, data, 0o600); err != nil {
+			t.Fatalf("failed to write %s: %v", path, err)
+		}
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatalf("failed to set mod time of %s: %v", path, err)
+		}
+	}
+}
+
+func TestWithClientCertFiles(t *testing.T) {
+	var commonName string
+	server, _ := newTLSServer(t, &tls.Config{ClientAuth: tls.RequireAnyClientCert})
+	server.Config.Handler = http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		commonName = r.TLS.PeerCertificates[0].Subject.CommonName
+	})
+	serverPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+
+	dir := t.TempDir()
+	certPath, keyPath := filepath.Join(dir, "client.pem"), filepath.Join(dir, "client-key.pem")
+	writeClientCert(t, "first", certPath, keyPath, time.Now().Add(-time.Minute))
+
+	c := clink.NewClient(clink.WithRootCAs(serverPEM), clink.WithClientCertFiles(certPath, keyPath))
+
+	get := func() {
+		t.Helper()
+
+		resp, err := c.Get(server.URL)
+		if err != nil {
+			t.Fatalf("failed to make request: %v", err)
+		}
+		_ = resp.Body.Close()
+		c.HttpClient.CloseIdleConnections()
+	}
+
+	get()
+	if commonName != "first" {
+		t.Errorf("expected first certificate, got: %q", commonName)
+	}
+
+	writeClientCert(t, "second", certPath, keyPath, time.Now())
+
+	get()
+	if commonName != "second" {
+		t.Errorf("expected rotated certificate, got: %q", commonName)
+	}
+}
+
+func TestWithClientCertFiles_BeforeTLSConfig(t *testing.T) {
+	var commonName string
+	server, _ := newTLSServer(t, &tls.Config{ClientAuth: tls.RequireAnyClientCert})
+	server.Config.Handler = http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		commonName = r.TLS.PeerCertificates[0].Subject.CommonName
+	})
+	serverPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+
+	dir := t.TempDir()
+	certPath, keyPath := filepath.Join(dir, "client.pem"), filepath.Join(dir, "client-key.pem")
+	writeClientCert(t, "client", certPath, keyPath, time.Now())
+
+	c := clink.NewClient(
+		clink.WithRootCAs(serverPEM),
+		clink.WithClientCertFiles(certPath, keyPath),
+		clink.WithTLSConfig(&tls.Config{MinVersion: tls.VersionTLS12}),
+	)
+
+	resp, err := c.Get(server.URL)
+	if err != nil {
+		t.Fatalf("failed to make request: %v", err)
+	}
+	_ = resp.Body.Close()
+
+	if commonName != "client" {
+		t.Errorf("expected the client certificate to be kept, got: %q", commonName)
+	}
+}
+
+func TestWithClientCertFiles_Missing(t *testing.T) {
+	dir := t.TempDir()
+	c := clink.NewClient(clink.WithClientCertFiles(filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")))
+
+	if _, err := c.Get("https://example.invalid"); !errors.Is(err, clink.ErrInvalidOption) {
+		t.Errorf("expected invalid option error, got: %v", err)
+	}
+}
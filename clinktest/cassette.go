@@ -0,0 +1,251 @@
+package clinktest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"sync"
+
+	"github.com/davesavic/clink"
+)
+
+// ErrNoInteraction is returned when replaying a request that wasn't recorded in the cassette.
+var ErrNoInteraction = errors.New("clinktest: no recorded interaction matches the request")
+
+// Mode is the mode of a Recorder.
+type Mode int
+
+const (
+	// ModeReplay responds to requests with the interactions of the cassette, without sending them.
+	ModeReplay Mode = iota
+	// ModeRecord sends the requests and records the interactions, replacing the cassette on Save.
+	ModeRecord
+	// ModeAuto replays the cassette if it exists, and records it otherwise.
+	ModeAuto
+)
+
+// Cassette is the content of a cassette file.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is a recorded request and its response.
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is a recorded request, with its secrets scrubbed.
+type RecordedRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+// RecordedResponse is a recorded response, with its secrets scrubbed.
+type RecordedResponse struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// MatchMethodURL matches requests with the same method and URL.
+func MatchMethodURL(req, recorded RecordedRequest) bool {
+	return req.Method == recorded.Method && req.URL == recorded.URL
+}
+
+// MatchMethodURLBody matches requests with the same method, URL and body.
+func MatchMethodURLBody(req, recorded RecordedRequest) bool {
+	return MatchMethodURL(req, recorded) && req.Body == recorded.Body
+}
+
+// RecorderOptions configures a Recorder. Zero fields are set to their default.
+type RecorderOptions struct {
+	// Mode is the mode of the recorder, ModeReplay by default.
+	Mode Mode
+	// Transport sends the requests being recorded. http.DefaultTransport is used if it is nil.
+	Transport http.RoundTripper
+	// Match reports whether a request, scrubbed like the recorded ones, matches a recorded request.
+	// MatchMethodURLBody is used if it is nil.
+	Match func(req, recorded RecordedRequest) bool
+	// Redactor scrubs the secrets of the recorded interactions: redacted headers, JSON fields and
+	// query parameters named like redacted fields are replaced by clink.RedactedValue.
+	// A nil Redactor scrubs the defaults.
+	Redactor *clink.Redactor
+}
+
+// Recorder is an http.RoundTripper recording real interactions to a cassette file, and replaying
+// them deterministically, for example in CI. Each recorded interaction is replayed once, in the
+// order it was recorded. It is safe for concurrent use.
+type Recorder struct {
+	path      string
+	recording bool
+	opts      RecorderOptions
+
+	mu       sync.Mutex
+	cassette Cassette
+	used     []bool
+}
+
+// NewRecorder returns a recorder for the cassette file at path. In replay mode, the cassette is
+// loaded from the file.
+func NewRecorder(path string, opts RecorderOptions) (*Recorder, error) {
+	if opts.Transport == nil {
+		opts.Transport = http.DefaultTransport
+	}
+
+	if opts.Match == nil {
+		opts.Match = MatchMethodURLBody
+	}
+
+	r := &Recorder{path: path, opts: opts}
+
+	data, err := os.ReadFile(path)
+	switch {
+	case opts.Mode == ModeRecord || (opts.Mode == ModeAuto && errors.Is(err, os.ErrNotExist)):
+		r.recording = true
+		return r, nil
+	case err != nil:
+		return nil, fmt.Errorf("failed to read cassette: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &r.cassette); err != nil {
+		return nil, fmt.Errorf("failed to decode cassette: %w", err)
+	}
+	r.used = make([]bool, len(r.cassette.Interactions))
+
+	return r, nil
+}
+
+// Recording reports whether the recorder records interactions, rather than replaying them.
+func (r *Recorder) Recording() bool {
+	return r.recording
+}
+
+// Client returns a clink client sending its requests with the recorder.
+func (r *Recorder) Client(opts ...clink.Option) *clink.Client {
+	return clink.NewClient(append(opts, clink.WithTransport(r))...)
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read request body: %w", err)
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	recorded := r.scrubRequest(req, body)
+	if r.recording {
+		return r.record(req, recorded)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, interaction := range r.cassette.Interactions {
+		if !r.used[i] && r.opts.Match(recorded, interaction.Request) {
+			r.used[i] = true
+			return interaction.Response.response(req), nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s %s", ErrNoInteraction, recorded.Method, recorded.URL)
+}
+
+func (r *Recorder) record(req *http.Request, recorded RecordedRequest) (*http.Response, error) {
+	resp, err := r.opts.Transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, Interaction{
+		Request: recorded,
+		Response: RecordedResponse{
+			StatusCode: resp.StatusCode,
+			Header:     r.opts.Redactor.Header(resp.Header),
+			Body:       string(r.opts.Redactor.JSON(body)),
+		},
+	})
+	r.mu.Unlock()
+
+	return resp, nil
+}
+
+// Save writes the recorded interactions to the cassette file. It does nothing when replaying.
+func (r *Recorder) Save() error {
+	if !r.recording {
+		return nil
+	}
+
+	r.mu.Lock()
+	data, err := json.MarshalIndent(r.cassette, "", "  ")
+	r.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to encode cassette: %w", err)
+	}
+
+	if err := os.WriteFile(r.path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write cassette: %w", err)
+	}
+
+	return nil
+}
+
+// scrubRequest returns the request as recorded in a cassette.
+func (r *Recorder) scrubRequest(req *http.Request, body []byte) RecordedRequest {
+	u := *req.URL
+	if u.User != nil {
+		u.User = url.User(clink.RedactedValue)
+	}
+
+	query := u.Query()
+	scrubbed := false
+	for key, values := range query {
+		if r.opts.Redactor.RedactsField(key) {
+			for i := range values {
+				values[i] = clink.RedactedValue
+			}
+			scrubbed = true
+		}
+	}
+	if scrubbed {
+		u.RawQuery = query.Encode()
+	}
+
+	return RecordedRequest{
+		Method: req.Method,
+		URL:    u.String(),
+		Header: r.opts.Redactor.Header(req.Header),
+		Body:   string(r.opts.Redactor.JSON(body)),
+	}
+}
+
+func (rr RecordedResponse) response(req *http.Request) *http.Response {
+	resp := NewResponse(req, rr.StatusCode, rr.Body)
+	for key, values := range rr.Header {
+		resp.Header[key] = append([]string(nil), values...)
+	}
+	resp.Header.Set("Content-Length", strconv.Itoa(len(rr.Body)))
+
+	return resp
+}
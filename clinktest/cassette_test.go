@@ -0,0 +1,115 @@
+package clinktest_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/davesavic/clink"
+	"github.com/davesavic/clink/clinktest"
+)
+
+func TestRecorder(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Set-Cookie", "session=abc")
+		_, _ = w.Write([]byte(`{"path":"` + r.URL.Path + `","request":` + string(body) + `,"access_token":"xyz"}`))
+	}))
+
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	send := func(c *clink.Client, body string) (string, error) {
+		req, _ := http.NewRequest(http.MethodPost, server.URL+"/login?access_token=secret", strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer secret")
+		resp, err := c.Do(req)
+		if err != nil {
+			return "", err
+		}
+		defer resp.Body.Close()
+
+		data, _ := io.ReadAll(resp.Body)
+		return string(data), nil
+	}
+
+	recorder, err := clinktest.NewRecorder(path, clinktest.RecorderOptions{Mode: clinktest.ModeAuto})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !recorder.Recording() {
+		t.Fatal("expected the recorder to record a missing cassette")
+	}
+
+	recorded, err := send(recorder.Client(), `{"user":"alice","password":"hunter2"}`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := recorder.Save(); err != nil {
+		t.Fatalf("failed to save cassette: %v", err)
+	}
+	server.Close()
+
+	cassette, _ := os.ReadFile(path)
+	for _, secret := range []string{"hunter2", "xyz", "Bearer secret", "session=abc", "token=secret"} {
+		if strings.Contains(string(cassette), secret) {
+			t.Errorf("expected %q to be scrubbed from the cassette:\n%s", secret, cassette)
+		}
+	}
+
+	testCases := []struct {
+		name       string
+		body       string
+		resultFunc func(*testing.T, string, error)
+	}{
+		{
+			name: "replays the matching interaction",
+			body: `{"user":"alice","password":"other"}`,
+			resultFunc: func(t *testing.T, body string, err error) {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+
+				if !strings.Contains(body, `"path":"/login"`) || !strings.Contains(body, clink.RedactedValue) || body == recorded {
+					t.Errorf("expected the scrubbed recorded response, got: %s", body)
+				}
+			},
+		},
+		{
+			name: "fails without a matching interaction",
+			body: `{"user":"bob"}`,
+			resultFunc: func(t *testing.T, _ string, err error) {
+				if !errors.Is(err, clinktest.ErrNoInteraction) {
+					t.Errorf("expected ErrNoInteraction, got: %v", err)
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			replayer, err := clinktest.NewRecorder(path, clinktest.RecorderOptions{Mode: clinktest.ModeAuto})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if replayer.Recording() {
+				t.Fatal("expected the recorder to replay the existing cassette")
+			}
+
+			body, err := send(replayer.Client(), tc.body)
+			tc.resultFunc(t, body, err)
+		})
+	}
+}
+
+func TestNewRecorder_MissingCassette(t *testing.T) {
+	_, err := clinktest.NewRecorder(filepath.Join(t.TempDir(), "missing.json"), clinktest.RecorderOptions{})
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected the missing cassette to fail in replay mode, got: %v", err)
+	}
+}
@@ -0,0 +1,323 @@
+// Package clinktest provides a mock transport for unit testing code using clink clients without
+// an HTTP server.
+//
+// Register stubs on a Transport and send the requests with a client using it:
+//
+//	transport := clinktest.NewTransport(t)
+//	transport.On(clinktest.Method(http.MethodGet), clinktest.Path("/users/*")).RespondJSON(http.StatusOK, user)
+//	client := transport.Client(clink.WithBaseURL("https://api.example.com"))
+//
+// When the test ends, it fails if a request didn't match any stub, or if a stub wasn't called
+// the expected number of times.
+//
+// A Recorder records real interactions to a cassette file instead, and replays them in later runs.
+// NewClient returns a client bound to an httptest server for tests using a real handler.
+package clinktest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/davesavic/clink"
+)
+
+// ErrNoMatch is returned for requests that don't match any stub.
+var ErrNoMatch = errors.New("clinktest: no stub matches the request")
+
+// Matcher matches the requests of a stub.
+type Matcher struct {
+	desc  string
+	match func(*http.Request) bool
+}
+
+// MatcherFunc returns a Matcher using fn, described by desc in test failures.
+// The request body can be read by fn, it is restored for the other matchers.
+func MatcherFunc(desc string, fn func(*http.Request) bool) Matcher {
+	return Matcher{desc: desc, match: fn}
+}
+
+// Method matches requests with the method.
+func Method(method string) Matcher {
+	return MatcherFunc("method "+method, func(req *http.Request) bool {
+		return req.Method == method
+	})
+}
+
+// URL matches requests with the URL.
+func URL(url string) Matcher {
+	return MatcherFunc("url "+url, func(req *http.Request) bool {
+		return req.URL.String() == url
+	})
+}
+
+// URLPattern matches requests whose URL matches the regular expression. It panics if the
+// expression is invalid.
+func URLPattern(pattern string) Matcher {
+	re := regexp.MustCompile(pattern)
+	return MatcherFunc("url matching "+pattern, func(req *http.Request) bool {
+		return re.MatchString(req.URL.String())
+	})
+}
+
+// Path matches requests whose URL path matches the pattern, using the syntax of path.Match.
+func Path(pattern string) Matcher {
+	return MatcherFunc("path "+pattern, func(req *http.Request) bool {
+		ok, _ := path.Match(pattern, req.URL.Path)
+		return ok
+	})
+}
+
+// Query matches requests with the query parameter.
+func Query(key, value string) Matcher {
+	return MatcherFunc(fmt.Sprintf("query %s=%s", key, value), func(req *http.Request) bool {
+		return req.URL.Query().Get(key) == value
+	})
+}
+
+// Header matches requests with the header.
+func Header(key, value string) Matcher {
+	return MatcherFunc(fmt.Sprintf("header %s: %s", key, value), func(req *http.Request) bool {
+		return req.Header.Get(key) == value
+	})
+}
+
+// Body matches requests with the body.
+func Body(body string) Matcher {
+	return MatcherFunc("body "+body, func(req *http.Request) bool {
+		return string(readBody(req)) == body
+	})
+}
+
+// BodyContains matches requests whose body contains the substring.
+func BodyContains(substr string) Matcher {
+	return MatcherFunc("body containing "+substr, func(req *http.Request) bool {
+		return bytes.Contains(readBody(req), []byte(substr))
+	})
+}
+
+// JSONBody matches requests whose body is JSON equal to v once both are encoded, regardless of
+// formatting and key order.
+func JSONBody(v any) Matcher {
+	expected, err := normalizeJSON(v)
+	return MatcherFunc(fmt.Sprintf("json body %v", expected), func(req *http.Request) bool {
+		var actual any
+		if err != nil || json.Unmarshal(readBody(req), &actual) != nil {
+			return false
+		}
+		return reflect.DeepEqual(actual, expected)
+	})
+}
+
+func normalizeJSON(v any) (any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var normalized any
+	err = json.Unmarshal(data, &normalized)
+	return normalized, err
+}
+
+func readBody(req *http.Request) []byte {
+	if req.Body == nil {
+		return nil
+	}
+
+	body, _ := io.ReadAll(req.Body)
+	return body
+}
+
+// Stub is a stubbed response for the requests matching all its matchers.
+type Stub struct {
+	matchers []Matcher
+	respond  func(*http.Request) (*http.Response, error)
+	times    int
+	optional bool
+	calls    int
+}
+
+// Respond sets the status and body of the responses of the stub.
+func (s *Stub) Respond(status int, body string) *Stub {
+	return s.RespondWith(func(req *http.Request) (*http.Response, error) {
+		return NewResponse(req, status, body), nil
+	})
+}
+
+// RespondJSON sets the status of the responses of the stub, and their body to v encoded as JSON.
+// It panics if v can't be encoded.
+func (s *Stub) RespondJSON(status int, v any) *Stub {
+	body, err := json.Marshal(v)
+	if err != nil {
+		panic(fmt.Sprintf("clinktest: failed to encode response body: %v", err))
+	}
+
+	return s.RespondWith(func(req *http.Request) (*http.Response, error) {
+		resp := NewResponse(req, status, string(body))
+		resp.Header.Set("Content-Type", "application/json")
+		return resp, nil
+	})
+}
+
+// RespondError makes the requests of the stub fail with err, like a network error.
+func (s *Stub) RespondError(err error) *Stub {
+	return s.RespondWith(func(*http.Request) (*http.Response, error) {
+		return nil, err
+	})
+}
+
+// RespondWith sets the function returning the responses of the stub.
+func (s *Stub) RespondWith(fn func(*http.Request) (*http.Response, error)) *Stub {
+	s.respond = fn
+	return s
+}
+
+// Times sets the number of requests the stub must match. Once matched n times, the stub no
+// longer matches requests. By default, a stub matches any number of requests, but at least one.
+func (s *Stub) Times(n int) *Stub {
+	s.times = n
+	return s
+}
+
+// Optional makes the stub not fail the test when it's called fewer times than expected.
+func (s *Stub) Optional() *Stub {
+	s.optional = true
+	return s
+}
+
+func (s *Stub) String() string {
+	return "stub" + describe(s.matchers)
+}
+
+// NewResponse returns a response to the request with the status and body.
+func NewResponse(req *http.Request, status int, body string) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        make(http.Header),
+		Body:          io.NopCloser(strings.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+// Transport is a mock http.RoundTripper responding to requests with the first matching stub.
+// It is safe for concurrent use.
+type Transport struct {
+	mu        sync.Mutex
+	stubs     []*Stub
+	unmatched []string
+}
+
+// NewTransport returns a transport without stubs. If t is not nil, the test fails when it ends
+// if the expectations of the transport aren't met (see Verify).
+func NewTransport(t testing.TB) *Transport {
+	transport := &Transport{}
+	if t != nil {
+		t.Cleanup(func() {
+			transport.Verify(t)
+		})
+	}
+
+	return transport
+}
+
+// On adds a stub for the requests matching all the matchers. The stub responds with an empty
+// 200 response unless set otherwise.
+func (m *Transport) On(matchers ...Matcher) *Stub {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stub := &Stub{matchers: matchers}
+	stub.Respond(http.StatusOK, "")
+	m.stubs = append(m.stubs, stub)
+
+	return stub
+}
+
+// Client returns a clink client sending its requests with the transport.
+func (m *Transport) Client(opts ...clink.Option) *clink.Client {
+	return clink.NewClient(append(opts, clink.WithTransport(m))...)
+}
+
+func (m *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body := readBody(req)
+	if req.Body != nil {
+		_ = req.Body.Close()
+	}
+
+	m.mu.Lock()
+	stub := m.match(req, body)
+	if stub == nil {
+		m.unmatched = append(m.unmatched, req.Method+" "+req.URL.String())
+	}
+	m.mu.Unlock()
+
+	if stub == nil {
+		return nil, fmt.Errorf("%w: %s %s", ErrNoMatch, req.Method, req.URL)
+	}
+
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return stub.respond(req)
+}
+
+// match returns the first stub matching the request and counts the call, or nil.
+func (m *Transport) match(req *http.Request, body []byte) *Stub {
+	for _, stub := range m.stubs {
+		if stub.times > 0 && stub.calls >= stub.times {
+			continue
+		}
+
+		matched := true
+		for _, matcher := range stub.matchers {
+			req.Body = io.NopCloser(bytes.NewReader(body))
+			if !matcher.match(req) {
+				matched = false
+				break
+			}
+		}
+
+		if matched {
+			stub.calls++
+			return stub
+		}
+	}
+
+	return nil
+}
+
+// Verify reports an error to t for each request that didn't match any stub, and for each stub
+// that wasn't called the expected number of times.
+func (m *Transport) Verify(t testing.TB) {
+	t.Helper()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, req := range m.unmatched {
+		t.Errorf("clinktest: unmatched request: %s", req)
+	}
+
+	for _, stub := range m.stubs {
+		switch {
+		case stub.optional:
+		case stub.times > 0 && stub.calls != stub.times:
+			t.Errorf("clinktest: %s: expected %d calls, got %d", stub, stub.times, stub.calls)
+		case stub.times == 0 && stub.calls == 0:
+			t.Errorf("clinktest: %s: expected at least one call", stub)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package clinktest_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/davesavic/clink"
+	"github.com/davesavic/clink/clinktest"
+)
+
+// recorder records the errors reported by the transport instead of failing the test.
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestTransport(t *testing.T) {
+	testCases := []struct {
+		name       string
+		stub       func(*clinktest.Transport)
+		send       func(*clink.Client) (*http.Response, error)
+		resultFunc func(*testing.T, *http.Response, error, []string)
+	}{
+		{
+			name: "matches method and path",
+			stub: func(m *clinktest.Transport) {
+				m.On(clinktest.Method(http.MethodGet), clinktest.Path("/users/*")).Respond(http.StatusOK, "alice")
+			},
+			send: func(c *clink.Client) (*http.Response, error) {
+				return c.Get("/users/1")
+			},
+			resultFunc: func(t *testing.T, resp *http.Response, err error, failures []string) {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+
+				if body, _ := io.ReadAll(resp.Body); string(body) != "alice" || len(failures) != 0 {
+					t.Errorf("expected the stubbed response, got: %s %v", body, failures)
+				}
+			},
+		},
+		{
+			name: "matches header, query and json body",
+			stub: func(m *clinktest.Transport) {
+				m.On(
+					clinktest.Header("X-Token", "secret"),
+					clinktest.Query("dry", "true"),
+					clinktest.JSONBody(map[string]any{"name": "bob", "age": 30}),
+				).RespondJSON(http.StatusCreated, map[string]int{"id": 2})
+			},
+			send: func(c *clink.Client) (*http.Response, error) {
+				req, _ := http.NewRequest(http.MethodPost, "/users?dry=true", strings.NewReader(`{"age": 30, "name": "bob"}`))
+				req.Header.Set("X-Token", "secret")
+				return c.Do(req)
+			},
+			resultFunc: func(t *testing.T, resp *http.Response, err error, failures []string) {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+
+				body, _ := io.ReadAll(resp.Body)
+				if resp.StatusCode != http.StatusCreated || string(body) != `{"id":2}` || resp.Header.Get("Content-Type") != "application/json" {
+					t.Errorf("expected the stubbed json response, got: %d %s", resp.StatusCode, body)
+				}
+			},
+		},
+		{
+			name: "stubbed error",
+			stub: func(m *clinktest.Transport) {
+				m.On(clinktest.URL("https://api.example.com/users")).RespondError(errors.New("connection reset"))
+			},
+			send: func(c *clink.Client) (*http.Response, error) {
+				return c.Get("/users")
+			},
+			resultFunc: func(t *testing.T, _ *http.Response, err error, _ []string) {
+				if err == nil || !strings.Contains(err.Error(), "connection reset") {
+					t.Errorf("expected the stubbed error, got: %v", err)
+				}
+			},
+		},
+		{
+			name: "unmatched request",
+			stub: func(m *clinktest.Transport) {
+				m.On(clinktest.BodyContains("hello")).Times(1)
+			},
+			send: func(c *clink.Client) (*http.Response, error) {
+				return c.Post("/users", strings.NewReader("goodbye"))
+			},
+			resultFunc: func(t *testing.T, _ *http.Response, err error, failures []string) {
+				if !errors.Is(err, clinktest.ErrNoMatch) {
+					t.Errorf("expected ErrNoMatch, got: %v", err)
+				}
+
+				if len(failures) != 2 || !strings.Contains(failures[0], "unmatched request: POST https://api.example.com/users") ||
+					!strings.Contains(failures[1], "stub (body containing hello): expected 1 calls, got 0") {
+					t.Errorf("expected the unmatched request and missing call to be reported, got: %v", failures)
+				}
+			},
+		},
+		{
+			name: "stub exhausted",
+			stub: func(m *clinktest.Transport) {
+				m.On(clinktest.URLPattern(`/users$`)).Times(1).Respond(http.StatusOK, "first")
+				m.On(clinktest.URLPattern(`/users$`)).Respond(http.StatusOK, "second")
+			},
+			send: func(c *clink.Client) (*http.Response, error) {
+				_, _ = c.Get("/users")
+				return c.Get("/users")
+			},
+			resultFunc: func(t *testing.T, resp *http.Response, err error, failures []string) {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+
+				if body, _ := io.ReadAll(resp.Body); string(body) != "second" || len(failures) != 0 {
+					t.Errorf("expected the second stub once the first is exhausted, got: %s %v", body, failures)
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := &recorder{TB: t}
+			transport := clinktest.NewTransport(nil)
+			tc.stub(transport)
+
+			client := transport.Client(clink.WithBaseURL("https://api.example.com"))
+			resp, err := tc.send(client)
+			transport.Verify(rec)
+
+			tc.resultFunc(t, resp, err, rec.errors)
+		})
+	}
+}
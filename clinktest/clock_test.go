@@ -0,0 +1,84 @@
+package clinktest_test
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/davesavic/clink"
+	"github.com/davesavic/clink/clinktest"
+)
+
+func TestClock(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name       string
+		opts       []clink.Option
+		status     int
+		requests   int
+		resultFunc func(*testing.T, *clinktest.Clock)
+	}{
+		{
+			name: "retry delays",
+			opts: []clink.Option{clink.WithRetries(3, func(_ *http.Request, resp *http.Response, err error) bool {
+				return err != nil || resp.StatusCode >= http.StatusInternalServerError
+			})},
+			status:   http.StatusServiceUnavailable,
+			requests: 1,
+			resultFunc: func(t *testing.T, clock *clinktest.Clock) {
+				expected := []time.Duration{time.Second, 2 * time.Second}
+				if sleeps := clock.Sleeps(); !slices.Equal(sleeps, expected) {
+					t.Errorf("expected retry delays %v, got: %v", expected, sleeps)
+				}
+
+				if elapsed := clock.Now().Sub(start); elapsed != 3*time.Second {
+					t.Errorf("expected the fake time to advance by 3s, got: %v", elapsed)
+				}
+			},
+		},
+		{
+			name:     "rate limit",
+			opts:     []clink.Option{clink.WithRateLimit(60)},
+			status:   http.StatusOK,
+			requests: 3,
+			resultFunc: func(t *testing.T, clock *clinktest.Clock) {
+				expected := []time.Duration{time.Second, time.Second}
+				if sleeps := clock.Sleeps(); !slices.Equal(sleeps, expected) {
+					t.Errorf("expected rate limit waits %v, got: %v", expected, sleeps)
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			transport := clinktest.NewTransport(nil)
+			transport.On().Respond(tc.status, "")
+
+			clock := clinktest.NewClock(start)
+			client := transport.Client(append(tc.opts, clink.WithClock(clock))...)
+
+			begin := time.Now()
+			for i := 0; i < tc.requests; i++ {
+				resp, err := client.Get("https://api.example.com")
+				var httpErr *clink.HTTPError
+				if errors.As(err, &httpErr) {
+					continue
+				}
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				_ = resp.Body.Close()
+			}
+
+			if time.Since(begin) > time.Second {
+				t.Errorf("expected the fake clock not to wait, took %v", time.Since(begin))
+			}
+
+			tc.resultFunc(t, clock)
+		})
+	}
+}
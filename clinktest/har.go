@@ -0,0 +1,87 @@
+package clinktest
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+)
+
+// har is the subset of the HTTP Archive format used to stub responses.
+type har struct {
+	Log struct {
+		Entries []harEntry `json:"entries"`
+	} `json:"log"`
+}
+
+type harEntry struct {
+	Request struct {
+		Method string `json:"method"`
+		URL    string `json:"url"`
+	} `json:"request"`
+	Response struct {
+		Status  int         `json:"status"`
+		Headers []harHeader `json:"headers"`
+		Content struct {
+			Text     string `json:"text"`
+			Encoding string `json:"encoding"`
+		} `json:"content"`
+	} `json:"response"`
+}
+
+type harHeader struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// LoadHAR adds a stub for each entry of the HAR file at path, such as traffic captured in a browser.
+// Each stub matches a single request with the method and URL of the entry, and responds with the
+// recorded status, headers and content, so entries with the same URL are replayed in order.
+// The stubs are optional, so entries that aren't requested don't fail the test.
+func (m *Transport) LoadHAR(path string) ([]*Stub, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read HAR file: %w", err)
+	}
+
+	var archive har
+	if err := json.Unmarshal(data, &archive); err != nil {
+		return nil, fmt.Errorf("failed to decode HAR file: %w", err)
+	}
+
+	stubs := make([]*Stub, 0, len(archive.Log.Entries))
+	for i, entry := range archive.Log.Entries {
+		body := entry.Response.Content.Text
+		if entry.Response.Content.Encoding == "base64" {
+			decoded, err := base64.StdEncoding.DecodeString(body)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode content of HAR entry %d: %w", i, err)
+			}
+			body = string(decoded)
+		}
+
+		header := make(http.Header)
+		for _, h := range entry.Response.Headers {
+			// The content is stored decoded, and HTTP/2 pseudo-headers aren't headers.
+			if h.Name == "" || h.Name[0] == ':' || http.CanonicalHeaderKey(h.Name) == "Content-Encoding" ||
+				http.CanonicalHeaderKey(h.Name) == "Content-Length" {
+				continue
+			}
+			header.Add(h.Name, h.Value)
+		}
+
+		status := entry.Response.Status
+		stub := m.On(Method(entry.Request.Method), URL(entry.Request.URL)).Times(1).Optional()
+		stub.RespondWith(func(req *http.Request) (*http.Response, error) {
+			resp := NewResponse(req, status, body)
+			for key, values := range header {
+				resp.Header[key] = append([]string(nil), values...)
+			}
+			return resp, nil
+		})
+		stubs = append(stubs, stub)
+	}
+
+	return stubs, nil
+}
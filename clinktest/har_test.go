@@ -0,0 +1,77 @@
+package clinktest_test
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/davesavic/clink/clinktest"
+)
+
+const testHAR = `{
+  "log": {
+    "version": "1.2",
+    "entries": [
+      {
+        "request": {"method": "GET", "url": "https://api.example.com/users"},
+        "response": {
+          "status": 200,
+          "headers": [{"name": "content-type", "value": "application/json"}, {"name": "content-encoding", "value": "gzip"}],
+          "content": {"mimeType": "application/json", "text": "[\"alice\"]"}
+        }
+      },
+      {
+        "request": {"method": "GET", "url": "https://api.example.com/users"},
+        "response": {"status": 200, "headers": [], "content": {"text": "WyJhbGljZSIsImJvYiJd", "encoding": "base64"}}
+      },
+      {
+        "request": {"method": "DELETE", "url": "https://api.example.com/users/1"},
+        "response": {"status": 204, "headers": [], "content": {}}
+      }
+    ]
+  }
+}`
+
+func TestTransport_LoadHAR(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "traffic.har")
+	if err := os.WriteFile(path, []byte(testHAR), 0o644); err != nil {
+		t.Fatalf("failed to write HAR file: %v", err)
+	}
+
+	transport := clinktest.NewTransport(t)
+	stubs, err := transport.LoadHAR(path)
+	if err != nil {
+		t.Fatalf("failed to load HAR file: %v", err)
+	}
+
+	if len(stubs) != 3 {
+		t.Fatalf("expected a stub per entry, got %d", len(stubs))
+	}
+
+	client := transport.Client()
+
+	testCases := []struct {
+		name        string
+		contentType string
+		body        string
+	}{
+		{name: "first entry", contentType: "application/json", body: `["alice"]`},
+		{name: "next entry with the same url", body: `["alice","bob"]`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, err := client.Get("https://api.example.com/users")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			defer resp.Body.Close()
+
+			body, _ := io.ReadAll(resp.Body)
+			if string(body) != tc.body || resp.Header.Get("Content-Type") != tc.contentType || resp.Header.Get("Content-Encoding") != "" {
+				t.Errorf("unexpected response: %v %s", resp.Header, body)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package clinktest
+
+import (
+	"context"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/davesavic/clink"
+)
+
+// NewClient starts an httptest server with the handler and returns a client whose base URL is the
+// URL of the server, configured with the options. The server is closed when the test ends.
+func NewClient(t testing.TB, handler http.Handler, opts ...clink.Option) *clink.Client {
+	t.Helper()
+
+	return newServerClient(t, httptest.NewServer(handler), opts)
+}
+
+// NewTLSClient is like NewClient, with an HTTPS server whose certificate is trusted by the client.
+func NewTLSClient(t testing.TB, handler http.Handler, opts ...clink.Option) *clink.Client {
+	t.Helper()
+
+	server := httptest.NewTLSServer(handler)
+	cert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+
+	return newServerClient(t, server, append([]clink.Option{clink.WithRootCAs(cert)}, opts...))
+}
+
+func newServerClient(t testing.TB, server *httptest.Server, opts []clink.Option) *clink.Client {
+	t.Cleanup(server.Close)
+
+	client := clink.NewClient(append([]clink.Option{clink.WithBaseURL(server.URL)}, opts...)...)
+	t.Cleanup(func() {
+		_ = client.Shutdown(context.Background())
+	})
+
+	return client
+}
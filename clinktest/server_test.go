@@ -0,0 +1,42 @@
+package clinktest_test
+
+import (
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/davesavic/clink"
+	"github.com/davesavic/clink/clinktest"
+)
+
+func TestNewClient(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.URL.Path + " " + r.Header.Get("X-Test")))
+	})
+
+	testCases := []struct {
+		name      string
+		newClient func(testing.TB, http.Handler, ...clink.Option) *clink.Client
+		scheme    string
+	}{
+		{name: "http", newClient: clinktest.NewClient, scheme: "http://"},
+		{name: "https", newClient: clinktest.NewTLSClient, scheme: "https://"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := tc.newClient(t, handler, clink.WithHeader("X-Test", "yes"))
+
+			resp, err := client.Get("/users")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			defer resp.Body.Close()
+
+			body, _ := io.ReadAll(resp.Body)
+			if string(body) != "/users yes" || resp.Request.URL.Scheme+"://" != tc.scheme {
+				t.Errorf("expected the request to be sent to the server, got: %s %s", resp.Request.URL, body)
+			}
+		})
+	}
+}
@@ -0,0 +1,207 @@
+package clinktest
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/davesavic/clink"
+)
+
+// Call is a request captured by Traffic.
+type Call struct {
+	Request *http.Request
+	Body    []byte
+}
+
+// matches reports whether the call has the method, a path matching the pattern (see path.Match)
+// and matches all the matchers.
+func (c Call) matches(method, pattern string, matchers []Matcher) bool {
+	if c.Request.Method != method {
+		return false
+	}
+
+	if ok, _ := path.Match(pattern, c.Request.URL.Path); !ok {
+		return false
+	}
+
+	for _, matcher := range matchers {
+		req := c.Request.Clone(c.Request.Context())
+		req.Body = io.NopCloser(bytes.NewReader(c.Body))
+		if !matcher.match(req) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Traffic is an http.RoundTripper capturing the requests sent with another transport, such as a
+// mock Transport or the transport of an httptest server, and providing assertions on them.
+// It is safe for concurrent use.
+type Traffic struct {
+	next http.RoundTripper
+
+	mu    sync.Mutex
+	calls []Call
+}
+
+// NewTraffic returns a Traffic sending the requests with next, or with http.DefaultTransport if
+// next is nil.
+func NewTraffic(next http.RoundTripper) *Traffic {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	return &Traffic{next: next}
+}
+
+// Client returns a clink client sending its requests with the traffic.
+func (t *Traffic) Client(opts ...clink.Option) *clink.Client {
+	return clink.NewClient(append(opts, clink.WithTransport(t))...)
+}
+
+func (t *Traffic) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read request body: %w", err)
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	t.mu.Lock()
+	t.calls = append(t.calls, Call{Request: req.Clone(req.Context()), Body: body})
+	t.mu.Unlock()
+
+	return t.next.RoundTrip(req)
+}
+
+// Calls returns the captured requests, in the order they were sent.
+func (t *Traffic) Calls() []Call {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return append([]Call(nil), t.calls...)
+}
+
+// Reset discards the captured requests.
+func (t *Traffic) Reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.calls = nil
+}
+
+// count returns the number of captured requests with the method and a path matching the pattern
+// (see path.Match) that match all the matchers.
+func (t *Traffic) count(method, pattern string, matchers []Matcher) int {
+	n := 0
+	for _, call := range t.Calls() {
+		if call.matches(method, pattern, matchers) {
+			n++
+		}
+	}
+
+	return n
+}
+
+// AssertCalled fails the test unless a request was sent with the method and a path matching the
+// pattern (see path.Match), matching all the matchers.
+func (t *Traffic) AssertCalled(tb testing.TB, method, pattern string, matchers ...Matcher) bool {
+	tb.Helper()
+
+	if t.count(method, pattern, matchers) == 0 {
+		tb.Errorf("clinktest: expected a call to %s %s%s, got: %s", method, pattern, describe(matchers), t)
+		return false
+	}
+
+	return true
+}
+
+// AssertNotCalled fails the test if a request was sent with the method and a path matching the
+// pattern, matching all the matchers.
+func (t *Traffic) AssertNotCalled(tb testing.TB, method, pattern string, matchers ...Matcher) bool {
+	tb.Helper()
+
+	if n := t.count(method, pattern, matchers); n > 0 {
+		tb.Errorf("clinktest: expected no call to %s %s%s, got %d", method, pattern, describe(matchers), n)
+		return false
+	}
+
+	return true
+}
+
+// AssertCallCount fails the test unless n requests were sent with the method and a path matching
+// the pattern, matching all the matchers.
+func (t *Traffic) AssertCallCount(tb testing.TB, n int, method, pattern string, matchers ...Matcher) bool {
+	tb.Helper()
+
+	if count := t.count(method, pattern, matchers); count != n {
+		tb.Errorf("clinktest: expected %d calls to %s %s%s, got %d", n, method, pattern, describe(matchers), count)
+		return false
+	}
+
+	return true
+}
+
+// AssertOrder fails the test unless requests were sent in the order of the calls, each written as
+// a method and a path pattern, such as "GET /users/*". Other requests may be sent in between.
+func (t *Traffic) AssertOrder(tb testing.TB, calls ...string) bool {
+	tb.Helper()
+
+	next := 0
+	for _, call := range t.Calls() {
+		if next == len(calls) {
+			break
+		}
+
+		method, pattern, _ := strings.Cut(calls[next], " ")
+		if call.matches(method, pattern, nil) {
+			next++
+		}
+	}
+
+	if next < len(calls) {
+		tb.Errorf("clinktest: expected calls in order %q, missing %q, got: %s", calls, calls[next], t)
+		return false
+	}
+
+	return true
+}
+
+// String returns the method and URL of the captured requests.
+func (t *Traffic) String() string {
+	calls := t.Calls()
+	if len(calls) == 0 {
+		return "no calls"
+	}
+
+	descs := make([]string, len(calls))
+	for i, call := range calls {
+		descs[i] = call.Request.Method + " " + call.Request.URL.String()
+	}
+
+	return strings.Join(descs, ", ")
+}
+
+func describe(matchers []Matcher) string {
+	if len(matchers) == 0 {
+		return ""
+	}
+
+	descs := make([]string, len(matchers))
+	for i, m := range matchers {
+		descs[i] = m.desc
+	}
+
+	return " (" + strings.Join(descs, ", ") + ")"
+}
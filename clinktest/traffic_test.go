@@ -0,0 +1,93 @@
+package clinktest_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/davesavic/clink/clinktest"
+)
+
+func TestTraffic(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	defer server.Close()
+
+	traffic := clinktest.NewTraffic(server.Client().Transport)
+	client := traffic.Client()
+
+	_, _ = client.Get(server.URL + "/users?page=1")
+	req, _ := http.NewRequest(http.MethodPost, server.URL+"/users", strings.NewReader(`{"name":"alice"}`))
+	req.Header.Set("X-Token", "secret")
+	_, _ = client.Do(req)
+	_, _ = client.Get(server.URL + "/users/1")
+
+	testCases := []struct {
+		name     string
+		assert   func(testing.TB) bool
+		expected bool
+	}{
+		{
+			name: "called",
+			assert: func(tb testing.TB) bool {
+				return traffic.AssertCalled(tb, http.MethodPost, "/users", clinktest.Header("X-Token", "secret"), clinktest.JSONBody(map[string]string{"name": "alice"}))
+			},
+			expected: true,
+		},
+		{
+			name: "not called with matcher",
+			assert: func(tb testing.TB) bool {
+				return traffic.AssertCalled(tb, http.MethodPost, "/users", clinktest.BodyContains("bob"))
+			},
+		},
+		{
+			name: "not called",
+			assert: func(tb testing.TB) bool {
+				return traffic.AssertNotCalled(tb, http.MethodDelete, "/users/*")
+			},
+			expected: true,
+		},
+		{
+			name: "call count",
+			assert: func(tb testing.TB) bool {
+				return traffic.AssertCallCount(tb, 1, http.MethodGet, "/users/*")
+			},
+			expected: true,
+		},
+		{
+			name: "wrong call count",
+			assert: func(tb testing.TB) bool {
+				return traffic.AssertCallCount(tb, 2, http.MethodGet, "/users", clinktest.Query("page", "1"))
+			},
+		},
+		{
+			name: "order",
+			assert: func(tb testing.TB) bool {
+				return traffic.AssertOrder(tb, "GET /users", "GET /users/1")
+			},
+			expected: true,
+		},
+		{
+			name: "wrong order",
+			assert: func(tb testing.TB) bool {
+				return traffic.AssertOrder(tb, "POST /users", "GET /users")
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := &recorder{TB: t}
+			ok := tc.assert(rec)
+
+			if ok != tc.expected || (len(rec.errors) == 0) != tc.expected {
+				t.Errorf("expected the assertion to return %v, got %v with failures: %v", tc.expected, ok, rec.errors)
+			}
+		})
+	}
+
+	traffic.Reset()
+	if calls := traffic.Calls(); len(calls) != 0 {
+		t.Errorf("expected no calls after reset, got %d", len(calls))
+	}
+}
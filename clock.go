@@ -0,0 +1,127 @@
+package clink
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+)
+
+// Clock tells the time and waits for the retry delays, rate limiting, circuit breakers and
+// endpoint failover of a client. Tests can use a fake clock to run them without waiting.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+	// After waits for the duration to elapse and then sends the current time on the returned channel.
+	After(d time.Duration) <-chan time.Time
+}
+
+// WithClock sets the clock of the client. The real time is used by default.
+func WithClock(clock Clock) Option {
+	return func(c *Client) {
+		if clock == nil {
+			c.addConfigError("WithClock: clock must not be nil")
+			return
+		}
+
+		c.clock = clock
+	}
+}
+
+// WithTestMode makes the client skip its waits, such as retry delays, rate limiting and polling
+// intervals, so that tests exercising them run in milliseconds. Waiting advances the time of the
+// client instead, as if the wait had elapsed, so time-based behavior like circuit breaker timeouts
+// stays consistent. It replaces the clock set with WithClock.
+func WithTestMode() Option {
+	return WithClock(&skipClock{})
+}
+
+// realClock is the Clock using the real time.
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// clocked is implemented by the cache stores, cookie jars and proxy pools of the library, which
+// tell the time with the clock of the client they are used by.
+type clocked interface {
+	useClock(clock Clock)
+}
+
+// bindClock makes the cache store, cookie jar and proxy pool of the client tell the time with the
+// client clock.
+func (c *Client) bindClock() {
+	for _, v := range []any{c.Cache, c.HttpClient.Jar, c.HttpClient.Transport} {
+		if v, ok := v.(clocked); ok {
+			v.useClock(c.clock)
+		}
+	}
+}
+
+// elapsed returns the time elapsed since t according to the clock of the client.
+func (c *Client) elapsed(t time.Time) time.Duration {
+	return c.clock.Now().Sub(t)
+}
+
+// sleep waits for the duration with the clock of the client, or until ctx is done.
+func (c *Client) sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	select {
+	case <-c.clock.After(d):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// waitRateLimiter waits for the rate limiter of the client to allow a request, like rate.Limiter.Wait
+// but with the clock of the client.
+func (c *Client) waitRateLimiter(ctx context.Context) error {
+	now := c.clock.Now()
+	reservation := c.RateLimiter.ReserveN(now, 1)
+	if !reservation.OK() {
+		return errors.New("request exceeds the rate limiter burst")
+	}
+
+	delay := reservation.DelayFrom(now)
+	if delay == 0 {
+		return nil
+	}
+
+	if deadline, ok := ctx.Deadline(); ok && now.Add(delay).After(deadline) {
+		reservation.CancelAt(now)
+		return errors.New("rate limit wait would exceed context deadline")
+	}
+
+	if err := c.sleep(ctx, delay); err != nil {
+		reservation.CancelAt(c.clock.Now())
+		return err
+	}
+
+	return nil
+}
+
+// skipClock is the Clock of WithTestMode: it runs with the real time, shifted by the waits it skipped.
+type skipClock struct {
+	skipped atomic.Int64
+}
+
+func (c *skipClock) Now() time.Time {
+	return time.Now().Add(time.Duration(c.skipped.Load()))
+}
+
+func (c *skipClock) After(d time.Duration) <-chan time.Time {
+	c.skipped.Add(int64(d))
+
+	ch := make(chan time.Time, 1)
+	ch <- c.Now()
+	return ch
+}
@@ -0,0 +1,97 @@
+package clink_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/davesavic/clink"
+)
+
+func TestWithClock_Nil(t *testing.T) {
+	c := clink.NewClient(clink.WithClock(nil))
+
+	if _, err := c.Get("http://example.invalid"); !errors.Is(err, clink.ErrInvalidOption) {
+		t.Errorf("expected ErrInvalidOption, got: %v", err)
+	}
+}
+
+func TestWithTestMode(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	testCases := []struct {
+		name     string
+		opts     []clink.Option
+		requests int
+	}{
+		{
+			name: "retry delays",
+			opts: []clink.Option{clink.WithRetries(3, func(_ *http.Request, resp *http.Response, err error) bool {
+				return err != nil || resp.StatusCode >= http.StatusInternalServerError
+			})},
+			requests: 1,
+		},
+		{
+			name:     "rate limit",
+			opts:     []clink.Option{clink.WithRateLimit(60)},
+			requests: 3,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := clink.NewClient(append(tc.opts, clink.WithTestMode())...)
+
+			start := time.Now()
+			for i := 0; i < tc.requests; i++ {
+				resp, err := c.Get(server.URL)
+				var httpErr *clink.HTTPError
+				if errors.As(err, &httpErr) {
+					continue
+				}
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				_ = resp.Body.Close()
+			}
+
+			if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+				t.Errorf("expected the waits to be skipped, took %v", elapsed)
+			}
+		})
+	}
+}
+
+// manualClock is a Clock whose time only moves when advanced.
+type manualClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *manualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+func (c *manualClock) After(d time.Duration) <-chan time.Time {
+	c.advance(d)
+
+	ch := make(chan time.Time, 1)
+	ch <- c.Now()
+	return ch
+}
+
+func (c *manualClock) advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+}
@@ -0,0 +1,147 @@
+package clink
+
+import (
+	"maps"
+	"slices"
+)
+
+// Clone returns a copy of the client that can be configured independently of it: changes to the
+// headers, query parameters or codecs of the copy, and options applied to it with With, don't
+// affect the original. The copy has its own transport and connections, stats, request queue,
+// request deduplication, concurrency limits, bulkheads and endpoint health checks, and starts
+// with the event subscriptions of the original. It shares the rate limiter, cache store, circuit
+// breaker, DNS cache, logger and metrics of the original unless they are replaced by options.
+// A client created with WithClient or WithTransport shares its transport with the copy. The copy
+// of an immutable client (see WithImmutable) is immutable too.
+func (c *Client) Clone() *Client {
+	return c.With()
+}
+
+// clone returns a copy of the client, which must not be an immutable client.
+func (c *Client) clone() *Client {
+	clone := *c
+
+	clone.Headers = maps.Clone(c.Headers)
+	clone.QueryParams = maps.Clone(c.QueryParams)
+	clone.Codecs = maps.Clone(c.Codecs)
+	clone.configErrs = slices.Clip(c.configErrs)
+	clone.cacheStats = &cacheCounters{}
+	clone.stats = &statsCounters{}
+	clone.events = c.events.clone()
+	clone.debug = c.debug.clone()
+	clone.queue = newWorkQueue(c.queue.workers, cap(c.queue.items))
+	clone.deliveries = &deliveryQueues{}
+
+	if c.inflight != nil {
+		clone.inflight = &flightGroup{calls: make(map[string]*flightCall)}
+	}
+
+	if c.concurrency != nil {
+		clone.concurrency = c.concurrency.clone()
+	}
+
+	if c.bulkheads != nil {
+		clone.bulkheads = make(map[string]*bulkhead, len(c.bulkheads))
+		for name, b := range c.bulkheads {
+			clone.bulkheads[name] = &bulkhead{name: b.name, slots: make(chan struct{}, cap(b.slots)), maxQueued: b.maxQueued}
+		}
+	}
+
+	if c.hostPolicy != nil {
+		clone.hostPolicy = &hostPolicy{allowed: slices.Clip(c.hostPolicy.allowed), blocked: slices.Clip(c.hostPolicy.blocked)}
+	}
+
+	if c.rootCAs != nil {
+		clone.rootCAs = &rootCAs{pemCerts: slices.Clip(c.rootCAs.pemCerts), withoutSystem: c.rootCAs.withoutSystem}
+	}
+
+	if c.endpoints != nil {
+		clone.endpoints = c.endpoints.clone()
+	}
+
+	if c.health != nil {
+		clone.health = &healthChecker{check: c.health.check, stop: make(chan struct{})}
+	}
+
+	client := *c.HttpClient
+	clone.HttpClient = &client
+
+	if c.transport != nil {
+		dialer := *c.dialer
+		clone.dialer = &dialer
+		clone.transport = c.transport.Clone()
+		clone.transport.DialContext = trackConns(clone.dialContext, &clone.stats.openConns)
+
+		if pooled, ok := client.Transport.(*proxyPoolTransport); ok {
+			client.Transport = &proxyPoolTransport{base: clone.transport, pool: pooled.pool}
+		} else {
+			client.Transport = clone.transport
+		}
+	}
+
+	if c.redirectHook {
+		client.CheckRedirect = clone.checkRedirect
+	}
+
+	return &clone
+}
+
+// With returns a copy of the client (see Clone) with the options applied, leaving the original
+// client unchanged. It can be used to derive clients with additional headers or a different retry
+// policy from a shared base client.
+func (c *Client) With(opts ...Option) *Client {
+	clone := c.config().clone()
+	for _, opt := range opts {
+		opt(clone)
+	}
+
+	clone.bindClock()
+
+	if clone.immutable {
+		clone.freeze()
+	}
+
+	return clone
+}
+
+func (b *eventBus) clone() *eventBus {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	return &eventBus{nextID: b.nextID, handlers: slices.Clip(b.handlers)}
+}
+
+func (d *debugDumper) clone() *debugDumper {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	clone := &debugDumper{w: d.w}
+	clone.enabled.Store(d.enabled.Load())
+	clone.maxBody.Store(d.maxBody.Load())
+
+	return clone
+}
+
+func (l *concurrencyLimiter) clone() *concurrencyLimiter {
+	clone := &concurrencyLimiter{perHost: l.perHost, hosts: make(map[string]*hostSlots)}
+	clone.setGlobal(cap(l.global))
+
+	return clone
+}
+
+// clone returns a copy of the set with the same endpoints, whose health and outlier state is
+// shared, and its own background discovery.
+func (s *endpointSet) clone() *endpointSet {
+	clone := &endpointSet{
+		statuses:  s.statuses,
+		balancer:  s.balancer,
+		outliers:  s.outliers,
+		endpoints: s.list(),
+	}
+
+	if s.discovery != nil {
+		clone.discovery = &discovery{discoverer: s.discovery.discoverer, interval: s.discovery.interval, stop: make(chan struct{})}
+	}
+
+	return clone
+}
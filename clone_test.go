@@ -0,0 +1,139 @@
+package clink_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/davesavic/clink"
+)
+
+func TestClientWith(t *testing.T) {
+	var failures atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/redirect":
+			http.Redirect(w, r, "/", http.StatusFound)
+		case "/flaky":
+			if failures.Add(1)%2 == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}
+		default:
+			_, _ = w.Write([]byte(r.Header.Get("X-Tenant")))
+		}
+	}))
+	defer server.Close()
+
+	retry := func(_ *http.Request, resp *http.Response, err error) bool {
+		return err != nil || resp.StatusCode == http.StatusServiceUnavailable
+	}
+
+	testCases := []struct {
+		name       string
+		opts       []clink.Option
+		path       string
+		resultFunc func(original, derived *clink.Response, originalErr, derivedErr error) bool
+	}{
+		{
+			name: "header",
+			opts: []clink.Option{clink.WithHeader("X-Tenant", "acme")},
+			resultFunc: func(original, derived *clink.Response, originalErr, derivedErr error) bool {
+				return originalErr == nil && derivedErr == nil && original.String() == "base" && derived.String() == "acme"
+			},
+		},
+		{
+			name: "redirect policy",
+			opts: []clink.Option{clink.WithRedirectPolicy(0, false)},
+			path: "/redirect",
+			resultFunc: func(original, derived *clink.Response, originalErr, derivedErr error) bool {
+				return originalErr == nil && derivedErr == nil &&
+					original.StatusCode == http.StatusOK && derived.StatusCode == http.StatusFound
+			},
+		},
+		{
+			name: "retry policy",
+			opts: []clink.Option{clink.WithRetries(1, retry)},
+			path: "/flaky",
+			resultFunc: func(original, derived *clink.Response, originalErr, derivedErr error) bool {
+				return originalErr == nil && derivedErr == nil &&
+					original.StatusCode == http.StatusServiceUnavailable && derived.StatusCode == http.StatusOK
+			},
+		},
+		{
+			name: "transport",
+			opts: []clink.Option{clink.WithSSRFProtection()},
+			resultFunc: func(original, derived *clink.Response, originalErr, derivedErr error) bool {
+				return originalErr == nil && errors.Is(derivedErr, clink.ErrBlockedAddress)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			failures.Store(0)
+			base := clink.NewClient(clink.WithBaseURL(server.URL), clink.WithHeader("X-Tenant", "base"))
+			derived := base.With(tc.opts...)
+
+			path := tc.path
+			if path == "" {
+				path = "/"
+			}
+
+			derivedResp, derivedErr := doWrapped(derived, path)
+			originalResp, originalErr := doWrapped(base, path)
+			if !tc.resultFunc(originalResp, derivedResp, originalErr, derivedErr) {
+				t.Errorf("unexpected result: %v, %v", originalErr, derivedErr)
+			}
+		})
+	}
+}
+
+func TestClientClone(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	base := clink.NewClient(clink.WithBaseURL(server.URL))
+
+	var baseEvents, cloneEvents atomic.Int32
+	base.Subscribe(func(clink.Event) { baseEvents.Add(1) })
+
+	clone := base.Clone()
+	clone.Headers["X-Tenant"] = "acme"
+	clone.Subscribe(func(clink.Event) { cloneEvents.Add(1) })
+
+	if _, ok := base.Headers["X-Tenant"]; ok {
+		t.Errorf("expected headers of the original client to be unchanged")
+	}
+
+	if _, err := doWrapped(clone, "/"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if base.Stats().Requests != 0 || clone.Stats().Requests != 1 {
+		t.Errorf("expected stats to be independent, got %d and %d", base.Stats().Requests, clone.Stats().Requests)
+	}
+
+	if baseEvents.Load() == 0 || cloneEvents.Load() == 0 {
+		t.Errorf("expected subscriptions of the original to be copied")
+	}
+
+	baseBefore, cloneBefore := baseEvents.Load(), cloneEvents.Load()
+	if _, err := doWrapped(base, "/"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if baseEvents.Load() == baseBefore || cloneEvents.Load() != cloneBefore {
+		t.Errorf("expected subscriptions of the clone not to receive events of the original")
+	}
+}
+
+func doWrapped(c *clink.Client, path string) (*clink.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.DoWrapped(req)
+}
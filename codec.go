@@ -0,0 +1,323 @@
+package clink
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Codec marshals and unmarshals request and response bodies for a content type.
+type Codec interface {
+	// ContentType returns the media type handled by the codec, for example application/json.
+	ContentType() string
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+}
+
+// JSONCodec encodes and decodes application/json bodies.
+type JSONCodec struct {
+	// DisallowUnknownFields rejects objects with keys that do not match a field of the target struct.
+	DisallowUnknownFields bool
+	// UseNumber decodes numbers in interface values as json.Number instead of float64.
+	UseNumber bool
+}
+
+func (JSONCodec) ContentType() string           { return "application/json" }
+func (JSONCodec) Marshal(v any) ([]byte, error) { return json.Marshal(v) }
+
+func (j JSONCodec) Unmarshal(data []byte, v any) error {
+	if !j.DisallowUnknownFields && !j.UseNumber {
+		return json.Unmarshal(data, v)
+	}
+
+	o := decodeOptions{disallowUnknownFields: j.DisallowUnknownFields, useNumber: j.UseNumber}
+	decoder := newJSONDecoder(bytes.NewReader(data), o)
+	if err := decoder.Decode(v); err != nil {
+		return err
+	}
+
+	if _, err := decoder.Token(); err != io.EOF {
+		return fmt.Errorf("unexpected data after top-level value")
+	}
+
+	return nil
+}
+
+// WithJSONCodec replaces the JSON implementation used by the client with the given marshal and
+// unmarshal functions, for example those of jsoniter, sonic or go-json. It is used to encode the
+// bodies of PostJSON, PutJSON, PatchJSON and Send, and to decode JSON responses with Decode,
+// ResponseToJson and Response.JSON. The DisallowUnknownFields and UseNumber decode options don't
+// apply to a replaced implementation, and StreamJSON always uses encoding/json.
+func WithJSONCodec(marshal func(v any) ([]byte, error), unmarshal func(data []byte, v any) error) Option {
+	return func(c *Client) {
+		if marshal == nil || unmarshal == nil {
+			c.addConfigError("WithJSONCodec: marshal and unmarshal must not be nil")
+			return
+		}
+
+		c.Codecs["application/json"] = jsonFuncCodec{marshal: marshal, unmarshal: unmarshal}
+	}
+}
+
+// jsonFuncCodec is an application/json codec set with WithJSONCodec.
+type jsonFuncCodec struct {
+	marshal   func(v any) ([]byte, error)
+	unmarshal func(data []byte, v any) error
+}
+
+func (jsonFuncCodec) ContentType() string                  { return "application/json" }
+func (j jsonFuncCodec) Marshal(v any) ([]byte, error)      { return j.marshal(v) }
+func (j jsonFuncCodec) Unmarshal(data []byte, v any) error { return j.unmarshal(data, v) }
+
+type jsonCodecContextKey struct{}
+
+// contextWithJSONCodec returns a copy of the context carrying the JSON codec of the client, so that
+// the responses to the request can be decoded with it.
+func contextWithJSONCodec(ctx context.Context, codec jsonFuncCodec) context.Context {
+	return context.WithValue(ctx, jsonCodecContextKey{}, codec)
+}
+
+// decodeJSON decodes the JSON body of the response into the target, with the codec set with
+// WithJSONCodec on the client that sent the request if there is one.
+func decodeJSON(response *http.Response, body io.Reader, target any, opts []DecodeOption) error {
+	if response.Request != nil {
+		if codec, ok := response.Request.Context().Value(jsonCodecContextKey{}).(jsonFuncCodec); ok {
+			data, err := io.ReadAll(body)
+			if err != nil {
+				return fmt.Errorf("failed to read response body: %w", err)
+			}
+
+			if err := codec.Unmarshal(data, target); err != nil {
+				return fmt.Errorf("failed to decode response: %w", err)
+			}
+
+			return nil
+		}
+	}
+
+	if err := newJSONDecoder(body, newDecodeOptions(opts)).Decode(target); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return nil
+}
+
+// XMLCodec encodes and decodes application/xml bodies.
+type XMLCodec struct{}
+
+func (XMLCodec) ContentType() string                { return "application/xml" }
+func (XMLCodec) Marshal(v any) ([]byte, error)      { return xml.Marshal(v) }
+func (XMLCodec) Unmarshal(data []byte, v any) error { return xml.Unmarshal(data, v) }
+
+// FormCodec encodes and decodes application/x-www-form-urlencoded bodies
+// from and into url.Values, map[string][]string or map[string]string.
+type FormCodec struct{}
+
+func (FormCodec) ContentType() string { return "application/x-www-form-urlencoded" }
+
+func (FormCodec) Marshal(v any) ([]byte, error) {
+	switch t := v.(type) {
+	case url.Values:
+		return []byte(t.Encode()), nil
+	case map[string][]string:
+		return []byte(url.Values(t).Encode()), nil
+	case map[string]string:
+		values := url.Values{}
+		for key, value := range t {
+			values.Set(key, value)
+		}
+		return []byte(values.Encode()), nil
+	}
+
+	return nil, fmt.Errorf("cannot encode %T as form", v)
+}
+
+func (FormCodec) Unmarshal(data []byte, v any) error {
+	values, err := url.ParseQuery(string(data))
+	if err != nil {
+		return err
+	}
+
+	switch t := v.(type) {
+	case *url.Values:
+		*t = values
+	case *map[string][]string:
+		*t = values
+	case *map[string]string:
+		*t = make(map[string]string, len(values))
+		for key := range values {
+			(*t)[key] = values.Get(key)
+		}
+	default:
+		return fmt.Errorf("cannot decode form into %T", v)
+	}
+
+	return nil
+}
+
+// TextCodec encodes and decodes text/plain bodies from and into string or []byte.
+type TextCodec struct{}
+
+func (TextCodec) ContentType() string { return "text/plain" }
+
+func (TextCodec) Marshal(v any) ([]byte, error) {
+	switch t := v.(type) {
+	case string:
+		return []byte(t), nil
+	case []byte:
+		return t, nil
+	}
+
+	return nil, fmt.Errorf("cannot encode %T as text", v)
+}
+
+func (TextCodec) Unmarshal(data []byte, v any) error {
+	switch t := v.(type) {
+	case *string:
+		*t = string(data)
+	case *[]byte:
+		*t = data
+	default:
+		return fmt.Errorf("cannot decode text into %T", v)
+	}
+
+	return nil
+}
+
+// YAMLCodec encodes and decodes application/yaml bodies.
+type YAMLCodec struct{}
+
+func (YAMLCodec) ContentType() string                { return "application/yaml" }
+func (YAMLCodec) Marshal(v any) ([]byte, error)      { return yaml.Marshal(v) }
+func (YAMLCodec) Unmarshal(data []byte, v any) error { return yaml.Unmarshal(data, v) }
+
+// defaultCodecs is the codec registry used by DecodeResponse and new clients.
+var defaultCodecs = newCodecRegistry(JSONCodec{}, XMLCodec{}, YAMLCodec{}, FormCodec{}, TextCodec{})
+
+func newCodecRegistry(codecs ...Codec) map[string]Codec {
+	registry := make(map[string]Codec, len(codecs))
+	for _, codec := range codecs {
+		registry[codec.ContentType()] = codec
+	}
+	return registry
+}
+
+// WithCodec registers a codec for its content type, replacing any codec registered for the same content type.
+func WithCodec(codec Codec) Option {
+	return func(c *Client) {
+		if codec == nil {
+			c.addConfigError("WithCodec: codec must not be nil")
+			return
+		}
+
+		c.Codecs[codec.ContentType()] = codec
+	}
+}
+
+// acceptHeader returns the Accept header for a response decoded into the target with the codecs:
+// text/plain for strings and byte slices, the form content type for url.Values, and otherwise the
+// content types of the other codecs, preferring application/json.
+func acceptHeader(codecs map[string]Codec, target any) string {
+	var contentType string
+	switch target.(type) {
+	case *string, *[]byte:
+		contentType = "text/plain"
+	case *url.Values:
+		contentType = "application/x-www-form-urlencoded"
+	}
+
+	if _, ok := codecs[contentType]; ok {
+		return contentType
+	}
+
+	var others []string
+	for mediaType := range codecs {
+		if mediaType != "application/json" && mediaType != "text/plain" && mediaType != "application/x-www-form-urlencoded" {
+			others = append(others, mediaType+";q=0.9")
+		}
+	}
+	slices.Sort(others)
+
+	if _, ok := codecs["application/json"]; ok {
+		others = append([]string{"application/json"}, others...)
+	}
+
+	return strings.Join(others, ", ")
+}
+
+// lookupCodec returns the codec registered for the content type.
+// Structured syntax suffixes (+json, +xml, +yaml), legacy YAML types and text/* types fall back to their generic codecs.
+func lookupCodec(codecs map[string]Codec, contentType string) Codec {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil
+	}
+
+	if codec, ok := codecs[mediaType]; ok {
+		return codec
+	}
+
+	switch {
+	case strings.HasSuffix(mediaType, "+json"):
+		return codecs["application/json"]
+	case strings.HasSuffix(mediaType, "+xml") || mediaType == "text/xml":
+		return codecs["application/xml"]
+	case strings.HasSuffix(mediaType, "+yaml") || mediaType == "application/x-yaml" || mediaType == "text/yaml":
+		return codecs["application/yaml"]
+	case strings.HasPrefix(mediaType, "text/"):
+		return codecs["text/plain"]
+	}
+
+	return nil
+}
+
+// Decode decodes the response body into the target using the codec registered for the response Content-Type.
+func (c *Client) Decode(response *http.Response, target any, opts ...DecodeOption) error {
+	return decodeResponse(c.config().Codecs, response, target, opts)
+}
+
+// Send encodes the body with the codec registered for the content type and sends it to the given URL.
+func (c *Client) Send(method, url, contentType string, body any) (*http.Response, error) {
+	codec := lookupCodec(c.config().Codecs, contentType)
+	if codec == nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedContentType, contentType)
+	}
+
+	data, err := codec.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode request body: %w", err)
+	}
+
+	req, err := http.NewRequest(method, url, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	return c.Do(req)
+}
+
+// PostJSON sends a POST request to the given URL with the body encoded as JSON.
+func (c *Client) PostJSON(url string, body any) (*http.Response, error) {
+	return c.Send(http.MethodPost, url, "application/json", body)
+}
+
+// PutJSON sends a PUT request to the given URL with the body encoded as JSON.
+func (c *Client) PutJSON(url string, body any) (*http.Response, error) {
+	return c.Send(http.MethodPut, url, "application/json", body)
+}
+
+// PatchJSON sends a PATCH request to the given URL with the body encoded as JSON.
+func (c *Client) PatchJSON(url string, body any) (*http.Response, error) {
+	return c.Send(http.MethodPatch, url, "application/json", body)
+}
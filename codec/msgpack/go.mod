@@ -0,0 +1,14 @@
+module github.com/davesavic/clink/codec/msgpack
+
+go 1.23
+
+require (
+	github.com/davesavic/clink v0.1.0
+	github.com/vmihailenco/msgpack/v5 v5.4.1
+)
+
+require (
+	github.com/vmihailenco/tagparser/v2 v2.0.0 // indirect
+	golang.org/x/time v0.5.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
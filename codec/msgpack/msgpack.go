@@ -0,0 +1,26 @@
+// Package msgpack provides a clink codec for MessagePack request and response bodies.
+//
+// Register it on a client with clink.WithCodec(msgpack.Codec{}).
+package msgpack
+
+import (
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// ContentType is the media type handled by the codec.
+const ContentType = "application/msgpack"
+
+// Codec encodes and decodes MessagePack bodies.
+type Codec struct{}
+
+func (Codec) ContentType() string {
+	return ContentType
+}
+
+func (Codec) Marshal(v any) ([]byte, error) {
+	return msgpack.Marshal(v)
+}
+
+func (Codec) Unmarshal(data []byte, v any) error {
+	return msgpack.Unmarshal(data, v)
+}
@@ -0,0 +1,40 @@
+package msgpack_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/davesavic/clink"
+	"github.com/davesavic/clink/codec/msgpack"
+)
+
+func TestCodec(t *testing.T) {
+	type item struct {
+		Key   string `msgpack:"key"`
+		Count int    `msgpack:"count"`
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", r.Header.Get("Content-Type"))
+		_, _ = io.Copy(w, r.Body)
+	}))
+	defer server.Close()
+
+	client := clink.NewClient(clink.WithClient(server.Client()), clink.WithCodec(msgpack.Codec{}))
+
+	resp, err := client.Send(http.MethodPost, server.URL, msgpack.ContentType, item{Key: "value", Count: 2})
+	if err != nil {
+		t.Fatalf("failed to make request: %v", err)
+	}
+
+	var target item
+	if err := client.Decode(resp, &target); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if target.Key != "value" || target.Count != 2 {
+		t.Errorf("unexpected decoded value: %+v", target)
+	}
+}
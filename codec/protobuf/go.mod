@@ -0,0 +1,13 @@
+module github.com/davesavic/clink/codec/protobuf
+
+go 1.23
+
+require (
+	github.com/davesavic/clink v0.1.0
+	google.golang.org/protobuf v1.36.12
+)
+
+require (
+	golang.org/x/time v0.5.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
@@ -0,0 +1,39 @@
+// Package protobuf provides a clink codec for protocol buffer request and response bodies.
+//
+// Register it on a client with clink.WithCodec(protobuf.Codec{}).
+package protobuf
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// ContentType is the media type handled by the codec.
+const ContentType = "application/x-protobuf"
+
+// Codec encodes and decodes protocol buffer messages.
+// Values passed to Marshal and Unmarshal must implement proto.Message.
+type Codec struct{}
+
+func (Codec) ContentType() string {
+	return ContentType
+}
+
+func (Codec) Marshal(v any) ([]byte, error) {
+	message, ok := v.(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf("cannot encode %T as protobuf: not a proto.Message", v)
+	}
+
+	return proto.Marshal(message)
+}
+
+func (Codec) Unmarshal(data []byte, v any) error {
+	message, ok := v.(proto.Message)
+	if !ok {
+		return fmt.Errorf("cannot decode protobuf into %T: not a proto.Message", v)
+	}
+
+	return proto.Unmarshal(data, message)
+}
@@ -0,0 +1,47 @@
+package protobuf_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/davesavic/clink"
+	"github.com/davesavic/clink/codec/protobuf"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestCodec(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", r.Header.Get("Content-Type"))
+		_, _ = io.Copy(w, r.Body)
+	}))
+	defer server.Close()
+
+	client := clink.NewClient(clink.WithClient(server.Client()), clink.WithCodec(protobuf.Codec{}))
+
+	resp, err := client.Send(http.MethodPost, server.URL, protobuf.ContentType, wrapperspb.String("value"))
+	if err != nil {
+		t.Fatalf("failed to make request: %v", err)
+	}
+
+	target := &wrapperspb.StringValue{}
+	if err := client.Decode(resp, target); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if target.GetValue() != "value" {
+		t.Errorf("expected value, got: %s", target.GetValue())
+	}
+}
+
+func TestCodec_RejectsNonMessages(t *testing.T) {
+	if _, err := (protobuf.Codec{}).Marshal("value"); err == nil {
+		t.Errorf("expected marshal error for non message")
+	}
+
+	var target string
+	if err := (protobuf.Codec{}).Unmarshal(nil, &target); err == nil {
+		t.Errorf("expected unmarshal error for non message")
+	}
+}
@@ -0,0 +1,233 @@
+package clink_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/davesavic/clink"
+)
+
+// upperCodec is a custom codec that upper-cases text bodies.
+type upperCodec struct{}
+
+func (upperCodec) ContentType() string { return "application/x-upper" }
+
+func (upperCodec) Marshal(v any) ([]byte, error) {
+	return []byte(strings.ToUpper(v.(string))), nil
+}
+
+func (upperCodec) Unmarshal(data []byte, v any) error {
+	*(v.(*string)) = strings.ToLower(string(data))
+	return nil
+}
+
+func TestCodecs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", r.Header.Get("Content-Type"))
+		_, _ = io.Copy(w, r.Body)
+	}))
+	defer server.Close()
+
+	client := clink.NewClient(
+		clink.WithClient(server.Client()),
+		clink.WithCodec(upperCodec{}),
+	)
+
+	testCases := []struct {
+		name       string
+		resultFunc func() bool
+	}{
+		{
+			name: "post json round trip",
+			resultFunc: func() bool {
+				resp, err := client.PostJSON(server.URL, map[string]string{"key": "value"})
+				if err != nil {
+					return false
+				}
+
+				var target map[string]string
+				err = client.Decode(resp, &target)
+				return err == nil && target["key"] == "value"
+			},
+		},
+		{
+			name: "put xml round trip",
+			resultFunc: func() bool {
+				type item struct {
+					Key string `xml:"key"`
+				}
+
+				resp, err := client.Send(http.MethodPut, server.URL, "application/xml", item{Key: "value"})
+				if err != nil {
+					return false
+				}
+
+				var target item
+				err = client.Decode(resp, &target)
+				return err == nil && target.Key == "value"
+			},
+		},
+		{
+			name: "patch form round trip",
+			resultFunc: func() bool {
+				resp, err := client.Send(http.MethodPatch, server.URL, "application/x-www-form-urlencoded", map[string]string{"key": "value"})
+				if err != nil {
+					return false
+				}
+
+				var target map[string]string
+				err = client.Decode(resp, &target)
+				return err == nil && target["key"] == "value"
+			},
+		},
+		{
+			name: "custom codec round trip",
+			resultFunc: func() bool {
+				resp, err := client.Send(http.MethodPost, server.URL, "application/x-upper", "Value")
+				if err != nil {
+					return false
+				}
+
+				var target string
+				err = client.Decode(resp, &target)
+				return err == nil && target == "value"
+			},
+		},
+		{
+			name: "unregistered codec",
+			resultFunc: func() bool {
+				_, err := client.Send(http.MethodPost, server.URL, "application/x-unknown", "value")
+				return errors.Is(err, clink.ErrUnsupportedContentType)
+			},
+		},
+		{
+			name: "custom codec is not available to other clients",
+			resultFunc: func() bool {
+				_, err := clink.NewClient().Send(http.MethodPost, server.URL, "application/x-upper", "value")
+				return errors.Is(err, clink.ErrUnsupportedContentType)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if !tc.resultFunc() {
+				t.Errorf("expected result to be successful")
+			}
+		})
+	}
+}
+
+func TestWithCodec_Nil(t *testing.T) {
+	c := clink.NewClient(clink.WithCodec(nil))
+
+	if _, err := c.Get("http://example.com"); !errors.Is(err, clink.ErrInvalidOption) {
+		t.Errorf("expected ErrInvalidOption, got: %v", err)
+	}
+}
+
+func TestWithJSONCodec(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.Copy(w, r.Body)
+	}))
+	defer server.Close()
+
+	var marshalled, unmarshalled atomic.Int64
+	client := clink.NewClient(
+		clink.WithClient(server.Client()),
+		clink.WithJSONCodec(
+			func(v any) ([]byte, error) {
+				marshalled.Add(1)
+				return json.Marshal(v)
+			},
+			func(data []byte, v any) error {
+				unmarshalled.Add(1)
+				return json.Unmarshal(data, v)
+			},
+		),
+	)
+
+	testCases := []struct {
+		name       string
+		resultFunc func() bool
+	}{
+		{
+			name: "post json and decode",
+			resultFunc: func() bool {
+				resp, err := client.PostJSON(server.URL, map[string]string{"key": "value"})
+				if err != nil {
+					return false
+				}
+
+				var target map[string]string
+				err = client.Decode(resp, &target)
+				return err == nil && target["key"] == "value" && marshalled.Load() == 1 && unmarshalled.Load() == 1
+			},
+		},
+		{
+			name: "response to json",
+			resultFunc: func() bool {
+				resp, err := client.PutJSON(server.URL, map[string]string{"key": "value"})
+				if err != nil {
+					return false
+				}
+
+				var target map[string]string
+				err = clink.ResponseToJson(resp, &target)
+				return err == nil && target["key"] == "value" && unmarshalled.Load() == 1
+			},
+		},
+		{
+			name: "response json",
+			resultFunc: func() bool {
+				req, _ := http.NewRequest(http.MethodPost, server.URL, strings.NewReader(`{"key":"value"}`))
+				resp, err := client.DoWrapped(req)
+				if err != nil {
+					return false
+				}
+
+				var target map[string]string
+				err = resp.JSON(&target)
+				return err == nil && target["key"] == "value" && marshalled.Load() == 0 && unmarshalled.Load() == 1
+			},
+		},
+		{
+			name: "other clients use encoding/json",
+			resultFunc: func() bool {
+				resp, err := clink.NewClient(clink.WithClient(server.Client())).PostJSON(server.URL, map[string]string{"key": "value"})
+				if err != nil {
+					return false
+				}
+
+				var target map[string]string
+				err = clink.ResponseToJson(resp, &target)
+				return err == nil && target["key"] == "value" && marshalled.Load() == 0 && unmarshalled.Load() == 0
+			},
+		},
+		{
+			name: "nil functions",
+			resultFunc: func() bool {
+				_, err := clink.NewClient(clink.WithJSONCodec(nil, json.Unmarshal)).Get(server.URL)
+				return errors.Is(err, clink.ErrInvalidOption)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			marshalled.Store(0)
+			unmarshalled.Store(0)
+
+			if !tc.resultFunc() {
+				t.Errorf("expected result to be successful")
+			}
+		})
+	}
+}
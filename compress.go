@@ -0,0 +1,84 @@
+package clink
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// Compression is a content coding used to compress request bodies.
+type Compression string
+
+const (
+	// Gzip compresses request bodies with gzip.
+	Gzip Compression = "gzip"
+	// Deflate compresses request bodies with zlib, the "deflate" content coding of HTTP.
+	Deflate Compression = "deflate"
+)
+
+// WithRequestCompression compresses request bodies of at least minSize bytes with the given
+// compression and sets their Content-Encoding header, for servers accepting compressed uploads.
+// Bodies that already have a Content-Encoding, streamed bodies (see StreamBody and FileBody) and
+// bodies that don't get smaller are sent as they are.
+func WithRequestCompression(compression Compression, minSize int) Option {
+	return func(c *Client) {
+		if compression != Gzip && compression != Deflate {
+			c.addConfigError("WithRequestCompression: unsupported compression %q", compression)
+			return
+		}
+
+		if minSize < 0 {
+			c.addConfigError("WithRequestCompression: minimum size must not be negative, got %d", minSize)
+			return
+		}
+
+		c.compression = &requestCompression{compression: compression, minSize: minSize}
+	}
+}
+
+type requestCompression struct {
+	compression Compression
+	minSize     int
+}
+
+// compress returns a copy of the request with the headers of the compressed body, and the
+// compressed body. It returns the request and body unchanged if the body isn't compressed.
+func (rc *requestCompression) compress(req *http.Request, body []byte) (*http.Request, []byte, error) {
+	if len(body) < rc.minSize || req.Header.Get("Content-Encoding") != "" {
+		return req, body, nil
+	}
+
+	var buf bytes.Buffer
+	var w io.WriteCloser
+	switch rc.compression {
+	case Deflate:
+		w = zlib.NewWriter(&buf)
+	default:
+		w = gzip.NewWriter(&buf)
+	}
+
+	if _, err := w.Write(body); err != nil {
+		return nil, nil, fmt.Errorf("failed to compress request body: %w", err)
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, nil, fmt.Errorf("failed to compress request body: %w", err)
+	}
+
+	if buf.Len() >= len(body) {
+		return req, body, nil
+	}
+
+	compressed := buf.Bytes()
+	req = req.Clone(req.Context())
+	req.Header.Set("Content-Encoding", string(rc.compression))
+	req.ContentLength = int64(len(compressed))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(compressed)), nil
+	}
+
+	return req, compressed, nil
+}
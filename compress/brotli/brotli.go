@@ -0,0 +1,24 @@
+// Package brotli provides a clink content decoder for brotli response bodies.
+//
+// Register it on a client with clink.WithDecompression(clink.GzipDecoder{}, brotli.Decoder{}).
+package brotli
+
+import (
+	"io"
+
+	"github.com/andybalholm/brotli"
+)
+
+// Encoding is the content coding handled by the decoder.
+const Encoding = "br"
+
+// Decoder decodes brotli response bodies.
+type Decoder struct{}
+
+func (Decoder) Encoding() string {
+	return Encoding
+}
+
+func (Decoder) NewReader(r io.Reader) (io.ReadCloser, error) {
+	return io.NopCloser(brotli.NewReader(r)), nil
+}
@@ -0,0 +1,44 @@
+package brotli_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	abrotli "github.com/andybalholm/brotli"
+	"github.com/davesavic/clink"
+	"github.com/davesavic/clink/compress/brotli"
+)
+
+func TestDecoder(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept-Encoding") != "gzip, br" {
+			w.WriteHeader(http.StatusNotAcceptable)
+			return
+		}
+
+		w.Header().Set("Content-Encoding", brotli.Encoding)
+		writer := abrotli.NewWriter(w)
+		_, _ = writer.Write([]byte("hello brotli"))
+		_ = writer.Close()
+	}))
+	defer server.Close()
+
+	c := clink.NewClient(clink.WithDecompression(clink.GzipDecoder{}, brotli.Decoder{}))
+
+	resp, err := c.Get(server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("failed to read body: %v", err)
+	}
+
+	if string(body) != "hello brotli" {
+		t.Errorf("expected decoded body, got %q (status %d)", body, resp.StatusCode)
+	}
+}
@@ -0,0 +1,13 @@
+module github.com/davesavic/clink/compress/brotli
+
+go 1.23
+
+require (
+	github.com/andybalholm/brotli v1.1.1
+	github.com/davesavic/clink v0.1.0
+)
+
+require (
+	golang.org/x/time v0.5.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
@@ -0,0 +1,13 @@
+module github.com/davesavic/clink/compress/zstd
+
+go 1.23
+
+require (
+	github.com/davesavic/clink v0.1.0
+	github.com/klauspost/compress v1.17.9
+)
+
+require (
+	golang.org/x/time v0.5.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
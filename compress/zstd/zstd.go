@@ -0,0 +1,29 @@
+// Package zstd provides a clink content decoder for zstd response bodies.
+//
+// Register it on a client with clink.WithDecompression(zstd.Decoder{}, clink.GzipDecoder{}).
+package zstd
+
+import (
+	"io"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Encoding is the content coding handled by the decoder.
+const Encoding = "zstd"
+
+// Decoder decodes zstd response bodies.
+type Decoder struct{}
+
+func (Decoder) Encoding() string {
+	return Encoding
+}
+
+func (Decoder) NewReader(r io.Reader) (io.ReadCloser, error) {
+	decoder, err := zstd.NewReader(r, zstd.WithDecoderConcurrency(1))
+	if err != nil {
+		return nil, err
+	}
+
+	return decoder.IOReadCloser(), nil
+}
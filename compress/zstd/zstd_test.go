@@ -0,0 +1,43 @@
+package zstd_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/davesavic/clink"
+	"github.com/davesavic/clink/compress/zstd"
+	kzstd "github.com/klauspost/compress/zstd"
+)
+
+func TestDecoder(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept-Encoding") != "zstd, gzip" {
+			w.WriteHeader(http.StatusNotAcceptable)
+			return
+		}
+
+		encoder, _ := kzstd.NewWriter(nil)
+		w.Header().Set("Content-Encoding", zstd.Encoding)
+		_, _ = w.Write(encoder.EncodeAll([]byte("hello zstd"), nil))
+	}))
+	defer server.Close()
+
+	c := clink.NewClient(clink.WithDecompression(zstd.Decoder{}, clink.GzipDecoder{}))
+
+	resp, err := c.Get(server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("failed to read body: %v", err)
+	}
+
+	if string(body) != "hello zstd" {
+		t.Errorf("expected decoded body, got %q (status %d)", body, resp.StatusCode)
+	}
+}
@@ -0,0 +1,105 @@
+package clink_test
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/davesavic/clink"
+)
+
+func TestWithRequestCompression(t *testing.T) {
+	large := strings.Repeat(`{"name": "clink"}`, 100)
+
+	testCases := []struct {
+		name       string
+		opt        clink.Option
+		body       string
+		encoding   string
+		resultFunc func(encoding, body string, err error) bool
+	}{
+		{
+			name: "gzip",
+			opt:  clink.WithRequestCompression(clink.Gzip, 1024),
+			body: large,
+			resultFunc: func(encoding, body string, err error) bool {
+				return err == nil && encoding == "gzip" && body == large
+			},
+		},
+		{
+			name: "deflate",
+			opt:  clink.WithRequestCompression(clink.Deflate, 1024),
+			body: large,
+			resultFunc: func(encoding, body string, err error) bool {
+				return err == nil && encoding == "deflate" && body == large
+			},
+		},
+		{
+			name: "below minimum size",
+			opt:  clink.WithRequestCompression(clink.Gzip, 1024),
+			body: `{"name": "clink"}`,
+			resultFunc: func(encoding, body string, err error) bool {
+				return err == nil && encoding == "" && body == `{"name": "clink"}`
+			},
+		},
+		{
+			name:     "already encoded",
+			opt:      clink.WithRequestCompression(clink.Gzip, 0),
+			body:     large,
+			encoding: "identity",
+			resultFunc: func(encoding, body string, err error) bool {
+				return err == nil && encoding == "identity" && body == large
+			},
+		},
+		{
+			name: "unsupported compression",
+			opt:  clink.WithRequestCompression("br", 0),
+			body: large,
+			resultFunc: func(_, _ string, err error) bool {
+				return errors.Is(err, clink.ErrInvalidOption)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var encoding, body string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				encoding = r.Header.Get("Content-Encoding")
+
+				var reader io.Reader = r.Body
+				switch encoding {
+				case "gzip":
+					reader, _ = gzip.NewReader(r.Body)
+				case "deflate":
+					reader, _ = zlib.NewReader(r.Body)
+				}
+
+				data, _ := io.ReadAll(reader)
+				body = string(data)
+			}))
+			defer server.Close()
+
+			c := clink.NewClient(tc.opt)
+
+			req, _ := http.NewRequest(http.MethodPost, server.URL, strings.NewReader(tc.body))
+			if tc.encoding != "" {
+				req.Header.Set("Content-Encoding", tc.encoding)
+			}
+
+			resp, err := c.Do(req)
+			if err == nil {
+				_ = resp.Body.Close()
+			}
+
+			if !tc.resultFunc(encoding, body, err) {
+				t.Errorf("unexpected result: %q, %d bytes, %v", encoding, len(body), err)
+			}
+		})
+	}
+}
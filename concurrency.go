@@ -0,0 +1,202 @@
+package clink
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// WithMaxConcurrency limits the number of requests the client has in flight across all hosts.
+// A request holds its slot until its response body is closed. A limit of zero disables it.
+func WithMaxConcurrency(n int) Option {
+	return func(c *Client) {
+		if n < 0 {
+			c.addConfigError("WithMaxConcurrency: limit must not be negative, got %d", n)
+			return
+		}
+
+		c.concurrencyLimiter().setGlobal(n)
+	}
+}
+
+// WithMaxConcurrencyPerHost limits the number of requests the client has in flight to each
+// destination host, so that a slow host can't use up the slots shared by all hosts. Requests waiting
+// for a host slot don't hold a slot of the global limit set with WithMaxConcurrency.
+// A limit of zero disables it.
+func WithMaxConcurrencyPerHost(n int) Option {
+	return func(c *Client) {
+		if n < 0 {
+			c.addConfigError("WithMaxConcurrencyPerHost: limit must not be negative, got %d", n)
+			return
+		}
+
+		c.concurrencyLimiter().perHost = n
+	}
+}
+
+func (c *Client) concurrencyLimiter() *concurrencyLimiter {
+	if c.concurrency == nil {
+		c.concurrency = &concurrencyLimiter{hosts: make(map[string]*hostSlots)}
+	}
+
+	return c.concurrency
+}
+
+// concurrencyLimiter bounds the requests in flight globally and per host.
+type concurrencyLimiter struct {
+	global  chan struct{}
+	perHost int
+
+	mu    sync.Mutex
+	hosts map[string]*hostSlots
+}
+
+// hostSlots is the semaphore of a host, removed from the limiter once no request uses it.
+type hostSlots struct {
+	slots chan struct{}
+	refs  int
+}
+
+func (l *concurrencyLimiter) setGlobal(n int) {
+	l.global = nil
+	if n > 0 {
+		l.global = make(chan struct{}, n)
+	}
+}
+
+// acquire waits for a slot for the host, then for a global slot, and returns the function
+// releasing them.
+func (l *concurrencyLimiter) acquire(ctx context.Context, host string) (func(), error) {
+	releaseHost, err := l.acquireHost(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+
+	if l.global != nil {
+		select {
+		case l.global <- struct{}{}:
+		case <-ctx.Done():
+			releaseHost()
+			return nil, ctx.Err()
+		}
+	}
+
+	return func() {
+		if l.global != nil {
+			<-l.global
+		}
+		releaseHost()
+	}, nil
+}
+
+func (l *concurrencyLimiter) acquireHost(ctx context.Context, host string) (func(), error) {
+	if l.perHost <= 0 {
+		return func() {}, nil
+	}
+
+	l.mu.Lock()
+	h, ok := l.hosts[host]
+	if !ok {
+		h = &hostSlots{slots: make(chan struct{}, l.perHost)}
+		l.hosts[host] = h
+	}
+	h.refs++
+	l.mu.Unlock()
+
+	unref := func() {
+		l.mu.Lock()
+		h.refs--
+		if h.refs == 0 {
+			delete(l.hosts, host)
+		}
+		l.mu.Unlock()
+	}
+
+	select {
+	case h.slots <- struct{}{}:
+	case <-ctx.Done():
+		unref()
+		return nil, ctx.Err()
+	}
+
+	return func() {
+		<-h.slots
+		unref()
+	}, nil
+}
+
+// releaseBody releases the concurrency slots of a request when its response body is closed.
+type releaseBody struct {
+	io.ReadCloser
+	release func()
+	once    sync.Once
+}
+
+func (b *releaseBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.release)
+
+	return err
+}
+
+// roundTrip sends a single attempt of the request with the HTTP client, holding a slot of its
+// bulkhead and of the concurrency limits until the response body is closed, unless its circuit
+// breaker is open.
+func (c *Client) roundTrip(req *http.Request) (*http.Response, error) {
+	release, err := c.acquireSlots(req)
+	if err != nil {
+		return nil, err
+	}
+
+	req, decode := c.decompression.prepare(req)
+	resp, err := c.breakRoundTrip(req)
+	if decode && err == nil {
+		c.decompression.decode(resp)
+	}
+	if release == nil {
+		return resp, err
+	}
+
+	if err != nil {
+		release()
+		return nil, err
+	}
+
+	resp.Body = &releaseBody{ReadCloser: resp.Body, release: release}
+
+	return resp, nil
+}
+
+// acquireSlots waits for a slot of the bulkhead of the request, then for the concurrency limits,
+// and returns the function releasing them, or nil if the request isn't limited.
+func (c *Client) acquireSlots(req *http.Request) (func(), error) {
+	b := c.bulkheadFor(req.Context())
+	if b == nil && c.concurrency == nil {
+		return nil, nil
+	}
+
+	releaseBulkhead := func() {}
+	if b != nil {
+		release, err := b.acquire(req.Context())
+		if err != nil {
+			return nil, err
+		}
+		releaseBulkhead = release
+	}
+
+	if c.concurrency == nil {
+		return releaseBulkhead, nil
+	}
+
+	release, err := c.concurrency.acquire(req.Context(), req.URL.Host)
+	if err != nil {
+		releaseBulkhead()
+		return nil, err
+	}
+
+	return func() {
+		release()
+		releaseBulkhead()
+	}, nil
+}
@@ -0,0 +1,501 @@
+package clink
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrInvalidOption is returned by every call of a client configured with an invalid option.
+var ErrInvalidOption = errors.New("invalid client option")
+
+// addConfigError records an error of an option, returned by every call of the client.
+func (c *Client) addConfigError(format string, args ...any) {
+	c.configErrs = append(c.configErrs, fmt.Errorf("%w: "+format, append([]any{ErrInvalidOption}, args...)...))
+}
+
+// configError returns the errors recorded by the options of the client, if any.
+func (c *Client) configError() error {
+	return errors.Join(c.configErrs...)
+}
+
+// DefaultConfigRetryStatuses are the response statuses retried by the preset clients, and by a
+// client created from a Config unless set with its RetryStatuses.
+var DefaultConfigRetryStatuses = []int{
+	http.StatusTooManyRequests,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// Config is a declarative configuration of a client, which can be unmarshaled from JSON or YAML
+// (see LoadConfig) so that the outbound HTTP policy of a service is defined with its other
+// settings. Each field corresponds to an option, and zero fields leave the client defaults
+// unchanged. Options taking functions or Go values, such as WithLogger, WithCodec or
+// WithTransport, have no field and are passed to NewClientFromConfig along with the config.
+type Config struct {
+	BaseURL     string            `json:"baseURL,omitempty" yaml:"baseURL,omitempty"`
+	Headers     map[string]string `json:"headers,omitempty" yaml:"headers,omitempty"`
+	QueryParams map[string]string `json:"queryParams,omitempty" yaml:"queryParams,omitempty"`
+	UserAgent   string            `json:"userAgent,omitempty" yaml:"userAgent,omitempty"`
+	BearerToken string            `json:"bearerToken,omitempty" yaml:"bearerToken,omitempty"`
+	BasicAuth   *BasicAuthConfig  `json:"basicAuth,omitempty" yaml:"basicAuth,omitempty"`
+	Timeout     Duration          `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	// RateLimit is the rate limit in requests per minute.
+	RateLimit int `json:"rateLimit,omitempty" yaml:"rateLimit,omitempty"`
+	// Retries is the number of times a request is retried when it can't be sent or its response
+	// has one of the RetryStatuses.
+	Retries int `json:"retries,omitempty" yaml:"retries,omitempty"`
+	// RetryStatuses are the response statuses retried, DefaultConfigRetryStatuses if it is empty.
+	RetryStatuses         []int            `json:"retryStatuses,omitempty" yaml:"retryStatuses,omitempty"`
+	MaxConcurrency        int              `json:"maxConcurrency,omitempty" yaml:"maxConcurrency,omitempty"`
+	MaxConcurrencyPerHost int              `json:"maxConcurrencyPerHost,omitempty" yaml:"maxConcurrencyPerHost,omitempty"`
+	Bulkheads             []BulkheadConfig `json:"bulkheads,omitempty" yaml:"bulkheads,omitempty"`
+	MaxRequestBytes       int64            `json:"maxRequestBytes,omitempty" yaml:"maxRequestBytes,omitempty"`
+	MaxResponseBytes      int64            `json:"maxResponseBytes,omitempty" yaml:"maxResponseBytes,omitempty"`
+	// ErrorOnStatus makes the client return an *HTTPError for responses with a status of 400 or above.
+	ErrorOnStatus        bool `json:"errorOnStatus,omitempty" yaml:"errorOnStatus,omitempty"`
+	IdempotencyKey       bool `json:"idempotencyKey,omitempty" yaml:"idempotencyKey,omitempty"`
+	RequestDeduplication bool `json:"requestDeduplication,omitempty" yaml:"requestDeduplication,omitempty"`
+	// RequestIDHeader is the header in which requests are stamped with a random UUID, if it is set.
+	RequestIDHeader string             `json:"requestIDHeader,omitempty" yaml:"requestIDHeader,omitempty"`
+	MemoryCache     *MemoryCacheConfig `json:"memoryCache,omitempty" yaml:"memoryCache,omitempty"`
+	// MaxRedirects is the maximum number of redirects followed, DefaultMaxRedirects if it is zero.
+	MaxRedirects           int      `json:"maxRedirects,omitempty" yaml:"maxRedirects,omitempty"`
+	DisableRedirects       bool     `json:"disableRedirects,omitempty" yaml:"disableRedirects,omitempty"`
+	RedirectStripHeaders   []string `json:"redirectStripHeaders,omitempty" yaml:"redirectStripHeaders,omitempty"`
+	RedirectForwardHeaders []string `json:"redirectForwardHeaders,omitempty" yaml:"redirectForwardHeaders,omitempty"`
+	// ReferrerPolicy is "full", "origin" or "none".
+	ReferrerPolicy string `json:"referrerPolicy,omitempty" yaml:"referrerPolicy,omitempty"`
+	// Endpoints are the endpoints of WithEndpoints, starting with the primary endpoint.
+	Endpoints        []string `json:"endpoints,omitempty" yaml:"endpoints,omitempty"`
+	FailoverStatuses []int    `json:"failoverStatuses,omitempty" yaml:"failoverStatuses,omitempty"`
+	// Balancer is "round-robin", "random" or "least-pending".
+	Balancer         string                  `json:"balancer,omitempty" yaml:"balancer,omitempty"`
+	HealthCheck      *HealthCheckConfig      `json:"healthCheck,omitempty" yaml:"healthCheck,omitempty"`
+	OutlierDetection *OutlierDetectionConfig `json:"outlierDetection,omitempty" yaml:"outlierDetection,omitempty"`
+	CircuitBreaker   *CircuitBreakerConfig   `json:"circuitBreaker,omitempty" yaml:"circuitBreaker,omitempty"`
+	Proxy            string                  `json:"proxy,omitempty" yaml:"proxy,omitempty"`
+	Proxies          []string                `json:"proxies,omitempty" yaml:"proxies,omitempty"`
+	// ProxyStrategy is "round-robin" or "random".
+	ProxyStrategy string `json:"proxyStrategy,omitempty" yaml:"proxyStrategy,omitempty"`
+	UnixSocket    string `json:"unixSocket,omitempty" yaml:"unixSocket,omitempty"`
+	HTTP2         *bool  `json:"http2,omitempty" yaml:"http2,omitempty"`
+	// DNSCacheTTL is how long looked up host names are cached.
+	DNSCacheTTL Duration `json:"dnsCacheTTL,omitempty" yaml:"dnsCacheTTL,omitempty"`
+	// IPFamily is "any", "prefer-ipv4", "prefer-ipv6", "ipv4-only" or "ipv6-only".
+	IPFamily              string            `json:"ipFamily,omitempty" yaml:"ipFamily,omitempty"`
+	RootCAFiles           []string          `json:"rootCAFiles,omitempty" yaml:"rootCAFiles,omitempty"`
+	SystemRoots           *bool             `json:"systemRoots,omitempty" yaml:"systemRoots,omitempty"`
+	ClientCertFile        string            `json:"clientCertFile,omitempty" yaml:"clientCertFile,omitempty"`
+	ClientKeyFile         string            `json:"clientKeyFile,omitempty" yaml:"clientKeyFile,omitempty"`
+	InsecureSkipTLSVerify bool              `json:"insecureSkipTLSVerify,omitempty" yaml:"insecureSkipTLSVerify,omitempty"`
+	HTTPSOnly             bool              `json:"httpsOnly,omitempty" yaml:"httpsOnly,omitempty"`
+	SSRFProtection        bool              `json:"ssrfProtection,omitempty" yaml:"ssrfProtection,omitempty"`
+	AllowedHosts          []string          `json:"allowedHosts,omitempty" yaml:"allowedHosts,omitempty"`
+	BlockedHosts          []string          `json:"blockedHosts,omitempty" yaml:"blockedHosts,omitempty"`
+	WorkerPool            *WorkerPoolConfig `json:"workerPool,omitempty" yaml:"workerPool,omitempty"`
+	// Debug dumps requests and responses to the standard error.
+	Debug          bool  `json:"debug,omitempty" yaml:"debug,omitempty"`
+	DebugBodyLimit int64 `json:"debugBodyLimit,omitempty" yaml:"debugBodyLimit,omitempty"`
+}
+
+// BasicAuthConfig is the basic auth of a Config.
+type BasicAuthConfig struct {
+	Username string `json:"username" yaml:"username"`
+	Password string `json:"password" yaml:"password"`
+}
+
+// BulkheadConfig is a bulkhead of a Config (see WithBulkhead).
+type BulkheadConfig struct {
+	Name          string `json:"name" yaml:"name"`
+	MaxConcurrent int    `json:"maxConcurrent" yaml:"maxConcurrent"`
+	MaxQueued     int    `json:"maxQueued,omitempty" yaml:"maxQueued,omitempty"`
+}
+
+// MemoryCacheConfig is the in-memory cache of a Config (see WithMemoryCache).
+type MemoryCacheConfig struct {
+	MaxEntries int   `json:"maxEntries,omitempty" yaml:"maxEntries,omitempty"`
+	MaxBytes   int64 `json:"maxBytes,omitempty" yaml:"maxBytes,omitempty"`
+}
+
+// HealthCheckConfig is the health check of a Config (see HealthCheck).
+type HealthCheckConfig struct {
+	Path               string   `json:"path,omitempty" yaml:"path,omitempty"`
+	Interval           Duration `json:"interval,omitempty" yaml:"interval,omitempty"`
+	Timeout            Duration `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	HealthyThreshold   int      `json:"healthyThreshold,omitempty" yaml:"healthyThreshold,omitempty"`
+	UnhealthyThreshold int      `json:"unhealthyThreshold,omitempty" yaml:"unhealthyThreshold,omitempty"`
+}
+
+// OutlierDetectionConfig is the outlier detection of a Config (see OutlierDetection).
+type OutlierDetectionConfig struct {
+	Interval         Duration `json:"interval,omitempty" yaml:"interval,omitempty"`
+	ErrorRate        float64  `json:"errorRate,omitempty" yaml:"errorRate,omitempty"`
+	MinRequests      int      `json:"minRequests,omitempty" yaml:"minRequests,omitempty"`
+	MaxLatency       Duration `json:"maxLatency,omitempty" yaml:"maxLatency,omitempty"`
+	BaseEjectionTime Duration `json:"baseEjectionTime,omitempty" yaml:"baseEjectionTime,omitempty"`
+	MaxEjectionTime  Duration `json:"maxEjectionTime,omitempty" yaml:"maxEjectionTime,omitempty"`
+}
+
+// CircuitBreakerConfig is the circuit breaker of a Config (see CircuitBreaker).
+type CircuitBreakerConfig struct {
+	FailureThreshold int      `json:"failureThreshold,omitempty" yaml:"failureThreshold,omitempty"`
+	OpenTimeout      Duration `json:"openTimeout,omitempty" yaml:"openTimeout,omitempty"`
+	// PerRoute gives each host and path its own circuit, instead of each host.
+	PerRoute bool `json:"perRoute,omitempty" yaml:"perRoute,omitempty"`
+}
+
+// WorkerPoolConfig is the worker pool of a Config (see WithWorkerPool).
+type WorkerPoolConfig struct {
+	// Workers is the number of workers, DefaultQueueWorkers if it is 0.
+	Workers int `json:"workers,omitempty" yaml:"workers,omitempty"`
+	// QueueSize is the size of the queue, DefaultQueueSize if it is 0.
+	QueueSize int `json:"queueSize,omitempty" yaml:"queueSize,omitempty"`
+}
+
+// Duration is a time.Duration written as a string like "1m30s" in JSON and YAML.
+type Duration time.Duration
+
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+func (d *Duration) UnmarshalText(text []byte) error {
+	parsed, err := time.ParseDuration(string(text))
+	if err != nil {
+		return fmt.Errorf("invalid duration: %w", err)
+	}
+
+	*d = Duration(parsed)
+	return nil
+}
+
+func (d *Duration) UnmarshalYAML(node *yaml.Node) error {
+	return d.UnmarshalText([]byte(node.Value))
+}
+
+// LoadConfig reads a Config from a JSON file, or a YAML file if its extension is .yaml or .yml.
+// Unknown fields are rejected.
+func LoadConfig(path string) (Config, error) {
+	var cfg Config
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	switch filepath.Ext(path) {
+	case ".yaml", ".yml":
+		err = decodeYAMLConfig(data, &cfg)
+	default:
+		err = decodeJSONConfig(data, &cfg)
+	}
+	if err != nil {
+		return cfg, fmt.Errorf("failed to decode config: %w", err)
+	}
+
+	return cfg, nil
+}
+
+func decodeJSONConfig(data []byte, cfg *Config) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	return decoder.Decode(cfg)
+}
+
+func decodeYAMLConfig(data []byte, cfg *Config) error {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+
+	err := decoder.Decode(cfg)
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+
+	return err
+}
+
+// retryStatuses returns a retry function retrying requests that can't be sent or whose response
+// has one of the statuses.
+func retryStatuses(statuses []int) func(*http.Request, *http.Response, error) bool {
+	return func(_ *http.Request, resp *http.Response, err error) bool {
+		return err != nil || slices.Contains(statuses, resp.StatusCode)
+	}
+}
+
+// NewClientFromConfig creates a new client with the options of the config, followed by the given
+// options. Like NewClientE, it returns an error wrapping ErrInvalidOption if an option is invalid,
+// including WithClient or WithTransport given with a config whose settings they would replace, such
+// as Proxy or HTTPSOnly.
+func NewClientFromConfig(cfg Config, opts ...Option) (*Client, error) {
+	return NewClientE(append(cfg.Options(), opts...)...)
+}
+
+// Options returns the options of the config. Invalid fields are recorded as configuration errors
+// of the client.
+func (cfg Config) Options() []Option {
+	var opts []Option
+	add := func(opt Option) {
+		opts = append(opts, opt)
+	}
+	invalid := func(field, value string) {
+		add(func(c *Client) {
+			c.addConfigError("Config: invalid %s %q", field, value)
+		})
+	}
+
+	if cfg.BaseURL != "" {
+		add(WithBaseURL(cfg.BaseURL))
+	}
+
+	if len(cfg.Endpoints) > 0 {
+		add(WithEndpoints(cfg.Endpoints[0], cfg.Endpoints[1:]...))
+	}
+
+	if len(cfg.FailoverStatuses) > 0 {
+		add(WithFailoverStatuses(cfg.FailoverStatuses...))
+	}
+
+	switch cfg.Balancer {
+	case "":
+	case "round-robin":
+		add(WithBalancer(&RoundRobin{}))
+	case "random":
+		add(WithBalancer(Random{}))
+	case "least-pending":
+		add(WithBalancer(LeastPending{}))
+	default:
+		invalid("balancer", cfg.Balancer)
+	}
+
+	if h := cfg.HealthCheck; h != nil {
+		add(WithHealthCheck(HealthCheck{
+			Path:               h.Path,
+			Interval:           time.Duration(h.Interval),
+			Timeout:            time.Duration(h.Timeout),
+			HealthyThreshold:   h.HealthyThreshold,
+			UnhealthyThreshold: h.UnhealthyThreshold,
+		}))
+	}
+
+	if o := cfg.OutlierDetection; o != nil {
+		add(WithOutlierDetection(OutlierDetection{
+			Interval:         time.Duration(o.Interval),
+			ErrorRate:        o.ErrorRate,
+			MinRequests:      o.MinRequests,
+			MaxLatency:       time.Duration(o.MaxLatency),
+			BaseEjectionTime: time.Duration(o.BaseEjectionTime),
+			MaxEjectionTime:  time.Duration(o.MaxEjectionTime),
+		}))
+	}
+
+	if b := cfg.CircuitBreaker; b != nil {
+		breaker := CircuitBreaker{FailureThreshold: b.FailureThreshold, OpenTimeout: time.Duration(b.OpenTimeout)}
+		if b.PerRoute {
+			breaker.Key = CircuitPerRoute
+		}
+		add(WithCircuitBreaker(breaker))
+	}
+
+	if len(cfg.Headers) > 0 {
+		add(WithHeaders(cfg.Headers))
+	}
+
+	if len(cfg.QueryParams) > 0 {
+		add(WithQueryParams(cfg.QueryParams))
+	}
+
+	if cfg.UserAgent != "" {
+		add(WithUserAgent(cfg.UserAgent))
+	}
+
+	if cfg.BearerToken != "" {
+		add(WithBearerAuth(cfg.BearerToken))
+	}
+
+	if cfg.BasicAuth != nil {
+		add(WithBasicAuth(cfg.BasicAuth.Username, cfg.BasicAuth.Password))
+	}
+
+	if cfg.Timeout != 0 {
+		add(WithTimeout(time.Duration(cfg.Timeout)))
+	}
+
+	if cfg.RateLimit != 0 {
+		add(WithRateLimit(cfg.RateLimit))
+	}
+
+	if cfg.Retries != 0 {
+		statuses := cfg.RetryStatuses
+		if len(statuses) == 0 {
+			statuses = DefaultConfigRetryStatuses
+		}
+		add(WithRetries(cfg.Retries, retryStatuses(statuses)))
+	}
+
+	if cfg.MaxConcurrency != 0 {
+		add(WithMaxConcurrency(cfg.MaxConcurrency))
+	}
+
+	if cfg.MaxConcurrencyPerHost != 0 {
+		add(WithMaxConcurrencyPerHost(cfg.MaxConcurrencyPerHost))
+	}
+
+	for _, b := range cfg.Bulkheads {
+		add(WithBulkhead(b.Name, b.MaxConcurrent, b.MaxQueued))
+	}
+
+	if cfg.MaxRequestBytes != 0 {
+		add(WithMaxRequestBytes(cfg.MaxRequestBytes))
+	}
+
+	if cfg.MaxResponseBytes != 0 {
+		add(WithMaxResponseBytes(cfg.MaxResponseBytes))
+	}
+
+	if cfg.ErrorOnStatus {
+		add(WithErrorOnStatus(nil))
+	}
+
+	if cfg.IdempotencyKey {
+		add(WithIdempotencyKey())
+	}
+
+	if cfg.RequestDeduplication {
+		add(WithRequestDeduplication())
+	}
+
+	if cfg.RequestIDHeader != "" {
+		add(WithRequestID(nil, cfg.RequestIDHeader))
+	}
+
+	if m := cfg.MemoryCache; m != nil {
+		add(WithMemoryCache(m.MaxEntries, m.MaxBytes))
+	}
+
+	if cfg.MaxRedirects != 0 || cfg.DisableRedirects {
+		maxRedirects := cfg.MaxRedirects
+		if maxRedirects == 0 {
+			maxRedirects = DefaultMaxRedirects
+		}
+		add(WithRedirectPolicy(maxRedirects, !cfg.DisableRedirects))
+	}
+
+	if len(cfg.RedirectStripHeaders) > 0 || len(cfg.RedirectForwardHeaders) > 0 {
+		add(WithRedirectHeaderPolicy(RedirectHeaderPolicy{Strip: cfg.RedirectStripHeaders, Forward: cfg.RedirectForwardHeaders}))
+	}
+
+	switch cfg.ReferrerPolicy {
+	case "":
+	case "full":
+		add(WithReferrerPolicy(ReferrerFull))
+	case "origin":
+		add(WithReferrerPolicy(ReferrerOrigin))
+	case "none":
+		add(WithReferrerPolicy(NoReferrer))
+	default:
+		invalid("referrer policy", cfg.ReferrerPolicy)
+	}
+
+	if cfg.Proxy != "" {
+		add(WithProxy(cfg.Proxy))
+	}
+
+	if len(cfg.Proxies) > 0 {
+		switch cfg.ProxyStrategy {
+		case "", "round-robin":
+			add(WithProxyPool(cfg.Proxies, ProxyRoundRobin))
+		case "random":
+			add(WithProxyPool(cfg.Proxies, ProxyRandom))
+		default:
+			invalid("proxy strategy", cfg.ProxyStrategy)
+		}
+	}
+
+	if cfg.UnixSocket != "" {
+		add(WithUnixSocket(cfg.UnixSocket))
+	}
+
+	if cfg.HTTP2 != nil {
+		add(WithHTTP2(*cfg.HTTP2))
+	}
+
+	if cfg.DNSCacheTTL != 0 {
+		add(WithDNSCache(time.Duration(cfg.DNSCacheTTL)))
+	}
+
+	switch cfg.IPFamily {
+	case "", "any":
+	case "prefer-ipv4":
+		add(WithIPFamily(PreferIPv4))
+	case "prefer-ipv6":
+		add(WithIPFamily(PreferIPv6))
+	case "ipv4-only":
+		add(WithIPFamily(IPv4Only))
+	case "ipv6-only":
+		add(WithIPFamily(IPv6Only))
+	default:
+		invalid("IP family", cfg.IPFamily)
+	}
+
+	if len(cfg.RootCAFiles) > 0 {
+		add(WithRootCAFiles(cfg.RootCAFiles...))
+	}
+
+	if cfg.SystemRoots != nil {
+		add(WithSystemRoots(*cfg.SystemRoots))
+	}
+
+	if cfg.ClientCertFile != "" || cfg.ClientKeyFile != "" {
+		add(WithClientCertFiles(cfg.ClientCertFile, cfg.ClientKeyFile))
+	}
+
+	if cfg.InsecureSkipTLSVerify {
+		add(WithInsecureSkipTLSVerify())
+	}
+
+	if cfg.HTTPSOnly {
+		add(WithHTTPSOnly())
+	}
+
+	if cfg.SSRFProtection {
+		add(WithSSRFProtection())
+	}
+
+	if len(cfg.AllowedHosts) > 0 {
+		add(WithAllowedHosts(cfg.AllowedHosts...))
+	}
+
+	if len(cfg.BlockedHosts) > 0 {
+		add(WithBlockedHosts(cfg.BlockedHosts...))
+	}
+
+	if w := cfg.WorkerPool; w != nil {
+		queueSize := w.QueueSize
+		if queueSize == 0 {
+			queueSize = DefaultQueueSize
+		}
+		add(WithWorkerPool(w.Workers, queueSize))
+	}
+
+	if cfg.Debug {
+		add(WithDebug(os.Stderr))
+	}
+
+	if cfg.DebugBodyLimit != 0 {
+		add(WithDebugBodyLimit(cfg.DebugBodyLimit))
+	}
+
+	return opts
+}
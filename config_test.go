@@ -0,0 +1,176 @@
+package clink_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/davesavic/clink"
+)
+
+func TestLoadConfig(t *testing.T) {
+	testCases := []struct {
+		name       string
+		file       string
+		content    string
+		resultFunc func(clink.Config, error) bool
+	}{
+		{
+			name:    "json",
+			file:    "client.json",
+			content: `{"baseURL": "https://api.example.com", "timeout": "1m30s", "retries": 2, "circuitBreaker": {"openTimeout": "10s"}}`,
+			resultFunc: func(cfg clink.Config, err error) bool {
+				return err == nil && cfg.BaseURL == "https://api.example.com" && cfg.Retries == 2 &&
+					time.Duration(cfg.Timeout) == 90*time.Second &&
+					time.Duration(cfg.CircuitBreaker.OpenTimeout) == 10*time.Second
+			},
+		},
+		{
+			name: "yaml",
+			file: "client.yaml",
+			content: "baseURL: https://api.example.com\ntimeout: 5s\nheaders:\n  X-Service: billing\n" +
+				"bulkheads:\n  - name: search\n    maxConcurrent: 4\n",
+			resultFunc: func(cfg clink.Config, err error) bool {
+				return err == nil && time.Duration(cfg.Timeout) == 5*time.Second &&
+					cfg.Headers["X-Service"] == "billing" && len(cfg.Bulkheads) == 1 && cfg.Bulkheads[0].MaxConcurrent == 4
+			},
+		},
+		{
+			name:    "empty yaml",
+			file:    "client.yml",
+			content: "",
+			resultFunc: func(cfg clink.Config, err error) bool {
+				return err == nil && cfg.BaseURL == ""
+			},
+		},
+		{
+			name:    "invalid duration",
+			file:    "client.json",
+			content: `{"timeout": "soon"}`,
+			resultFunc: func(_ clink.Config, err error) bool {
+				return err != nil
+			},
+		},
+		{
+			name:    "unknown field",
+			file:    "client.yaml",
+			content: "baseUrl: https://api.example.com\n",
+			resultFunc: func(_ clink.Config, err error) bool {
+				return err != nil
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tc.file)
+			if err := os.WriteFile(path, []byte(tc.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			cfg, err := clink.LoadConfig(path)
+			if !tc.resultFunc(cfg, err) {
+				t.Errorf("unexpected result: %+v, %v", cfg, err)
+			}
+		})
+	}
+}
+
+func TestNewClientFromConfig(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if hits.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(r.Header.Get("X-Service") + " " + r.Header.Get("User-Agent")))
+	}))
+	defer server.Close()
+
+	testCases := []struct {
+		name       string
+		cfg        clink.Config
+		opts       []clink.Option
+		resultFunc func(*clink.Client, error) bool
+	}{
+		{
+			name: "options applied",
+			cfg: clink.Config{
+				BaseURL:   server.URL,
+				Headers:   map[string]string{"X-Service": "billing"},
+				UserAgent: "billing/1.0",
+				Retries:   1,
+			},
+			resultFunc: func(c *clink.Client, err error) bool {
+				if err != nil {
+					return false
+				}
+				req, _ := http.NewRequest(http.MethodGet, "/", nil)
+				resp, err := c.DoWrapped(req)
+				return err == nil && resp.String() == "billing billing/1.0" && hits.Load() == 2
+			},
+		},
+		{
+			name: "invalid enum",
+			cfg:  clink.Config{IPFamily: "ipv5"},
+			resultFunc: func(c *clink.Client, err error) bool {
+				return c == nil && errors.Is(err, clink.ErrInvalidOption)
+			},
+		},
+		{
+			name: "invalid option",
+			cfg:  clink.Config{RateLimit: -1},
+			resultFunc: func(c *clink.Client, err error) bool {
+				return c == nil && errors.Is(err, clink.ErrInvalidOption)
+			},
+		},
+		{
+			name: "transport settings with custom client",
+			cfg:  clink.Config{Proxy: "http://proxy.example.com:8080"},
+			opts: []clink.Option{clink.WithClient(&http.Client{})},
+			resultFunc: func(c *clink.Client, err error) bool {
+				return c == nil && errors.Is(err, clink.ErrInvalidOption)
+			},
+		},
+		{
+			name: "transport settings with custom transport",
+			cfg:  clink.Config{Proxy: "http://proxy.example.com:8080"},
+			opts: []clink.Option{clink.WithTransport(http.DefaultTransport)},
+			resultFunc: func(c *clink.Client, err error) bool {
+				return c == nil && errors.Is(err, clink.ErrInvalidOption)
+			},
+		},
+		{
+			name: "redirect settings with custom client",
+			cfg:  clink.Config{HTTPSOnly: true},
+			opts: []clink.Option{clink.WithClient(&http.Client{})},
+			resultFunc: func(c *clink.Client, err error) bool {
+				return c == nil && errors.Is(err, clink.ErrInvalidOption)
+			},
+		},
+		{
+			name: "redirect settings with custom transport",
+			cfg:  clink.Config{HTTPSOnly: true},
+			opts: []clink.Option{clink.WithTransport(http.DefaultTransport)},
+			resultFunc: func(c *clink.Client, err error) bool {
+				return c != nil && err == nil
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			hits.Store(0)
+
+			c, err := clink.NewClientFromConfig(tc.cfg, tc.opts...)
+			if !tc.resultFunc(c, err) {
+				t.Errorf("unexpected result: %v", err)
+			}
+		})
+	}
+}
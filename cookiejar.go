@@ -0,0 +1,396 @@
+package clink
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FileCookieJar is an http.CookieJar that persists cookies to a JSON file, so that sessions
+// survive process restarts. Expired cookies are pruned when the jar is loaded and saved.
+// The file can optionally be encrypted with AES-GCM.
+type FileCookieJar struct {
+	path string
+	aead cipher.AEAD
+
+	mu      sync.Mutex
+	clock   Clock
+	jar     *cookiejar.Jar
+	cookies map[string]storedCookie
+}
+
+// storedCookie is a cookie persisted with the URL it was set by.
+type storedCookie struct {
+	URL      string        `json:"url"`
+	Name     string        `json:"name"`
+	Value    string        `json:"value"`
+	Path     string        `json:"path,omitempty"`
+	Domain   string        `json:"domain,omitempty"`
+	Expires  time.Time     `json:"expires,omitempty"`
+	Secure   bool          `json:"secure,omitempty"`
+	HttpOnly bool          `json:"http_only,omitempty"`
+	SameSite http.SameSite `json:"same_site,omitempty"`
+}
+
+// NewFileCookieJar creates a cookie jar persisted to the file at path, loading any cookies
+// already stored in it. If key is not nil, the file is encrypted with AES-GCM using the key,
+// which must be 16, 24 or 32 bytes long. Cookies are saved after every change.
+func NewFileCookieJar(path string, key []byte) (*FileCookieJar, error) {
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cookie jar: %w", err)
+	}
+
+	j := &FileCookieJar{path: path, clock: realClock{}, jar: jar, cookies: make(map[string]storedCookie)}
+
+	if key != nil {
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cookie jar key: %w", err)
+		}
+
+		j.aead, err = cipher.NewGCM(block)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cookie jar key: %w", err)
+		}
+	}
+
+	if err := j.load(); err != nil {
+		return nil, err
+	}
+
+	return j, nil
+}
+
+// ErrNoCookieJar is returned by the cookie methods of a client without a cookie jar.
+var ErrNoCookieJar = errors.New("client has no cookie jar")
+
+// WithCookieJar sets the cookie jar of the http client. If jar is nil, an in-memory jar is used.
+// When used with WithClient, it must come after it; the given http.Client is copied rather than modified.
+func WithCookieJar(jar http.CookieJar) Option {
+	return func(c *Client) {
+		if jar == nil {
+			jar = newMemoryCookieJar()
+		}
+
+		if c.transport == nil {
+			client := *c.HttpClient
+			c.HttpClient = &client
+		}
+
+		c.HttpClient.Jar = jar
+	}
+}
+
+// SetCookies stores the cookies received from the URL and saves the jar.
+func (j *FileCookieJar) SetCookies(u *url.URL, cookies []*http.Cookie) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.setLocked(u, cookies, j.clock.Now())
+	_ = j.saveLocked()
+}
+
+// Cookies returns the cookies to send in a request to the URL.
+func (j *FileCookieJar) Cookies(u *url.URL) []*http.Cookie {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if expireCookies(j.jar, j.cookies, j.clock.Now()) {
+		_ = j.saveLocked()
+	}
+
+	return j.jar.Cookies(u)
+}
+
+func (j *FileCookieJar) useClock(clock Clock) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.clock = clock
+}
+
+// Clear removes every cookie from the jar and its file.
+func (j *FileCookieJar) Clear() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return fmt.Errorf("failed to create cookie jar: %w", err)
+	}
+
+	j.jar = jar
+	j.cookies = make(map[string]storedCookie)
+
+	return j.saveLocked()
+}
+
+// SetCookie stores the cookie in the client's jar as if it was set by the host, which can be
+// a host name (example.com) or a URL (https://example.com/path).
+func (c *Client) SetCookie(host string, cookie *http.Cookie) error {
+	if c.config().HttpClient.Jar == nil {
+		return ErrNoCookieJar
+	}
+
+	u, err := cookieURL(host)
+	if err != nil {
+		return err
+	}
+
+	c.config().HttpClient.Jar.SetCookies(u, []*http.Cookie{cookie})
+
+	return nil
+}
+
+// Cookies returns the cookies the client sends to the host, which can be a host name or a URL.
+// It returns nil if the client has no cookie jar.
+func (c *Client) Cookies(host string) []*http.Cookie {
+	if c.config().HttpClient.Jar == nil {
+		return nil
+	}
+
+	u, err := cookieURL(host)
+	if err != nil {
+		return nil
+	}
+
+	return c.config().HttpClient.Jar.Cookies(u)
+}
+
+// ClearCookies removes every cookie from the client's jar. Jars other than the in-memory jar of
+// WithCookieJar(nil) and FileCookieJar must implement a Clear() error method to be cleared.
+func (c *Client) ClearCookies() error {
+	switch jar := c.config().HttpClient.Jar.(type) {
+	case nil:
+		return ErrNoCookieJar
+	case interface{ Clear() error }:
+		return jar.Clear()
+	default:
+		return fmt.Errorf("cookie jar %T cannot be cleared", jar)
+	}
+}
+
+// memoryCookieJar is an in-memory cookie jar that can be cleared.
+type memoryCookieJar struct {
+	mu      sync.Mutex
+	clock   Clock
+	jar     *cookiejar.Jar
+	cookies map[string]storedCookie
+}
+
+func newMemoryCookieJar() *memoryCookieJar {
+	jar, _ := cookiejar.New(nil)
+	return &memoryCookieJar{clock: realClock{}, jar: jar, cookies: make(map[string]storedCookie)}
+}
+
+func (j *memoryCookieJar) SetCookies(u *url.URL, cookies []*http.Cookie) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.jar.SetCookies(u, cookies)
+	recordCookies(j.cookies, u, cookies, j.clock.Now())
+}
+
+func (j *memoryCookieJar) Cookies(u *url.URL) []*http.Cookie {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	expireCookies(j.jar, j.cookies, j.clock.Now())
+	return j.jar.Cookies(u)
+}
+
+func (j *memoryCookieJar) Clear() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.jar, _ = cookiejar.New(nil)
+	j.cookies = make(map[string]storedCookie)
+	return nil
+}
+
+func (j *memoryCookieJar) useClock(clock Clock) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.clock = clock
+}
+
+// expireCookies removes the cookies that expired at now from the jar, which only expires cookies
+// with the real time, and from the cookies recorded for it. It reports whether any cookie expired.
+func expireCookies(jar *cookiejar.Jar, cookies map[string]storedCookie, now time.Time) bool {
+	var expired bool
+	for key, s := range cookies {
+		if s.Expires.IsZero() || s.Expires.After(now) {
+			continue
+		}
+
+		if u, err := url.Parse(s.URL); err == nil {
+			jar.SetCookies(u, []*http.Cookie{{Name: s.Name, Path: s.Path, Domain: s.Domain, MaxAge: -1}})
+		}
+		delete(cookies, key)
+		expired = true
+	}
+
+	return expired
+}
+
+// cookieURL returns the URL of a host name or URL given to the cookie methods of the client.
+// Host names use https, so that both secure and insecure cookies apply.
+func cookieURL(host string) (*url.URL, error) {
+	if !strings.Contains(host, "://") {
+		host = "https://" + host
+	}
+
+	u, err := url.Parse(host)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cookie host: %w", err)
+	}
+
+	if u.Path == "" {
+		u.Path = "/"
+	}
+
+	return u, nil
+}
+
+// Save writes the cookies of the jar to its file. Cookies are saved after every change, so
+// Save is only needed to find out about write errors.
+func (j *FileCookieJar) Save() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	return j.saveLocked()
+}
+
+func (j *FileCookieJar) setLocked(u *url.URL, cookies []*http.Cookie, now time.Time) {
+	j.jar.SetCookies(u, cookies)
+	recordCookies(j.cookies, u, cookies, now)
+}
+
+// recordCookies records the cookies set by the URL with their expiry time, or removes them if
+// they are expired at now.
+func recordCookies(stored map[string]storedCookie, u *url.URL, cookies []*http.Cookie, now time.Time) {
+	for _, cookie := range cookies {
+		key := cookieKey(u, cookie)
+
+		expires := cookie.Expires
+		if cookie.MaxAge > 0 {
+			expires = now.Add(time.Duration(cookie.MaxAge) * time.Second)
+		}
+
+		if cookie.MaxAge < 0 || !expires.IsZero() && !expires.After(now) {
+			delete(stored, key)
+			continue
+		}
+
+		stored[key] = storedCookie{
+			URL:      u.String(),
+			Name:     cookie.Name,
+			Value:    cookie.Value,
+			Path:     cookie.Path,
+			Domain:   cookie.Domain,
+			Expires:  expires,
+			Secure:   cookie.Secure,
+			HttpOnly: cookie.HttpOnly,
+			SameSite: cookie.SameSite,
+		}
+	}
+}
+
+// cookieKey identifies a cookie by the domain it applies to, its path and name.
+func cookieKey(u *url.URL, cookie *http.Cookie) string {
+	domain := cookie.Domain
+	if domain == "" {
+		domain = u.Hostname()
+	}
+
+	return domain + ";" + cookie.Path + ";" + cookie.Name
+}
+
+func (j *FileCookieJar) load() error {
+	data, err := os.ReadFile(j.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read cookie jar: %w", err)
+	}
+
+	if j.aead != nil {
+		nonceSize := j.aead.NonceSize()
+		if len(data) < nonceSize {
+			return fmt.Errorf("failed to decrypt cookie jar: data too short")
+		}
+
+		data, err = j.aead.Open(nil, data[:nonceSize], data[nonceSize:], nil)
+		if err != nil {
+			return fmt.Errorf("failed to decrypt cookie jar: %w", err)
+		}
+	}
+
+	var stored []storedCookie
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return fmt.Errorf("failed to decode cookie jar: %w", err)
+	}
+
+	now := j.clock.Now()
+	for _, s := range stored {
+		u, err := url.Parse(s.URL)
+		if err != nil {
+			continue
+		}
+
+		j.setLocked(u, []*http.Cookie{{
+			Name:     s.Name,
+			Value:    s.Value,
+			Path:     s.Path,
+			Domain:   s.Domain,
+			Expires:  s.Expires,
+			Secure:   s.Secure,
+			HttpOnly: s.HttpOnly,
+			SameSite: s.SameSite,
+		}}, now)
+	}
+
+	return nil
+}
+
+func (j *FileCookieJar) saveLocked() error {
+	now := j.clock.Now()
+
+	stored := make([]storedCookie, 0, len(j.cookies))
+	for key, s := range j.cookies {
+		if !s.Expires.IsZero() && !s.Expires.After(now) {
+			delete(j.cookies, key)
+			continue
+		}
+		stored = append(stored, s)
+	}
+
+	data, err := json.Marshal(stored)
+	if err != nil {
+		return fmt.Errorf("failed to encode cookie jar: %w", err)
+	}
+
+	if j.aead != nil {
+		nonce := make([]byte, j.aead.NonceSize())
+		if _, err := rand.Read(nonce); err != nil {
+			return fmt.Errorf("failed to encrypt cookie jar: %w", err)
+		}
+		data = j.aead.Seal(nonce, nonce, data, nil)
+	}
+
+	if err := writeFileAtomic(j.path, data); err != nil {
+		return fmt.Errorf("failed to write cookie jar: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,234 @@
+package clink_test
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/davesavic/clink"
+)
+
+func TestFileCookieJar(t *testing.T) {
+	key := bytes.Repeat([]byte("k"), 32)
+
+	testCases := []struct {
+		name       string
+		key        []byte
+		resultFunc func(t *testing.T, path string, cookies []*http.Cookie)
+	}{
+		{
+			name: "restores cookies from file",
+			resultFunc: func(t *testing.T, _ string, cookies []*http.Cookie) {
+				if len(cookies) != 2 {
+					t.Errorf("expected session and persistent cookies, got: %v", cookies)
+				}
+			},
+		},
+		{
+			name: "encrypts file",
+			key:  key,
+			resultFunc: func(t *testing.T, path string, cookies []*http.Cookie) {
+				data, _ := os.ReadFile(path)
+				if bytes.Contains(data, []byte("secret-session")) {
+					t.Errorf("expected cookie file to be encrypted")
+				}
+
+				if len(cookies) != 2 {
+					t.Errorf("expected cookies to be restored, got: %v", cookies)
+				}
+
+				if _, err := clink.NewFileCookieJar(path, bytes.Repeat([]byte("x"), 32)); err == nil {
+					t.Errorf("expected error loading jar with wrong key")
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				http.SetCookie(w, &http.Cookie{Name: "session", Value: "secret-session"})
+				http.SetCookie(w, &http.Cookie{Name: "persistent", Value: "1", MaxAge: 3600})
+				http.SetCookie(w, &http.Cookie{Name: "expired", Value: "1", Expires: time.Now().Add(-time.Hour)})
+			}))
+			defer server.Close()
+
+			path := filepath.Join(t.TempDir(), "cookies.json")
+
+			jar, err := clink.NewFileCookieJar(path, tc.key)
+			if err != nil {
+				t.Fatalf("failed to create jar: %v", err)
+			}
+
+			resp, err := clink.NewClient(clink.WithCookieJar(jar)).Get(server.URL)
+			if err != nil {
+				t.Fatalf("failed to make request: %v", err)
+			}
+			_ = resp.Body.Close()
+
+			restored, err := clink.NewFileCookieJar(path, tc.key)
+			if err != nil {
+				t.Fatalf("failed to load jar: %v", err)
+			}
+
+			u, _ := url.Parse(server.URL)
+			tc.resultFunc(t, path, restored.Cookies(u))
+		})
+	}
+}
+
+func TestFileCookieJar_PrunesExpiredCookies(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cookies.json")
+	u, _ := url.Parse("http://example.com")
+
+	jar, err := clink.NewFileCookieJar(path, nil)
+	if err != nil {
+		t.Fatalf("failed to create jar: %v", err)
+	}
+
+	jar.SetCookies(u, []*http.Cookie{
+		{Name: "short", Value: "1", Expires: time.Now().Add(50 * time.Millisecond)},
+		{Name: "long", Value: "1", Expires: time.Now().Add(time.Hour)},
+	})
+	time.Sleep(100 * time.Millisecond)
+
+	if err := jar.Save(); err != nil {
+		t.Fatalf("failed to save jar: %v", err)
+	}
+
+	data, _ := os.ReadFile(path)
+	if bytes.Contains(data, []byte(`"short"`)) || !bytes.Contains(data, []byte(`"long"`)) {
+		t.Errorf("expected expired cookie to be pruned, got: %s", data)
+	}
+}
+
+func TestClient_Cookies(t *testing.T) {
+	var received string
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		received = r.Header.Get("Cookie")
+	}))
+	defer server.Close()
+
+	u, _ := url.Parse(server.URL)
+
+	testCases := []struct {
+		name string
+		jar  func(t *testing.T) http.CookieJar
+	}{
+		{
+			name: "in-memory jar",
+			jar: func(_ *testing.T) http.CookieJar {
+				return nil
+			},
+		},
+		{
+			name: "file jar",
+			jar: func(t *testing.T) http.CookieJar {
+				jar, err := clink.NewFileCookieJar(filepath.Join(t.TempDir(), "cookies.json"), nil)
+				if err != nil {
+					t.Fatalf("failed to create jar: %v", err)
+				}
+				return jar
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := clink.NewClient(clink.WithCookieJar(tc.jar(t)))
+
+			if err := c.SetCookie(server.URL, &http.Cookie{Name: "session", Value: "seeded"}); err != nil {
+				t.Fatalf("failed to set cookie: %v", err)
+			}
+
+			if cookies := c.Cookies(u.Host); len(cookies) != 1 || cookies[0].Value != "seeded" {
+				t.Errorf("expected seeded cookie, got: %v", cookies)
+			}
+
+			resp, err := c.Get(server.URL)
+			if err != nil {
+				t.Fatalf("failed to make request: %v", err)
+			}
+			_ = resp.Body.Close()
+
+			if received != "session=seeded" {
+				t.Errorf("expected seeded cookie to be sent, got: %q", received)
+			}
+
+			if err := c.ClearCookies(); err != nil {
+				t.Fatalf("failed to clear cookies: %v", err)
+			}
+
+			if cookies := c.Cookies(server.URL); len(cookies) != 0 {
+				t.Errorf("expected no cookies after clear, got: %v", cookies)
+			}
+		})
+	}
+}
+
+func TestClient_CookiesClock(t *testing.T) {
+	testCases := []struct {
+		name string
+		jar  func(t *testing.T) http.CookieJar
+	}{
+		{
+			name: "in-memory jar",
+			jar: func(_ *testing.T) http.CookieJar {
+				return nil
+			},
+		},
+		{
+			name: "file jar",
+			jar: func(t *testing.T) http.CookieJar {
+				jar, err := clink.NewFileCookieJar(filepath.Join(t.TempDir(), "cookies.json"), nil)
+				if err != nil {
+					t.Fatalf("failed to create jar: %v", err)
+				}
+				return jar
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			clock := &manualClock{now: time.Now()}
+			c := clink.NewClient(clink.WithCookieJar(tc.jar(t)), clink.WithClock(clock))
+
+			if err := c.SetCookie("example.com", &http.Cookie{Name: "session", Value: "1", MaxAge: 60}); err != nil {
+				t.Fatalf("failed to set cookie: %v", err)
+			}
+
+			if cookies := c.Cookies("example.com"); len(cookies) != 1 {
+				t.Errorf("expected the cookie before it expires, got: %v", cookies)
+			}
+
+			clock.advance(2 * time.Minute)
+
+			if cookies := c.Cookies("example.com"); len(cookies) != 0 {
+				t.Errorf("expected the cookie to expire with the client clock, got: %v", cookies)
+			}
+		})
+	}
+}
+
+func TestClient_CookiesWithoutJar(t *testing.T) {
+	c := clink.NewClient()
+
+	if err := c.SetCookie("example.com", &http.Cookie{Name: "a", Value: "1"}); !errors.Is(err, clink.ErrNoCookieJar) {
+		t.Errorf("expected no cookie jar error, got: %v", err)
+	}
+
+	if err := c.ClearCookies(); !errors.Is(err, clink.ErrNoCookieJar) {
+		t.Errorf("expected no cookie jar error, got: %v", err)
+	}
+
+	if cookies := c.Cookies("example.com"); cookies != nil {
+		t.Errorf("expected no cookies, got: %v", cookies)
+	}
+}
@@ -0,0 +1,43 @@
+package clink
+
+import (
+	"context"
+)
+
+// DefaultCorrelationIDHeader is the header used to send correlation IDs.
+const DefaultCorrelationIDHeader = "X-Correlation-ID"
+
+type correlationIDContextKey struct{}
+
+// WithCorrelationID sends the correlation ID of the request context in the given header on every request,
+// unless the request already sets the header. If extractor is nil, CorrelationIDFromContext is used.
+// If headerName is empty, DefaultCorrelationIDHeader is used. Requests without a correlation ID are
+// sent unchanged.
+func WithCorrelationID(extractor func(context.Context) string, headerName string) Option {
+	return func(c *Client) {
+		if extractor == nil {
+			extractor = func(ctx context.Context) string {
+				id, _ := CorrelationIDFromContext(ctx)
+				return id
+			}
+		}
+
+		if headerName == "" {
+			headerName = DefaultCorrelationIDHeader
+		}
+
+		c.CorrelationIDExtractor = extractor
+		c.CorrelationIDHeader = headerName
+	}
+}
+
+// ContextWithCorrelationID returns a copy of the context carrying the correlation ID.
+func ContextWithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationIDContextKey{}, id)
+}
+
+// CorrelationIDFromContext returns the correlation ID stored in the context with ContextWithCorrelationID.
+func CorrelationIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(correlationIDContextKey{}).(string)
+	return id, ok
+}
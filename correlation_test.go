@@ -0,0 +1,81 @@
+package clink_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/davesavic/clink"
+)
+
+type traceIDKey struct{}
+
+func TestCorrelationID(t *testing.T) {
+	testCases := []struct {
+		name     string
+		opts     []clink.Option
+		ctx      context.Context
+		header   string
+		preset   string
+		expected string
+	}{
+		{
+			name:     "default extractor and header",
+			opts:     []clink.Option{clink.WithCorrelationID(nil, "")},
+			ctx:      clink.ContextWithCorrelationID(context.Background(), "corr-1"),
+			header:   clink.DefaultCorrelationIDHeader,
+			expected: "corr-1",
+		},
+		{
+			name: "custom extractor and header",
+			opts: []clink.Option{clink.WithCorrelationID(func(ctx context.Context) string {
+				id, _ := ctx.Value(traceIDKey{}).(string)
+				return id
+			}, "X-Trace-ID")},
+			ctx:      context.WithValue(context.Background(), traceIDKey{}, "trace-1"),
+			header:   "X-Trace-ID",
+			expected: "trace-1",
+		},
+		{
+			name:     "no header without id in context",
+			opts:     []clink.Option{clink.WithCorrelationID(nil, "")},
+			ctx:      context.Background(),
+			header:   clink.DefaultCorrelationIDHeader,
+			expected: "",
+		},
+		{
+			name:     "does not override header set on request",
+			opts:     []clink.Option{clink.WithCorrelationID(nil, "")},
+			ctx:      clink.ContextWithCorrelationID(context.Background(), "corr-1"),
+			header:   clink.DefaultCorrelationIDHeader,
+			preset:   "explicit",
+			expected: "explicit",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var received string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				received = r.Header.Get(tc.header)
+			}))
+			defer server.Close()
+
+			req, _ := http.NewRequestWithContext(tc.ctx, http.MethodGet, server.URL, nil)
+			if tc.preset != "" {
+				req.Header.Set(tc.header, tc.preset)
+			}
+
+			resp, err := clink.NewClient(tc.opts...).Do(req)
+			if err != nil {
+				t.Fatalf("failed to make request: %v", err)
+			}
+			_ = resp.Body.Close()
+
+			if received != tc.expected {
+				t.Errorf("expected %q, got: %q", tc.expected, received)
+			}
+		})
+	}
+}
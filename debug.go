@@ -0,0 +1,149 @@
+package clink
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// DefaultDebugBodyLimit is the maximum number of body bytes written by a debug dump, unless
+// changed with WithDebugBodyLimit.
+const DefaultDebugBodyLimit = 64 << 10
+
+// WithDebug dumps every request and response, including retries, to the writer. Sensitive headers
+// and JSON fields are redacted (see WithRedactor) and bodies are capped (see WithDebugBodyLimit).
+// Dumping can be toggled at runtime with SetDebug.
+func WithDebug(w io.Writer) Option {
+	return func(c *Client) {
+		c.debug.setWriter(w)
+		c.debug.enabled.Store(true)
+	}
+}
+
+// WithDebugBodyLimit sets the maximum number of body bytes written by a debug dump.
+func WithDebugBodyLimit(n int64) Option {
+	return func(c *Client) {
+		c.debug.maxBody.Store(n)
+	}
+}
+
+// SetDebug enables or disables debug dumps at runtime. Dumps are written to the writer given to
+// WithDebug, or to os.Stderr if WithDebug wasn't used. It is safe to call concurrently with requests.
+// It has no effect on an immutable client (see WithImmutable).
+func (c *Client) SetDebug(enabled bool) {
+	if !c.checkMutable("SetDebug") {
+		return
+	}
+
+	c.debug.enabled.Store(enabled)
+}
+
+// debugDumper writes requests and responses to a writer while enabled.
+type debugDumper struct {
+	enabled atomic.Bool
+	maxBody atomic.Int64
+
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func newDebugDumper() *debugDumper {
+	d := &debugDumper{w: os.Stderr}
+	d.maxBody.Store(DefaultDebugBodyLimit)
+
+	return d
+}
+
+func (d *debugDumper) setWriter(w io.Writer) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.w = w
+}
+
+// dumpRequest writes the request with the given body, which has been read by the caller.
+func (d *debugDumper) dumpRequest(req *http.Request, body []byte, redactor *Redactor) {
+	if !d.enabled.Load() {
+		return
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "> %s %s %s\n", req.Method, redactor.URL(req.URL), req.Proto)
+	d.writeHeaderAndBody(&buf, "> ", req.Header, body, false, redactor)
+	d.write(buf.Bytes())
+}
+
+// dumpResponse writes the response and restores its body so that it can still be read in full.
+func (d *debugDumper) dumpResponse(resp *http.Response, err error, redactor *Redactor) {
+	if !d.enabled.Load() {
+		return
+	}
+
+	var buf bytes.Buffer
+	if err != nil {
+		fmt.Fprintf(&buf, "< error: %v\n\n", err)
+		d.write(buf.Bytes())
+		return
+	}
+
+	var body []byte
+	var truncated bool
+	if resp.Body != nil && resp.Body != http.NoBody {
+		limit := d.maxBody.Load()
+		body, _ = io.ReadAll(io.LimitReader(resp.Body, limit+1))
+		resp.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(body), resp.Body), resp.Body}
+
+		if int64(len(body)) > limit {
+			body, truncated = body[:limit], true
+		}
+	}
+
+	fmt.Fprintf(&buf, "< %s %s\n", resp.Proto, resp.Status)
+	d.writeHeaderAndBody(&buf, "< ", resp.Header, body, truncated, redactor)
+	d.write(buf.Bytes())
+}
+
+func (d *debugDumper) writeHeaderAndBody(buf *bytes.Buffer, prefix string, header http.Header, body []byte, truncated bool, redactor *Redactor) {
+	header = redactor.Header(header)
+
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		fmt.Fprintf(buf, "%s%s: %s\n", prefix, name, strings.Join(header[name], ", "))
+	}
+	buf.WriteString(prefix + "\n")
+
+	body = redactor.JSON(body)
+	if limit := d.maxBody.Load(); int64(len(body)) > limit {
+		body, truncated = body[:limit], true
+	}
+
+	if len(body) > 0 {
+		buf.Write(body)
+		buf.WriteString("\n")
+	}
+
+	if truncated {
+		buf.WriteString("[body truncated]\n")
+	}
+
+	buf.WriteString("\n")
+}
+
+func (d *debugDumper) write(p []byte) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	_, _ = d.w.Write(p)
+}
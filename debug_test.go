@@ -0,0 +1,85 @@
+package clink_test
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/davesavic/clink"
+)
+
+func TestWithDebug(t *testing.T) {
+	testCases := []struct {
+		name       string
+		opts       []clink.Option
+		setup      func(*clink.Client)
+		resultFunc func(dump string, body string) bool
+	}{
+		{
+			name: "dumps request and response",
+			resultFunc: func(dump string, body string) bool {
+				return strings.Contains(dump, "> POST ") &&
+					strings.Contains(dump, `{"name":"bob"}`) &&
+					strings.Contains(dump, "< HTTP/1.1 200 OK") &&
+					strings.Contains(dump, "< Content-Type: application/json") &&
+					body == `{"result":"ok","password":"hunter2"}`
+			},
+		},
+		{
+			name: "redacts headers and json fields",
+			resultFunc: func(dump string, _ string) bool {
+				return strings.Contains(dump, "> Authorization: [REDACTED]") &&
+					strings.Contains(dump, `"password":"[REDACTED]"`) &&
+					!strings.Contains(dump, "hunter2") && !strings.Contains(dump, "token")
+			},
+		},
+		{
+			name: "caps bodies",
+			opts: []clink.Option{clink.WithDebugBodyLimit(5)},
+			resultFunc: func(dump string, body string) bool {
+				return strings.Contains(dump, "{\"res\n[body truncated]") &&
+					body == `{"result":"ok","password":"hunter2"}`
+			},
+		},
+		{
+			name: "can be disabled at runtime",
+			setup: func(c *clink.Client) {
+				c.SetDebug(false)
+			},
+			resultFunc: func(dump string, _ string) bool {
+				return dump == ""
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"result":"ok","password":"hunter2"}`))
+			}))
+			defer server.Close()
+
+			var buf bytes.Buffer
+			c := clink.NewClient(append([]clink.Option{clink.WithDebug(&buf), clink.WithBearerAuth("token")}, tc.opts...)...)
+			if tc.setup != nil {
+				tc.setup(c)
+			}
+
+			resp, err := c.Post(server.URL, strings.NewReader(`{"name":"bob"}`))
+			if err != nil {
+				t.Fatalf("failed to make request: %v", err)
+			}
+
+			body, _ := io.ReadAll(resp.Body)
+			_ = resp.Body.Close()
+
+			if !tc.resultFunc(buf.String(), string(body)) {
+				t.Errorf("unexpected dump: %s", buf.String())
+			}
+		})
+	}
+}
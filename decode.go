@@ -0,0 +1,117 @@
+package clink
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// ErrUnsupportedContentType is returned when a body cannot be encoded or decoded for its content type.
+var ErrUnsupportedContentType = errors.New("unsupported content type")
+
+// DecodeOption configures how response bodies are decoded.
+type DecodeOption func(*decodeOptions)
+
+type decodeOptions struct {
+	fallbackContentType   string
+	disallowUnknownFields bool
+	useNumber             bool
+}
+
+func newDecodeOptions(opts []DecodeOption) decodeOptions {
+	o := decodeOptions{fallbackContentType: "application/json"}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// FallbackContentType sets the content type used to decode responses without a supported Content-Type.
+// The default fallback is application/json.
+func FallbackContentType(contentType string) DecodeOption {
+	return func(o *decodeOptions) {
+		o.fallbackContentType = contentType
+	}
+}
+
+// DisallowUnknownFields makes JSON decoding fail when an object contains keys
+// that do not match any field of the target struct.
+func DisallowUnknownFields() DecodeOption {
+	return func(o *decodeOptions) {
+		o.disallowUnknownFields = true
+	}
+}
+
+// UseNumber makes JSON decoding store numbers in interface values as json.Number instead of float64,
+// so that large integers are not silently rounded.
+func UseNumber() DecodeOption {
+	return func(o *decodeOptions) {
+		o.useNumber = true
+	}
+}
+
+// DecodeResponse decodes the response body into the target based on the response Content-Type.
+// JSON, XML, YAML, form (into *url.Values or *map[string]string) and plain text (into *string or *[]byte)
+// bodies are supported.
+func DecodeResponse[T any](response *http.Response, target *T, opts ...DecodeOption) error {
+	return decodeResponse(defaultCodecs, response, target, opts)
+}
+
+func decodeResponse(codecs map[string]Codec, response *http.Response, target any, opts []DecodeOption) error {
+	if response == nil {
+		return fmt.Errorf("response is nil")
+	}
+
+	if response.Body == nil {
+		return fmt.Errorf("response body is nil")
+	}
+
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(response.Body)
+
+	o := newDecodeOptions(opts)
+
+	codec := lookupCodec(codecs, response.Header.Get("Content-Type"))
+	if codec == nil {
+		codec = lookupCodec(codecs, o.fallbackContentType)
+	}
+
+	if codec == nil {
+		return fmt.Errorf("%w: %s", ErrUnsupportedContentType, response.Header.Get("Content-Type"))
+	}
+
+	if jsonCodec, ok := codec.(JSONCodec); ok {
+		jsonCodec.DisallowUnknownFields = jsonCodec.DisallowUnknownFields || o.disallowUnknownFields
+		jsonCodec.UseNumber = jsonCodec.UseNumber || o.useNumber
+		codec = jsonCodec
+	}
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if err := codec.Unmarshal(body, target); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return nil
+}
+
+// newJSONDecoder returns a JSON decoder configured with the decode options.
+func newJSONDecoder(r io.Reader, o decodeOptions) *json.Decoder {
+	decoder := json.NewDecoder(r)
+
+	if o.disallowUnknownFields {
+		decoder.DisallowUnknownFields()
+	}
+
+	if o.useNumber {
+		decoder.UseNumber()
+	}
+
+	return decoder
+}
@@ -0,0 +1,165 @@
+package clink_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/davesavic/clink"
+)
+
+func newResponse(contentType, body string) *http.Response {
+	header := http.Header{}
+	if contentType != "" {
+		header.Set("Content-Type", contentType)
+	}
+
+	return &http.Response{
+		Header: header,
+		Body:   io.NopCloser(strings.NewReader(body)),
+	}
+}
+
+func TestDecodeResponse(t *testing.T) {
+	type item struct {
+		Key string `json:"key" xml:"key"`
+	}
+
+	testCases := []struct {
+		name       string
+		resultFunc func() bool
+	}{
+		{
+			name: "json body",
+			resultFunc: func() bool {
+				var target item
+				err := clink.DecodeResponse(newResponse("application/json; charset=utf-8", `{"key": "value"}`), &target)
+				return err == nil && target.Key == "value"
+			},
+		},
+		{
+			name: "json suffix body",
+			resultFunc: func() bool {
+				var target item
+				err := clink.DecodeResponse(newResponse("application/problem+json", `{"key": "value"}`), &target)
+				return err == nil && target.Key == "value"
+			},
+		},
+		{
+			name: "xml body",
+			resultFunc: func() bool {
+				var target item
+				err := clink.DecodeResponse(newResponse("text/xml", `<item><key>value</key></item>`), &target)
+				return err == nil && target.Key == "value"
+			},
+		},
+		{
+			name: "yaml body",
+			resultFunc: func() bool {
+				var target map[string]string
+				err := clink.DecodeResponse(newResponse("application/x-yaml", "key: value\n"), &target)
+				return err == nil && target["key"] == "value"
+			},
+		},
+		{
+			name: "form body",
+			resultFunc: func() bool {
+				var target url.Values
+				err := clink.DecodeResponse(newResponse("application/x-www-form-urlencoded", `key=value`), &target)
+				return err == nil && target.Get("key") == "value"
+			},
+		},
+		{
+			name: "form body into map",
+			resultFunc: func() bool {
+				var target map[string]string
+				err := clink.DecodeResponse(newResponse("application/x-www-form-urlencoded", `key=value`), &target)
+				return err == nil && target["key"] == "value"
+			},
+		},
+		{
+			name: "text body",
+			resultFunc: func() bool {
+				var target string
+				err := clink.DecodeResponse(newResponse("text/plain", `value`), &target)
+				return err == nil && target == "value"
+			},
+		},
+		{
+			name: "missing content type falls back to json",
+			resultFunc: func() bool {
+				var target item
+				err := clink.DecodeResponse(newResponse("", `{"key": "value"}`), &target)
+				return err == nil && target.Key == "value"
+			},
+		},
+		{
+			name: "custom fallback content type",
+			resultFunc: func() bool {
+				var target []byte
+				err := clink.DecodeResponse(newResponse("application/octet-stream", `value`), &target, clink.FallbackContentType("text/plain"))
+				return err == nil && string(target) == "value"
+			},
+		},
+		{
+			name: "unsupported fallback content type",
+			resultFunc: func() bool {
+				var target item
+				err := clink.DecodeResponse(newResponse("application/octet-stream", `value`), &target, clink.FallbackContentType("image/png"))
+				return errors.Is(err, clink.ErrUnsupportedContentType)
+			},
+		},
+		{
+			name: "text into unsupported target",
+			resultFunc: func() bool {
+				var target item
+				err := clink.DecodeResponse(newResponse("text/plain", `value`), &target)
+				return err != nil && strings.Contains(err.Error(), "failed to decode response")
+			},
+		},
+		{
+			name: "disallow unknown fields",
+			resultFunc: func() bool {
+				var target item
+				err := clink.DecodeResponse(newResponse("application/json", `{"key": "value", "other": 1}`), &target, clink.DisallowUnknownFields())
+				return err != nil && strings.Contains(err.Error(), "unknown field")
+			},
+		},
+		{
+			name: "use number",
+			resultFunc: func() bool {
+				var target map[string]any
+				err := clink.DecodeResponse(newResponse("application/json", `{"id": 9007199254740993}`), &target, clink.UseNumber())
+				return err == nil && target["id"] == json.Number("9007199254740993")
+			},
+		},
+		{
+			name: "strict codec rejects trailing data",
+			resultFunc: func() bool {
+				var target item
+				err := clink.DecodeResponse(newResponse("application/json", `{"key": "value"} {}`), &target, clink.UseNumber())
+				return err != nil
+			},
+		},
+		{
+			name: "response is nil",
+			resultFunc: func() bool {
+				var target item
+				err := clink.DecodeResponse(nil, &target)
+				return err != nil && err.Error() == "response is nil"
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if !tc.resultFunc() {
+				t.Errorf("expected result to be successful")
+			}
+		})
+	}
+}
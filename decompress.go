@@ -0,0 +1,133 @@
+package clink
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// ContentDecoder decodes response bodies with a content coding, for WithDecompression.
+// Decoders for brotli and zstd are provided by the compress/brotli and compress/zstd packages;
+// other codings can be implemented on top of third-party libraries.
+type ContentDecoder interface {
+	// Encoding returns the content coding decoded, as written in the Content-Encoding header.
+	Encoding() string
+	// NewReader returns a reader decoding r.
+	NewReader(r io.Reader) (io.ReadCloser, error)
+}
+
+// GzipDecoder decodes gzip response bodies.
+type GzipDecoder struct{}
+
+func (GzipDecoder) Encoding() string {
+	return "gzip"
+}
+
+func (GzipDecoder) NewReader(r io.Reader) (io.ReadCloser, error) {
+	return gzip.NewReader(r)
+}
+
+// DeflateDecoder decodes deflate (zlib) response bodies.
+type DeflateDecoder struct{}
+
+func (DeflateDecoder) Encoding() string {
+	return "deflate"
+}
+
+func (DeflateDecoder) NewReader(r io.Reader) (io.ReadCloser, error) {
+	return zlib.NewReader(r)
+}
+
+// WithDecompression advertises the content codings of the decoders in the Accept-Encoding header of
+// requests, in the given order of preference, and transparently decodes response bodies encoded
+// with one of them. It replaces the gzip decompression of http.Transport, so GzipDecoder should be
+// among the decoders. Requests with an Accept-Encoding or Range header, and HEAD requests, are
+// sent as they are and their responses aren't decoded.
+//
+// Without it, the client only advertises and decodes gzip. To accept gzip, brotli and zstd:
+//
+//	clink.WithDecompression(clink.GzipDecoder{}, brotli.Decoder{}, zstd.Decoder{})
+func WithDecompression(decoders ...ContentDecoder) Option {
+	return func(c *Client) {
+		if len(decoders) == 0 {
+			c.addConfigError("WithDecompression: no decoder given")
+			return
+		}
+
+		d := &decompression{decoders: make(map[string]ContentDecoder, len(decoders))}
+		encodings := make([]string, 0, len(decoders))
+		for _, decoder := range decoders {
+			encoding := strings.ToLower(decoder.Encoding())
+			d.decoders[encoding] = decoder
+			encodings = append(encodings, encoding)
+		}
+		d.acceptEncoding = strings.Join(encodings, ", ")
+
+		c.decompression = d
+	}
+}
+
+type decompression struct {
+	decoders       map[string]ContentDecoder
+	acceptEncoding string
+}
+
+// prepare returns a copy of the request advertising the decoded content codings, and whether its
+// response should be decoded.
+func (d *decompression) prepare(req *http.Request) (*http.Request, bool) {
+	if d == nil || req.Method == http.MethodHead ||
+		req.Header.Get("Accept-Encoding") != "" || req.Header.Get("Range") != "" {
+		return req, false
+	}
+
+	req = req.Clone(req.Context())
+	req.Header.Set("Accept-Encoding", d.acceptEncoding)
+
+	return req, true
+}
+
+// decode replaces the body of the response by its decoded content if it is encoded with one of the
+// content codings of the decoders.
+func (d *decompression) decode(resp *http.Response) {
+	decoder, ok := d.decoders[strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding")))]
+	if !ok || resp.Body == nil || resp.Body == http.NoBody {
+		return
+	}
+
+	resp.Body = &decodedBody{body: resp.Body, decoder: decoder}
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	resp.Uncompressed = true
+}
+
+// decodedBody decodes a response body, creating the decoder on the first read so that empty
+// bodies can be closed without being decoded.
+type decodedBody struct {
+	body    io.ReadCloser
+	decoder ContentDecoder
+	reader  io.ReadCloser
+	err     error
+}
+
+func (b *decodedBody) Read(p []byte) (int, error) {
+	if b.reader == nil && b.err == nil {
+		b.reader, b.err = b.decoder.NewReader(b.body)
+	}
+
+	if b.err != nil {
+		return 0, b.err
+	}
+
+	return b.reader.Read(p)
+}
+
+func (b *decodedBody) Close() error {
+	if b.reader != nil {
+		_ = b.reader.Close()
+	}
+
+	return b.body.Close()
+}
@@ -0,0 +1,99 @@
+package clink_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/davesavic/clink"
+)
+
+func TestWithDecompression(t *testing.T) {
+	encode := func(encoding, body string) []byte {
+		var buf bytes.Buffer
+		var w io.WriteCloser
+		switch encoding {
+		case "gzip":
+			w = gzip.NewWriter(&buf)
+		case "deflate":
+			w = zlib.NewWriter(&buf)
+		default:
+			return []byte(body)
+		}
+		_, _ = w.Write([]byte(body))
+		_ = w.Close()
+		return buf.Bytes()
+	}
+
+	testCases := []struct {
+		name           string
+		decoders       []clink.ContentDecoder
+		acceptEncoding string
+		resultFunc     func(accepted string, resp *http.Response, body string) bool
+	}{
+		{
+			name:     "gzip",
+			decoders: []clink.ContentDecoder{clink.GzipDecoder{}, clink.DeflateDecoder{}},
+			resultFunc: func(accepted string, resp *http.Response, body string) bool {
+				return accepted == "gzip, deflate" && body == "hello" && resp.Header.Get("Content-Encoding") == "" && resp.Uncompressed
+			},
+		},
+		{
+			name:     "deflate preferred",
+			decoders: []clink.ContentDecoder{clink.DeflateDecoder{}, clink.GzipDecoder{}},
+			resultFunc: func(accepted string, resp *http.Response, body string) bool {
+				return accepted == "deflate, gzip" && body == "hello"
+			},
+		},
+		{
+			name:           "accept encoding set on request",
+			decoders:       []clink.ContentDecoder{clink.GzipDecoder{}},
+			acceptEncoding: "identity",
+			resultFunc: func(accepted string, resp *http.Response, body string) bool {
+				return accepted == "identity" && body == "hello" && !resp.Uncompressed
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var accepted string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				accepted = r.Header.Get("Accept-Encoding")
+				encoding, _, _ := strings.Cut(accepted, ",")
+				if encoding != "identity" {
+					w.Header().Set("Content-Encoding", encoding)
+				}
+				_, _ = w.Write(encode(encoding, "hello"))
+			}))
+			defer server.Close()
+
+			c := clink.NewClient(clink.WithDecompression(tc.decoders...))
+
+			req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+			if tc.acceptEncoding != "" {
+				req.Header.Set("Accept-Encoding", tc.acceptEncoding)
+			}
+
+			resp, err := c.Do(req)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			defer resp.Body.Close()
+
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatalf("failed to read body: %v", err)
+			}
+
+			if !tc.resultFunc(accepted, resp, string(body)) {
+				t.Errorf("unexpected result: %q, %q", accepted, body)
+			}
+		})
+	}
+}
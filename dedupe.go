@@ -0,0 +1,149 @@
+package clink
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// WithRequestDeduplication coalesces concurrent identical GET requests (same URL and headers,
+// other than the request ID and idempotency key headers) into a single upstream request. The
+// response body is read into memory once and every caller receives its own copy of the response.
+// Callers waiting for another caller's request can stop waiting when their own context is done,
+// and send the request again themselves if the other caller's context is done first.
+func WithRequestDeduplication() Option {
+	return func(c *Client) {
+		c.inflight = &flightGroup{calls: make(map[string]*flightCall)}
+	}
+}
+
+// flightGroup tracks in-flight requests by key.
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*flightCall
+}
+
+type flightCall struct {
+	done     chan struct{}
+	resp     *http.Response
+	body     []byte
+	attempts int
+	err      error
+	// cancelled is set when the request failed because the context of the caller sending it was done.
+	cancelled bool
+}
+
+// do executes fn once for all concurrent callers with the same request key. Headers in ignored
+// aren't part of the key.
+func (g *flightGroup) do(req *http.Request, ignored []string, fn func() (*http.Response, int, error)) (*http.Response, int, error) {
+	key := flightKey(req, ignored)
+
+	for {
+		call, err := g.join(req, key, fn)
+		if err != nil {
+			return nil, 0, err
+		}
+		if call.cancelled && req.Context().Err() == nil {
+			continue
+		}
+
+		return call.share(req)
+	}
+}
+
+// join executes fn if no call with the key is in flight, or waits for the one in flight.
+func (g *flightGroup) join(req *http.Request, key string, fn func() (*http.Response, int, error)) (*flightCall, error) {
+	g.mu.Lock()
+	call, ok := g.calls[key]
+	if !ok {
+		call = &flightCall{done: make(chan struct{})}
+		g.calls[key] = call
+	}
+	g.mu.Unlock()
+
+	if !ok {
+		g.lead(req, key, call, fn)
+	} else {
+		select {
+		case <-call.done:
+		case <-req.Context().Done():
+			return nil, fmt.Errorf("request context error: %w", req.Context().Err())
+		}
+	}
+
+	return call, nil
+}
+
+// errFlightAborted is returned to the callers waiting for a request whose sender panicked.
+var errFlightAborted = errors.New("deduplicated request aborted")
+
+// lead executes fn for the call and releases the callers waiting for it, even if fn panics.
+func (g *flightGroup) lead(req *http.Request, key string, call *flightCall, fn func() (*http.Response, int, error)) {
+	completed := false
+	defer func() {
+		if !completed {
+			call.err = errFlightAborted
+		}
+
+		g.mu.Lock()
+		delete(g.calls, key)
+		g.mu.Unlock()
+		close(call.done)
+	}()
+
+	call.resp, call.attempts, call.err = fn()
+	if call.err == nil || errors.Is(call.err, errRetriesExhausted) {
+		var err error
+		call.body, err = io.ReadAll(call.resp.Body)
+		_ = call.resp.Body.Close()
+		if err != nil {
+			call.err = fmt.Errorf("failed to read response body: %w", err)
+		}
+	}
+	call.cancelled = call.err != nil && req.Context().Err() != nil
+	completed = true
+}
+
+// share returns a copy of the call's response for req.
+func (call *flightCall) share(req *http.Request) (*http.Response, int, error) {
+	if call.err != nil && !errors.Is(call.err, errRetriesExhausted) {
+		return nil, call.attempts, call.err
+	}
+
+	resp := *call.resp
+	resp.Header = call.resp.Header.Clone()
+	resp.Body = io.NopCloser(bytes.NewReader(call.body))
+	resp.Request = req
+
+	return &resp, call.attempts, call.err
+}
+
+// flightKey identifies identical requests by method, URL and headers, other than the ignored ones.
+func flightKey(req *http.Request, ignored []string) string {
+	names := make([]string, 0, len(req.Header))
+	for name := range req.Header {
+		if !slices.ContainsFunc(ignored, func(header string) bool { return strings.EqualFold(header, name) }) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	b.WriteString(req.Method)
+	b.WriteString(" ")
+	b.WriteString(req.URL.String())
+	for _, name := range names {
+		b.WriteString("\n")
+		b.WriteString(name)
+		b.WriteString(": ")
+		b.WriteString(strings.Join(req.Header[name], ", "))
+	}
+
+	return b.String()
+}
@@ -0,0 +1,180 @@
+package clink_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/davesavic/clink"
+)
+
+func TestRequestDeduplication(t *testing.T) {
+	testCases := []struct {
+		name     string
+		opts     []clink.Option
+		header   func(i int) string
+		expected int32
+	}{
+		{
+			name:     "identical requests are coalesced",
+			opts:     []clink.Option{clink.WithRequestDeduplication()},
+			header:   func(i int) string { return "same" },
+			expected: 1,
+		},
+		{
+			name:     "requests with different headers are not coalesced",
+			opts:     []clink.Option{clink.WithRequestDeduplication()},
+			header:   func(i int) string { return string(rune('a' + i)) },
+			expected: 5,
+		},
+		{
+			name:     "requests with different request IDs are coalesced",
+			opts:     []clink.Option{clink.WithRequestDeduplication(), clink.WithRequestID(nil, "X-Request-ID")},
+			header:   func(i int) string { return "same" },
+			expected: 1,
+		},
+		{
+			name:     "requests are not coalesced without option",
+			header:   func(i int) string { return "same" },
+			expected: 5,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var requestCount int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&requestCount, 1)
+				time.Sleep(100 * time.Millisecond)
+				_, _ = w.Write([]byte("response"))
+			}))
+			defer server.Close()
+
+			client := clink.NewClient(append(tc.opts, clink.WithClient(server.Client()))...)
+
+			var wg sync.WaitGroup
+			for i := 0; i < 5; i++ {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+
+					req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+					req.Header.Set("X-Tenant", tc.header(i))
+
+					resp, err := client.Do(req)
+					if err != nil {
+						t.Errorf("failed to make request: %v", err)
+						return
+					}
+
+					body, _ := io.ReadAll(resp.Body)
+					if string(body) != "response" {
+						t.Errorf("expected every caller to receive the body, got: %s", body)
+					}
+
+					if resp.Request.Header.Get("X-Tenant") != tc.header(i) {
+						t.Errorf("expected response to reference the caller request")
+					}
+				}(i)
+			}
+			wg.Wait()
+
+			if n := atomic.LoadInt32(&requestCount); n != tc.expected {
+				t.Errorf("expected %d upstream requests, got: %d", tc.expected, n)
+			}
+		})
+	}
+}
+
+func TestRequestDeduplication_LeaderCancelled(t *testing.T) {
+	var requestCount int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requestCount, 1)
+		select {
+		case <-time.After(100 * time.Millisecond):
+			_, _ = w.Write([]byte("response"))
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+
+	client := clink.NewClient(clink.WithRequestDeduplication(), clink.WithClient(server.Client()))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	leader := make(chan error, 1)
+	go func() {
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+		_, err := client.Do(req)
+		leader <- err
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	follower := make(chan error, 1)
+	go func() {
+		req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+		resp, err := client.Do(req)
+		if err == nil {
+			body, _ := io.ReadAll(resp.Body)
+			if string(body) != "response" {
+				t.Errorf("expected the follower to receive the body, got: %s", body)
+			}
+		}
+		follower <- err
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+
+	if err := <-leader; err == nil {
+		t.Error("expected the cancelled leader to fail")
+	}
+	if err := <-follower; err != nil {
+		t.Errorf("expected the follower to send the request again, got: %v", err)
+	}
+	if n := atomic.LoadInt32(&requestCount); n != 2 {
+		t.Errorf("expected 2 upstream requests, got: %d", n)
+	}
+}
+
+func TestRequestDeduplication_LeaderPanics(t *testing.T) {
+	release := make(chan struct{})
+	client := clink.NewClient(clink.WithRequestDeduplication(), clink.WithTransport(roundTripperFunc(func(*http.Request) (*http.Response, error) {
+		<-release
+		panic("transport failure")
+	})))
+
+	leader := make(chan any, 1)
+	go func() {
+		defer func() { leader <- recover() }()
+		req, _ := http.NewRequest(http.MethodGet, "http://example.com", nil)
+		_, _ = client.Do(req)
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	follower := make(chan error, 1)
+	go func() {
+		req, _ := http.NewRequest(http.MethodGet, "http://example.com", nil)
+		_, err := client.Do(req)
+		follower <- err
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+
+	if r := <-leader; r == nil {
+		t.Error("expected the panic to reach the leader")
+	}
+	select {
+	case err := <-follower:
+		if err == nil {
+			t.Error("expected the follower to fail")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the follower to be released")
+	}
+}
@@ -0,0 +1,393 @@
+package clink
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Default settings of a DeliveryQueue, unless set in DeliveryOptions.
+const (
+	DefaultDeliveryMaxAttempts = 10
+	DefaultDeliveryBackoff     = time.Second
+	MaxDeliveryBackoff         = 5 * time.Minute
+)
+
+// Delivery is a request stored in a DeliveryStore until it has been delivered. Its Header doesn't
+// hold the headers redacted by the client's Redactor.
+type Delivery struct {
+	ID          string      `json:"id"`
+	Method      string      `json:"method"`
+	URL         string      `json:"url"`
+	Header      http.Header `json:"header,omitempty"`
+	Body        []byte      `json:"body,omitempty"`
+	Attempts    int         `json:"attempts"`
+	NextAttempt time.Time   `json:"next_attempt"`
+}
+
+// DeliveryStore stores the pending deliveries of a DeliveryQueue. Implementations must be safe for
+// concurrent use. A persistent store lets deliveries survive process restarts.
+type DeliveryStore interface {
+	// Save inserts the delivery, or replaces the delivery with the same ID.
+	Save(ctx context.Context, d Delivery) error
+	// Delete removes the delivery with the given ID.
+	Delete(ctx context.Context, id string) error
+	// List returns all the stored deliveries.
+	List(ctx context.Context) ([]Delivery, error)
+}
+
+// DeliveryOptions configures a DeliveryQueue.
+type DeliveryOptions struct {
+	// MaxAttempts is the number of attempts after which a delivery fails.
+	// DefaultDeliveryMaxAttempts is used if it is not positive.
+	MaxAttempts int
+	// Backoff is the delay before the second attempt, doubled after each attempt up to
+	// MaxDeliveryBackoff. DefaultDeliveryBackoff is used if it is not positive.
+	Backoff time.Duration
+	// OnFailure is called with deliveries that failed permanently, either because the server
+	// rejected them with a 4xx status or because they ran out of attempts.
+	OnFailure func(Delivery, error)
+}
+
+// DeliveryQueue sends requests in the background on a best-effort basis, for requests such as
+// analytics events whose response isn't needed. Requests are kept in a store and retried until
+// they are delivered, including after a restart when the store is persistent.
+type DeliveryQueue struct {
+	client *Client
+	store  DeliveryStore
+	opts   DeliveryOptions
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wake   chan struct{}
+	done   chan struct{}
+
+	mu     sync.RWMutex
+	closed bool
+}
+
+// NewDeliveryQueue creates a delivery queue sending requests with the client, and starts
+// delivering the requests already in the store.
+func (c *Client) NewDeliveryQueue(store DeliveryStore, opts DeliveryOptions) *DeliveryQueue {
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = DefaultDeliveryMaxAttempts
+	}
+
+	if opts.Backoff <= 0 {
+		opts.Backoff = DefaultDeliveryBackoff
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	q := &DeliveryQueue{
+		client: c,
+		store:  store,
+		opts:   opts,
+		ctx:    ctx,
+		cancel: cancel,
+		wake:   make(chan struct{}, 1),
+		done:   make(chan struct{}),
+	}
+
+	c.deliveries.add(q)
+	go q.run()
+
+	return q
+}
+
+// Enqueue stores the request for delivery and returns once it is stored. The request body is read
+// and closed. Enqueue returns ErrQueueClosed after Close.
+//
+// Headers redacted by the client's Redactor, such as Authorization and Cookie, are not stored, so
+// that credentials aren't persisted in the store: credentials must be set on the client (for
+// example with WithBearerAuth), whose headers are added when the request is sent.
+func (q *DeliveryQueue) Enqueue(req *http.Request) error {
+	var body []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return fmt.Errorf("failed to read request body: %w", err)
+		}
+	}
+
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	if q.closed {
+		return ErrQueueClosed
+	}
+
+	d := Delivery{
+		ID:          newUUID(),
+		Method:      req.Method,
+		URL:         req.URL.String(),
+		Header:      q.storedHeader(req.Header),
+		Body:        body,
+		NextAttempt: q.client.clock.Now(),
+	}
+
+	if err := q.store.Save(req.Context(), d); err != nil {
+		return fmt.Errorf("failed to store delivery: %w", err)
+	}
+
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+
+	return nil
+}
+
+// storedHeader returns a copy of the header without the headers redacted by the client's Redactor.
+func (q *DeliveryQueue) storedHeader(header http.Header) http.Header {
+	stored := header.Clone()
+	for name := range stored {
+		if q.client.Redactor.RedactsHeader(name) {
+			delete(stored, name)
+		}
+	}
+
+	return stored
+}
+
+// deliveryQueues are the delivery queues of a client, closed when the client is shut down.
+type deliveryQueues struct {
+	mu     sync.Mutex
+	queues []*DeliveryQueue
+}
+
+func (d *deliveryQueues) add(q *DeliveryQueue) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.queues = append(d.queues, q)
+}
+
+func (d *deliveryQueues) close(ctx context.Context) error {
+	d.mu.Lock()
+	queues := d.queues
+	d.queues = nil
+	d.mu.Unlock()
+
+	var errs []error
+	for _, q := range queues {
+		errs = append(errs, q.Close(ctx))
+	}
+
+	return errors.Join(errs...)
+}
+
+// Close stops the delivery of requests and waits for the current attempt to finish, or until ctx
+// is done, in which case the attempt is cancelled. Undelivered requests stay in the store.
+func (q *DeliveryQueue) Close(ctx context.Context) error {
+	q.mu.Lock()
+	if !q.closed {
+		q.closed = true
+		close(q.wake)
+	}
+	q.mu.Unlock()
+
+	select {
+	case <-q.done:
+		return nil
+	case <-ctx.Done():
+		q.cancel()
+		<-q.done
+		return ctx.Err()
+	}
+}
+
+func (q *DeliveryQueue) run() {
+	defer close(q.done)
+	defer q.cancel()
+
+	for {
+		// Deliveries are waited for with the client clock. Without pending deliveries, the store
+		// is still checked every hour of real time for deliveries saved by other processes.
+		var timer <-chan time.Time
+		poll := time.NewTimer(deliveryPollInterval)
+		if wait, pending := q.deliverDue(); pending {
+			timer = q.client.clock.After(wait)
+		}
+
+		select {
+		case _, ok := <-q.wake:
+			poll.Stop()
+			if !ok {
+				return
+			}
+		case <-timer:
+			poll.Stop()
+		case <-poll.C:
+		}
+	}
+}
+
+// deliveryPollInterval is the interval at which an idle DeliveryQueue checks its store.
+const deliveryPollInterval = time.Hour
+
+// deliverDue attempts the deliveries that are due and returns the delay until the next one, if
+// any delivery is pending.
+func (q *DeliveryQueue) deliverDue() (time.Duration, bool) {
+	deliveries, err := q.store.List(q.ctx)
+	if err != nil {
+		q.logError("failed to list deliveries", err)
+		return q.opts.Backoff, true
+	}
+
+	sort.Slice(deliveries, func(i, j int) bool {
+		return deliveries[i].NextAttempt.Before(deliveries[j].NextAttempt)
+	})
+
+	var pending bool
+	next := time.Duration(math.MaxInt64)
+	now := q.client.clock.Now()
+	for _, d := range deliveries {
+		if q.closing() {
+			break
+		}
+
+		if wait := d.NextAttempt.Sub(now); wait > 0 {
+			next = min(next, wait)
+			pending = true
+			continue
+		}
+
+		if retryAt, retry := q.attempt(d); retry {
+			next = min(next, retryAt.Sub(q.client.clock.Now()))
+			pending = true
+		}
+	}
+
+	return max(next, 0), pending
+}
+
+// attempt sends the delivery and removes or reschedules it. It returns the time of the next
+// attempt if the delivery has to be retried.
+func (q *DeliveryQueue) attempt(d Delivery) (time.Time, bool) {
+	err := q.send(d)
+	if err == nil {
+		q.remove(d)
+		return time.Time{}, false
+	}
+
+	if q.ctx.Err() != nil {
+		return time.Time{}, false
+	}
+
+	d.Attempts++
+
+	var httpErr *HTTPError
+	permanent := errors.As(err, &httpErr) && httpErr.StatusCode >= 400 && httpErr.StatusCode < 500 &&
+		httpErr.StatusCode != http.StatusRequestTimeout && httpErr.StatusCode != http.StatusTooManyRequests
+
+	if permanent || d.Attempts >= q.opts.MaxAttempts {
+		q.remove(d)
+		if q.opts.OnFailure != nil {
+			q.opts.OnFailure(d, err)
+		}
+		return time.Time{}, false
+	}
+
+	backoff := min(q.opts.Backoff<<(d.Attempts-1), MaxDeliveryBackoff)
+	if backoff <= 0 {
+		backoff = MaxDeliveryBackoff
+	}
+	d.NextAttempt = q.client.clock.Now().Add(backoff)
+
+	if err := q.store.Save(q.ctx, d); err != nil {
+		q.logError("failed to store delivery", err)
+	}
+
+	return d.NextAttempt, true
+}
+
+func (q *DeliveryQueue) send(d Delivery) error {
+	req, err := http.NewRequestWithContext(q.ctx, d.Method, d.URL, bytes.NewReader(d.Body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header = d.Header.Clone()
+	if req.Header == nil {
+		req.Header = make(http.Header)
+	}
+
+	resp, err := q.client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	if !isSuccessStatus(resp) {
+		return newHTTPError(resp, q.client.Redactor)
+	}
+
+	return DrainAndClose(resp)
+}
+
+func (q *DeliveryQueue) remove(d Delivery) {
+	if err := q.store.Delete(q.ctx, d.ID); err != nil {
+		q.logError("failed to delete delivery", err)
+	}
+}
+
+func (q *DeliveryQueue) closing() bool {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	return q.closed || q.ctx.Err() != nil
+}
+
+func (q *DeliveryQueue) logError(msg string, err error) {
+	if q.client.Logger != nil {
+		q.client.Logger.Error(msg, "error", err)
+	}
+}
+
+// MemoryDeliveryStore is an in-memory DeliveryStore. Its deliveries don't survive restarts.
+type MemoryDeliveryStore struct {
+	mu         sync.Mutex
+	deliveries map[string]Delivery
+}
+
+// NewMemoryDeliveryStore creates a new in-memory delivery store.
+func NewMemoryDeliveryStore() *MemoryDeliveryStore {
+	return &MemoryDeliveryStore{deliveries: make(map[string]Delivery)}
+}
+
+func (m *MemoryDeliveryStore) Save(_ context.Context, d Delivery) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.deliveries[d.ID] = d
+
+	return nil
+}
+
+func (m *MemoryDeliveryStore) Delete(_ context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.deliveries, id)
+
+	return nil
+}
+
+func (m *MemoryDeliveryStore) List(_ context.Context) ([]Delivery, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	deliveries := make([]Delivery, 0, len(m.deliveries))
+	for _, d := range m.deliveries {
+		deliveries = append(deliveries, d)
+	}
+
+	return deliveries, nil
+}
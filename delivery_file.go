@@ -0,0 +1,90 @@
+package clink
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// FileDeliveryStore is a DeliveryStore keeping each delivery in a JSON file of a directory,
+// so that deliveries survive process restarts.
+type FileDeliveryStore struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// NewFileDeliveryStore creates a file delivery store in the given directory.
+func NewFileDeliveryStore(dir string) (*FileDeliveryStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create delivery directory: %w", err)
+	}
+
+	return &FileDeliveryStore{dir: dir}, nil
+}
+
+func (f *FileDeliveryStore) Save(_ context.Context, d Delivery) error {
+	data, err := json.Marshal(d)
+	if err != nil {
+		return fmt.Errorf("failed to encode delivery: %w", err)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := writeFileAtomic(f.path(d.ID), data); err != nil {
+		return fmt.Errorf("failed to write delivery: %w", err)
+	}
+
+	return nil
+}
+
+func (f *FileDeliveryStore) Delete(_ context.Context, id string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := os.Remove(f.path(id)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to delete delivery: %w", err)
+	}
+
+	return nil
+}
+
+func (f *FileDeliveryStore) List(_ context.Context) ([]Delivery, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	entries, err := os.ReadDir(f.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read delivery directory: %w", err)
+	}
+
+	var deliveries []Delivery
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(f.dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read delivery: %w", err)
+		}
+
+		var d Delivery
+		if err := json.Unmarshal(data, &d); err != nil {
+			return nil, fmt.Errorf("failed to decode delivery %s: %w", entry.Name(), err)
+		}
+		deliveries = append(deliveries, d)
+	}
+
+	return deliveries, nil
+}
+
+func (f *FileDeliveryStore) path(id string) string {
+	return filepath.Join(f.dir, filepath.Base(id)+".json")
+}
@@ -0,0 +1,272 @@
+package clink_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/davesavic/clink"
+)
+
+func TestDeliveryQueue(t *testing.T) {
+	testCases := []struct {
+		name       string
+		statuses   []int
+		resultFunc func(*testing.T, []string, []error)
+	}{
+		{
+			name:     "delivered after retries",
+			statuses: []int{http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusOK},
+			resultFunc: func(t *testing.T, bodies []string, failures []error) {
+				if len(bodies) != 3 || bodies[2] != `{"event":"signup"}` {
+					t.Errorf("expected 3 attempts with the request body, got: %q", bodies)
+				}
+
+				if len(failures) != 0 {
+					t.Errorf("expected no failures, got: %v", failures)
+				}
+			},
+		},
+		{
+			name:     "permanent failure",
+			statuses: []int{http.StatusBadRequest},
+			resultFunc: func(t *testing.T, bodies []string, failures []error) {
+				var httpErr *clink.HTTPError
+				if len(failures) != 1 || !errors.As(failures[0], &httpErr) || httpErr.StatusCode != http.StatusBadRequest {
+					t.Errorf("expected a single 400 failure, got: %v", failures)
+				}
+			},
+		},
+		{
+			name:     "out of attempts",
+			statuses: []int{http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusServiceUnavailable},
+			resultFunc: func(t *testing.T, bodies []string, failures []error) {
+				if len(bodies) != 3 {
+					t.Errorf("expected 3 attempts, got: %d", len(bodies))
+				}
+
+				if len(failures) != 1 {
+					t.Errorf("expected a single failure, got: %v", failures)
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var bodies []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+
+				mu.Lock()
+				defer mu.Unlock()
+				w.WriteHeader(tc.statuses[min(len(bodies), len(tc.statuses)-1)])
+				bodies = append(bodies, string(body))
+			}))
+			defer server.Close()
+
+			var failures []error
+			store := clink.NewMemoryDeliveryStore()
+			q := clink.NewClient().NewDeliveryQueue(store, clink.DeliveryOptions{
+				MaxAttempts: 3,
+				Backoff:     5 * time.Millisecond,
+				OnFailure: func(_ clink.Delivery, err error) {
+					failures = append(failures, err)
+				},
+			})
+
+			req, _ := http.NewRequest(http.MethodPost, server.URL, strings.NewReader(`{"event":"signup"}`))
+			if err := q.Enqueue(req); err != nil {
+				t.Fatalf("failed to enqueue: %v", err)
+			}
+
+			// Close waits for the delivery loop, so the failures are recorded once it returns.
+			waitForDeliveries(t, store)
+			if err := q.Close(context.Background()); err != nil {
+				t.Fatalf("failed to close: %v", err)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			tc.resultFunc(t, bodies, failures)
+		})
+	}
+}
+
+func TestDeliveryQueue_Closed(t *testing.T) {
+	q := clink.NewClient().NewDeliveryQueue(clink.NewMemoryDeliveryStore(), clink.DeliveryOptions{})
+	if err := q.Close(context.Background()); err != nil {
+		t.Fatalf("failed to close: %v", err)
+	}
+
+	req, _ := http.NewRequest(http.MethodPost, "http://example.com", nil)
+	if err := q.Enqueue(req); !errors.Is(err, clink.ErrQueueClosed) {
+		t.Errorf("expected ErrQueueClosed, got: %v", err)
+	}
+}
+
+func TestDeliveryQueue_Restart(t *testing.T) {
+	var available atomic.Bool
+	var delivered atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if !available.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		delivered.Add(1)
+	}))
+	defer server.Close()
+
+	store, err := clink.NewFileDeliveryStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+
+	opts := clink.DeliveryOptions{Backoff: time.Hour}
+	q := clink.NewClient().NewDeliveryQueue(store, opts)
+
+	req, _ := http.NewRequest(http.MethodPost, server.URL, strings.NewReader("event"))
+	if err := q.Enqueue(req); err != nil {
+		t.Fatalf("failed to enqueue: %v", err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		deliveries, _ := store.List(context.Background())
+		if len(deliveries) == 1 && deliveries[0].Attempts == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected a failed attempt to be stored, got: %+v", deliveries)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if err := q.Close(context.Background()); err != nil {
+		t.Fatalf("failed to close: %v", err)
+	}
+
+	// The stored delivery is due again, as it would be after its backoff.
+	deliveries, _ := store.List(context.Background())
+	deliveries[0].NextAttempt = time.Now()
+	_ = store.Save(context.Background(), deliveries[0])
+
+	available.Store(true)
+	q = clink.NewClient().NewDeliveryQueue(store, opts)
+	defer q.Close(context.Background())
+
+	waitForDeliveries(t, store)
+
+	if delivered.Load() != 1 {
+		t.Errorf("expected the stored request to be delivered after the restart, got: %d", delivered.Load())
+	}
+}
+
+type recordingDeliveryStore struct {
+	*clink.MemoryDeliveryStore
+	mu      sync.Mutex
+	headers []http.Header
+}
+
+func (s *recordingDeliveryStore) Save(ctx context.Context, d clink.Delivery) error {
+	s.mu.Lock()
+	s.headers = append(s.headers, d.Header)
+	s.mu.Unlock()
+
+	return s.MemoryDeliveryStore.Save(ctx, d)
+}
+
+func TestDeliveryQueue_Credentials(t *testing.T) {
+	var authorization atomic.Value
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		authorization.Store(r.Header.Get("Authorization"))
+	}))
+	defer server.Close()
+
+	store := &recordingDeliveryStore{MemoryDeliveryStore: clink.NewMemoryDeliveryStore()}
+	q := clink.NewClient(clink.WithBearerAuth("client-token")).NewDeliveryQueue(store, clink.DeliveryOptions{})
+	defer q.Close(context.Background())
+
+	req, _ := http.NewRequest(http.MethodPost, server.URL, strings.NewReader("event"))
+	req.Header.Set("Authorization", "Bearer request-token")
+	req.Header.Set("X-Event", "signup")
+	if err := q.Enqueue(req); err != nil {
+		t.Fatalf("failed to enqueue: %v", err)
+	}
+
+	waitForDeliveries(t, store)
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	if len(store.headers) != 1 || store.headers[0].Get("Authorization") != "" || store.headers[0].Get("X-Event") != "signup" {
+		t.Errorf("expected only the non-sensitive headers to be stored, got: %v", store.headers)
+	}
+
+	if got := authorization.Load(); got != "Bearer client-token" {
+		t.Errorf("expected the client credentials to be sent, got: %v", got)
+	}
+}
+
+func TestDeliveryQueue_Clock(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	failed := make(chan clink.Delivery, 1)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	q := clink.NewClient(clink.WithClock(&manualClock{now: start})).NewDeliveryQueue(clink.NewMemoryDeliveryStore(), clink.DeliveryOptions{
+		MaxAttempts: 3,
+		Backoff:     time.Minute,
+		OnFailure: func(d clink.Delivery, _ error) {
+			failed <- d
+		},
+	})
+	defer q.Close(context.Background())
+
+	req, _ := http.NewRequest(http.MethodPost, server.URL, nil)
+	if err := q.Enqueue(req); err != nil {
+		t.Fatalf("failed to enqueue: %v", err)
+	}
+
+	// The retries are waited for with the client clock, which advances instantly.
+	select {
+	case d := <-failed:
+		if d.Attempts != 3 {
+			t.Errorf("expected 3 attempts, got: %d", d.Attempts)
+		}
+		if !d.NextAttempt.Equal(start.Add(3 * time.Minute)) {
+			t.Errorf("expected the attempts to be scheduled with the client clock, got: %v", d.NextAttempt)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the delivery to fail without waiting for the backoff")
+	}
+}
+
+// waitForDeliveries waits until the store is empty.
+func waitForDeliveries(t *testing.T, store clink.DeliveryStore) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		deliveries, err := store.List(context.Background())
+		if err != nil {
+			t.Fatalf("failed to list deliveries: %v", err)
+		}
+		if len(deliveries) == 0 {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected all deliveries to be processed, got: %+v", deliveries)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
@@ -0,0 +1,158 @@
+package clink
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultDiscoveryInterval is the interval between endpoint discoveries, unless set with WithDiscovery.
+const DefaultDiscoveryInterval = 30 * time.Second
+
+// Discoverer supplies the current endpoints of a service, for example from DNS SRV records,
+// Consul or Kubernetes Endpoints.
+type Discoverer interface {
+	// Discover returns the base URLs of the endpoints of the service.
+	Discover(ctx context.Context) ([]string, error)
+}
+
+// DiscovererFunc adapts a function to a Discoverer.
+type DiscovererFunc func(ctx context.Context) ([]string, error)
+
+func (f DiscovererFunc) Discover(ctx context.Context) ([]string, error) {
+	return f(ctx)
+}
+
+// WithDiscovery replaces the endpoints set with WithEndpoints by the endpoints found by the
+// discoverer. Endpoints are discovered before the first request with a relative URL (again before
+// the next one if the context of the request is done before the discovery completes), then in the
+// background at the given interval (DefaultDiscoveryInterval if it is not positive), keeping the
+// state of the endpoints still present. If a discovery fails, the previous endpoints are kept.
+// Background discovery stops when Client.Shutdown is called.
+func WithDiscovery(discoverer Discoverer, interval time.Duration) Option {
+	return func(c *Client) {
+		if discoverer == nil {
+			c.addConfigError("WithDiscovery: discoverer must not be nil")
+			return
+		}
+
+		if interval <= 0 {
+			interval = DefaultDiscoveryInterval
+		}
+
+		c.endpointSet().discovery = &discovery{discoverer: discoverer, interval: interval, stop: make(chan struct{})}
+	}
+}
+
+type discovery struct {
+	discoverer Discoverer
+	interval   time.Duration
+	mu         sync.Mutex
+	discovered bool
+	start      sync.Once
+	stopOnce   sync.Once
+	stop       chan struct{}
+}
+
+// run discovers the endpoints until a discovery isn't interrupted by the context of the request,
+// and starts the background discovery.
+func (d *discovery) run(ctx context.Context, c *Client) {
+	d.mu.Lock()
+	if !d.discovered {
+		err := d.refresh(ctx, c)
+		d.discovered = err == nil || ctx.Err() == nil
+	}
+	d.mu.Unlock()
+
+	d.start.Do(func() {
+		go d.loop(c)
+	})
+}
+
+func (d *discovery) close() {
+	d.stopOnce.Do(func() {
+		close(d.stop)
+	})
+}
+
+func (d *discovery) loop(c *Client) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			_ = d.refresh(context.Background(), c)
+		case <-d.stop:
+			return
+		}
+	}
+}
+
+func (d *discovery) refresh(ctx context.Context, c *Client) error {
+	urls, err := d.discoverer.Discover(ctx)
+	if err != nil {
+		d.logError(c, "failed to discover endpoints", err)
+		return err
+	}
+
+	valid := urls[:0:0]
+	for _, u := range urls {
+		if !validEndpoint(u) {
+			d.logError(c, "discovered invalid endpoint", fmt.Errorf("invalid endpoint %q", u))
+			continue
+		}
+		valid = append(valid, u)
+	}
+
+	c.endpoints.update(valid)
+
+	return nil
+}
+
+func (d *discovery) logError(c *Client, msg string, err error) {
+	if c.Logger != nil {
+		c.Logger.Error(msg, "error", err)
+	}
+}
+
+// SRVDiscoverer is a Discoverer looking up the endpoints of a service in DNS SRV records
+// (see net.Resolver.LookupSRV), in the order of their priority and weight.
+type SRVDiscoverer struct {
+	Service string
+	Proto   string
+	Name    string
+	// Scheme is the scheme of the endpoint URLs, "http" if it is empty.
+	Scheme string
+	// Resolver is the resolver used for the lookups, net.DefaultResolver if it is nil.
+	Resolver *net.Resolver
+}
+
+func (d SRVDiscoverer) Discover(ctx context.Context) ([]string, error) {
+	resolver := d.Resolver
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+
+	scheme := d.Scheme
+	if scheme == "" {
+		scheme = "http"
+	}
+
+	_, records, err := resolver.LookupSRV(ctx, d.Service, d.Proto, d.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up SRV records: %w", err)
+	}
+
+	urls := make([]string, 0, len(records))
+	for _, record := range records {
+		host := strings.TrimSuffix(record.Target, ".")
+		urls = append(urls, scheme+"://"+net.JoinHostPort(host, strconv.Itoa(int(record.Port))))
+	}
+
+	return urls, nil
+}
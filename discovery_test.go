@@ -0,0 +1,164 @@
+package clink_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/davesavic/clink"
+)
+
+func TestWithDiscovery(t *testing.T) {
+	newServer := func(name string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(name))
+		}))
+	}
+
+	first := newServer("first")
+	defer first.Close()
+	second := newServer("second")
+	defer second.Close()
+
+	var current atomic.Value
+	current.Store([]string{first.URL})
+	discoverer := clink.DiscovererFunc(func(context.Context) ([]string, error) {
+		return current.Load().([]string), nil
+	})
+
+	c := clink.NewClient(
+		clink.WithEndpoints("http://static.clink.test"),
+		clink.WithDiscovery(discoverer, 10*time.Millisecond),
+	)
+	defer func() { _ = c.Shutdown(context.Background()) }()
+
+	get := func() string {
+		resp, err := c.Get("/")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return string(body)
+	}
+
+	if body := get(); body != "first" {
+		t.Errorf("expected the discovered endpoint to be used, got: %s", body)
+	}
+
+	current.Store([]string{second.URL})
+	deadline := time.Now().Add(time.Second)
+	for get() != "second" {
+		if time.Now().After(deadline) {
+			t.Fatal("expected the endpoints to be discovered again")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestWithDiscovery_NoEndpoints(t *testing.T) {
+	discoverer := clink.DiscovererFunc(func(context.Context) ([]string, error) {
+		return []string{"not a url"}, nil
+	})
+
+	c := clink.NewClient(clink.WithDiscovery(discoverer, time.Hour))
+	defer func() { _ = c.Shutdown(context.Background()) }()
+
+	if _, err := c.Get("/"); !errors.Is(err, clink.ErrNoEndpoints) {
+		t.Errorf("expected ErrNoEndpoints, got: %v", err)
+	}
+}
+
+func TestWithDiscovery_NilDiscoverer(t *testing.T) {
+	c := clink.NewClient(clink.WithDiscovery(nil, time.Hour))
+	defer func() { _ = c.Shutdown(context.Background()) }()
+
+	if _, err := c.Get("/"); !errors.Is(err, clink.ErrInvalidOption) {
+		t.Errorf("expected ErrInvalidOption, got: %v", err)
+	}
+}
+
+func TestWithDiscovery_CancelledFirstRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	var calls int32
+	discoverer := clink.DiscovererFunc(func(ctx context.Context) ([]string, error) {
+		atomic.AddInt32(&calls, 1)
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		return []string{server.URL}, nil
+	})
+
+	c := clink.NewClient(clink.WithDiscovery(discoverer, time.Hour))
+	defer func() { _ = c.Shutdown(context.Background()) }()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "/", nil)
+	if _, err := c.Do(req); err == nil {
+		t.Fatal("expected the cancelled request to fail")
+	}
+
+	resp, err := c.Get("/")
+	if err != nil {
+		t.Fatalf("expected the endpoints to be discovered again, got: %v", err)
+	}
+	_ = resp.Body.Close()
+
+	if n := atomic.LoadInt32(&calls); n != 2 {
+		t.Errorf("expected 2 discoveries, got: %d", n)
+	}
+}
+
+func TestSRVDiscoverer(t *testing.T) {
+	dnsAddr, _ := newDNSServer(t)
+	resolver := &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "udp", dnsAddr)
+		},
+	}
+
+	testCases := []struct {
+		name       string
+		scheme     string
+		resultFunc func(*testing.T, []string, error)
+	}{
+		{
+			name: "default scheme",
+			resultFunc: func(t *testing.T, urls []string, err error) {
+				if err != nil || !slices.Equal(urls, []string{"http://localhost:8080"}) {
+					t.Errorf("unexpected endpoints: %v (%v)", urls, err)
+				}
+			},
+		},
+		{
+			name:   "custom scheme",
+			scheme: "https",
+			resultFunc: func(t *testing.T, urls []string, err error) {
+				if err != nil || !slices.Equal(urls, []string{"https://localhost:8080"}) {
+					t.Errorf("unexpected endpoints: %v (%v)", urls, err)
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			d := clink.SRVDiscoverer{Service: "api", Proto: "tcp", Name: "clink.test", Scheme: tc.scheme, Resolver: resolver}
+			urls, err := d.Discover(context.Background())
+			tc.resultFunc(t, urls, err)
+		})
+	}
+}
@@ -0,0 +1,209 @@
+package clink
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"time"
+)
+
+// WithResolver sets the resolver used by the client's own transport to look up host names,
+// for example to pin lookups to specific DNS servers.
+// The option can't be used with WithClient or WithTransport.
+func WithResolver(resolver *net.Resolver) Option {
+	return func(c *Client) {
+		if c.ownTransport("WithResolver") == nil {
+			return
+		}
+
+		c.dialer.Resolver = resolver
+	}
+}
+
+// WithDNSCache caches the addresses of looked up host names for the given duration, so that
+// clients sending many requests don't look up the same names over and over. The TTLs of DNS
+// records aren't available to the resolver, so the duration should not exceed them. At most
+// 1024 host names are cached. The option can't be used with WithClient or WithTransport.
+func WithDNSCache(ttl time.Duration) Option {
+	return func(c *Client) {
+		if ttl <= 0 {
+			c.addConfigError("WithDNSCache: ttl must be positive, got %s", ttl)
+			return
+		}
+
+		if c.ownTransport("WithDNSCache") == nil {
+			return
+		}
+
+		c.dnsCache = &dnsCache{ttl: ttl, entries: make(map[string]dnsEntry)}
+	}
+}
+
+// IPFamily selects the IP addresses the client's own transport connects to.
+type IPFamily int
+
+const (
+	// AnyIP connects to the addresses in the order they were resolved.
+	AnyIP IPFamily = iota
+	// PreferIPv4 connects to the IPv4 addresses first.
+	PreferIPv4
+	// PreferIPv6 connects to the IPv6 addresses first.
+	PreferIPv6
+	// IPv4Only only connects to the IPv4 addresses.
+	IPv4Only
+	// IPv6Only only connects to the IPv6 addresses.
+	IPv6Only
+)
+
+// WithIPFamily sets the IP family of the addresses the client connects to. The resolved addresses
+// of a host are tried one after the other, each with the full dial timeout, until a connection
+// succeeds. The address that served a request is available from Timings.RemoteAddr.
+// The option can't be used with WithClient or WithTransport.
+func WithIPFamily(family IPFamily) Option {
+	return func(c *Client) {
+		if c.ownTransport("WithIPFamily") == nil {
+			return
+		}
+
+		c.ipFamily = family
+	}
+}
+
+// order returns the IP addresses of the family, in the order they should be tried.
+func (f IPFamily) order(ips []string) []string {
+	if f == AnyIP {
+		return ips
+	}
+
+	var v4, v6 []string
+	for _, ip := range ips {
+		if parsed := net.ParseIP(ip); parsed != nil && parsed.To4() != nil {
+			v4 = append(v4, ip)
+		} else {
+			v6 = append(v6, ip)
+		}
+	}
+
+	switch f {
+	case PreferIPv4:
+		return append(v4, v6...)
+	case PreferIPv6:
+		return append(v6, v4...)
+	case IPv4Only:
+		return v4
+	default:
+		return v6
+	}
+}
+
+// dialContext dials connections for the client's own transport, honoring WithUnixSocket,
+// WithDNSCache and WithIPFamily.
+func (c *Client) dialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	if c.unixSocket != "" {
+		return c.dialer.DialContext(ctx, "unix", c.unixSocket)
+	}
+
+	if c.dnsCache == nil && c.ipFamily == AnyIP {
+		return c.dialer.DialContext(ctx, network, addr)
+	}
+
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil || net.ParseIP(host) != nil {
+		return c.dialer.DialContext(ctx, network, addr)
+	}
+
+	var ips []string
+	if c.dnsCache != nil {
+		ips, err = c.dnsCache.lookup(ctx, c.clock, c.dialer.Resolver, host)
+	} else {
+		ips, err = lookupHost(ctx, c.dialer.Resolver, host)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	ips = c.ipFamily.order(ips)
+	if len(ips) == 0 {
+		return nil, &net.DNSError{Err: "no addresses of the IP family", Name: host, IsNotFound: true}
+	}
+
+	var errs []error
+	for _, ip := range ips {
+		conn, err := c.dialer.DialContext(ctx, network, net.JoinHostPort(ip, port))
+		if err == nil {
+			return conn, nil
+		}
+		errs = append(errs, err)
+	}
+
+	return nil, errors.Join(errs...)
+}
+
+// dnsCache caches host name lookups.
+type dnsCache struct {
+	ttl time.Duration
+
+	mu      sync.Mutex
+	entries map[string]dnsEntry
+}
+
+// dnsCacheMaxEntries is the number of host names a dnsCache holds at most.
+const dnsCacheMaxEntries = 1024
+
+type dnsEntry struct {
+	ips     []string
+	expires time.Time
+}
+
+func (d *dnsCache) lookup(ctx context.Context, clock Clock, resolver *net.Resolver, host string) ([]string, error) {
+	d.mu.Lock()
+	entry, ok := d.entries[host]
+	d.mu.Unlock()
+
+	if ok && clock.Now().Before(entry.expires) {
+		return entry.ips, nil
+	}
+
+	ips, err := lookupHost(ctx, resolver, host)
+	if err != nil {
+		return nil, err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := clock.Now()
+	if _, ok := d.entries[host]; !ok && len(d.entries) >= dnsCacheMaxEntries {
+		d.evictLocked(now)
+	}
+	d.entries[host] = dnsEntry{ips: ips, expires: now.Add(d.ttl)}
+
+	return ips, nil
+}
+
+// evictLocked removes the expired entries, or an arbitrary entry if none has expired.
+func (d *dnsCache) evictLocked(now time.Time) {
+	for host, entry := range d.entries {
+		if !now.Before(entry.expires) {
+			delete(d.entries, host)
+		}
+	}
+
+	if len(d.entries) < dnsCacheMaxEntries {
+		return
+	}
+
+	for host := range d.entries {
+		delete(d.entries, host)
+		return
+	}
+}
+
+func lookupHost(ctx context.Context, resolver *net.Resolver, host string) ([]string, error) {
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+
+	return resolver.LookupHost(ctx, host)
+}
@@ -0,0 +1,216 @@
+package clink_test
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/davesavic/clink"
+)
+
+// newDNSServer starts a DNS server answering every A query with 127.0.0.1 and counting queries.
+func newDNSServer(t *testing.T) (string, *atomic.Int64) {
+	t.Helper()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+
+	var queries atomic.Int64
+	go func() {
+		buf := make([]byte, 512)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			queries.Add(1)
+
+			query := buf[:n]
+			end := 12
+			for end < len(query) && query[end] != 0 {
+				end += int(query[end]) + 1
+			}
+			end += 5
+			qtype := binary.BigEndian.Uint16(query[end-4 : end-2])
+
+			resp := append([]byte{}, query[:2]...)
+			resp = append(resp, 0x81, 0x80, 0, 1, 0, 0, 0, 0, 0, 0)
+			resp = append(resp, query[12:end]...)
+			if qtype == 1 {
+				resp[7] = 1
+				resp = append(resp, 0xc0, 0x0c, 0, 1, 0, 1, 0, 0, 0, 60, 0, 4, 127, 0, 0, 1)
+			} else if qtype == 28 {
+				resp[7] = 1
+				resp = append(resp, 0xc0, 0x0c, 0, 28, 0, 1, 0, 0, 0, 60, 0, 16)
+				resp = append(resp, net.IPv6loopback...)
+			} else if qtype == 33 {
+				// SRV record with priority 10, weight 5, port 8080 and target localhost.
+				resp[7] = 1
+				resp = append(resp, 0xc0, 0x0c, 0, 33, 0, 1, 0, 0, 0, 60, 0, 17, 0, 10, 0, 5, 0x1f, 0x90)
+				resp = append(resp, append([]byte{9}, "localhost"...)...)
+				resp = append(resp, 0)
+			}
+			_, _ = conn.WriteTo(resp, addr)
+		}
+	}()
+
+	return conn.LocalAddr().String(), &queries
+}
+
+func TestWithDNSCache(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {}))
+	defer server.Close()
+
+	dnsAddr, queries := newDNSServer(t)
+	resolver := &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "udp", dnsAddr)
+		},
+	}
+
+	_, port, _ := net.SplitHostPort(strings.TrimPrefix(server.URL, "http://"))
+	url := "http://api.clink.test:" + port
+
+	testCases := []struct {
+		name       string
+		opts       []clink.Option
+		resultFunc func(first, second int64) bool
+	}{
+		{
+			name: "resolves with custom resolver on every new connection",
+			opts: []clink.Option{clink.WithResolver(resolver)},
+			resultFunc: func(first, second int64) bool {
+				return first > 0 && second == first
+			},
+		},
+		{
+			name: "caches lookups",
+			opts: []clink.Option{clink.WithResolver(resolver), clink.WithDNSCache(time.Minute)},
+			resultFunc: func(first, second int64) bool {
+				return first > 0 && second == 0
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := clink.NewClient(tc.opts...)
+
+			counts := make([]int64, 2)
+			for i := range counts {
+				queries.Store(0)
+
+				resp, err := c.Get(url)
+				if err != nil {
+					t.Fatalf("failed to make request: %v", err)
+				}
+				_ = resp.Body.Close()
+				c.HttpClient.CloseIdleConnections()
+
+				counts[i] = queries.Load()
+			}
+
+			if !tc.resultFunc(counts[0], counts[1]) {
+				t.Errorf("unexpected number of dns queries: %v", counts)
+			}
+		})
+	}
+}
+
+func TestWithDNSCache_CustomTransport(t *testing.T) {
+	c := clink.NewClient(clink.WithClient(&http.Client{}), clink.WithDNSCache(time.Minute))
+
+	if _, err := c.Get("http://example.invalid"); !errors.Is(err, clink.ErrInvalidOption) {
+		t.Errorf("expected invalid option error, got: %v", err)
+	}
+}
+
+func TestWithDNSCache_InvalidTTL(t *testing.T) {
+	c := clink.NewClient(clink.WithDNSCache(0))
+
+	if _, err := c.Get("http://example.invalid"); !errors.Is(err, clink.ErrInvalidOption) {
+		t.Errorf("expected invalid option error, got: %v", err)
+	}
+}
+
+func TestWithIPFamily(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {}))
+	defer server.Close()
+
+	dnsAddr, _ := newDNSServer(t)
+	resolver := &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "udp", dnsAddr)
+		},
+	}
+
+	// The host resolves to 127.0.0.1 and ::1, but the server only listens on 127.0.0.1.
+	_, port, _ := net.SplitHostPort(strings.TrimPrefix(server.URL, "http://"))
+	url := "http://api.clink.test:" + port
+
+	testCases := []struct {
+		name       string
+		family     clink.IPFamily
+		resultFunc func(*testing.T, string, error)
+	}{
+		{
+			name:   "prefer ipv4",
+			family: clink.PreferIPv4,
+			resultFunc: func(t *testing.T, addr string, err error) {
+				if err != nil || addr != "127.0.0.1:"+port {
+					t.Errorf("expected the request to be sent to 127.0.0.1, got: %s (%v)", addr, err)
+				}
+			},
+		},
+		{
+			name:   "fails over to the next address",
+			family: clink.PreferIPv6,
+			resultFunc: func(t *testing.T, addr string, err error) {
+				if err != nil || addr != "127.0.0.1:"+port {
+					t.Errorf("expected the request to fail over to 127.0.0.1, got: %s (%v)", addr, err)
+				}
+			},
+		},
+		{
+			name:   "ipv6 only",
+			family: clink.IPv6Only,
+			resultFunc: func(t *testing.T, addr string, err error) {
+				if err == nil {
+					t.Errorf("expected the request to fail, got response from %s", addr)
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var addr string
+			c := clink.NewClient(
+				clink.WithResolver(resolver),
+				clink.WithIPFamily(tc.family),
+				clink.WithTracing(func(_ *http.Request, timings clink.Timings) {
+					addr = timings.RemoteAddr
+				}),
+			)
+
+			resp, err := c.Get(url)
+			if err == nil {
+				_ = resp.Body.Close()
+			}
+
+			tc.resultFunc(t, addr, err)
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package clink
+
+import (
+	"io"
+	"net/http"
+)
+
+// Doer sends HTTP requests like Client. Code sending requests can accept a Doer instead of a
+// *Client, so that a fake can be used in its tests.
+type Doer interface {
+	Do(req *http.Request) (*http.Response, error)
+	Head(url string) (*http.Response, error)
+	Options(url string) (*http.Response, error)
+	Get(url string) (*http.Response, error)
+	Post(url string, body io.Reader) (*http.Response, error)
+	Put(url string, body io.Reader) (*http.Response, error)
+	Patch(url string, body io.Reader) (*http.Response, error)
+	Delete(url string) (*http.Response, error)
+}
+
+var _ Doer = (*Client)(nil)
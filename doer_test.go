@@ -0,0 +1,56 @@
+package clink_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/davesavic/clink"
+)
+
+// fakeDoer is a Doer responding to every request with the same body.
+type fakeDoer struct {
+	clink.Doer
+	body string
+}
+
+func (f fakeDoer) Get(string) (*http.Response, error) {
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(f.body))}, nil
+}
+
+func TestDoer(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("server"))
+	}))
+	defer server.Close()
+
+	fetch := func(d clink.Doer) string {
+		resp, err := d.Get(server.URL)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		defer resp.Body.Close()
+
+		body, _ := io.ReadAll(resp.Body)
+		return string(body)
+	}
+
+	testCases := []struct {
+		name     string
+		doer     clink.Doer
+		expected string
+	}{
+		{name: "client", doer: clink.NewClient(), expected: "server"},
+		{name: "fake", doer: fakeDoer{body: "fake"}, expected: "fake"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if body := fetch(tc.doer); body != tc.expected {
+				t.Errorf("expected %q, got: %q", tc.expected, body)
+			}
+		})
+	}
+}
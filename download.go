@@ -0,0 +1,355 @@
+package clink
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Default settings of Download, unless set in DownloadOptions.
+const (
+	DefaultDownloadChunkSize    = 8 << 20
+	DefaultDownloadChunkRetries = 3
+)
+
+// DownloadOptions configures Client.Download.
+type DownloadOptions struct {
+	// Concurrency is the number of chunks downloaded concurrently with Range requests. The file is
+	// downloaded with a single request if Concurrency is 1 or less, if the server doesn't support
+	// range requests, or if the file is smaller than ChunkSize.
+	Concurrency int
+	// ChunkSize is the size of the chunks. DefaultDownloadChunkSize is used if it is not positive.
+	ChunkSize int64
+	// ChunkRetries is the number of times a failed chunk is downloaded again, in addition to the
+	// retries of the client. DefaultDownloadChunkRetries is used if it is zero, and failed chunks
+	// are not retried if it is negative.
+	ChunkRetries int
+	// RequestOptions are applied to the requests of the download. The checksums expected with
+	// ExpectChecksum and VerifyDigestHeader are verified against the whole file when it is
+	// downloaded in chunks, using the digest header of the HEAD response for VerifyDigestHeader.
+	RequestOptions []RequestOption
+	// Checksum is the hex encoded digest of the file, computed with ChecksumAlgorithm (SHA256 or
+	// SHA512). If set, the downloaded file is verified before it is moved to its path, and the
+	// download fails with a *ChecksumMismatchError if the digest doesn't match.
+	Checksum          string
+	ChecksumAlgorithm string
+	// Overwrite allows DownloadToDir to replace an existing file. Without it, DownloadToDir fails
+	// with an error wrapping fs.ErrExist if the file it would write already exists. Download always
+	// replaces the file at its path.
+	Overwrite bool
+}
+
+// Download downloads the URL to the file at path and returns the number of bytes written.
+// Large files can be downloaded faster in concurrent chunks (see DownloadOptions). The download is
+// written to a temporary file renamed to path once complete, so a failed or interrupted download
+// never leaves a partial file at path.
+func (c *Client) Download(ctx context.Context, url, path string, opts DownloadOptions) (int64, error) {
+	n, _, err := c.downloadFile(ctx, url, filepath.Dir(path), opts, true, func(http.Header) string {
+		return path
+	})
+
+	return n, err
+}
+
+// DownloadToDir downloads the URL like Download to a file of the directory, named after the
+// filename of the Content-Disposition header of the response, or else after the last segment of
+// the URL path. It returns the path of the file and the number of bytes written. An existing file
+// is only replaced if DownloadOptions.Overwrite is set.
+func (c *Client) DownloadToDir(ctx context.Context, url, dir string, opts DownloadOptions) (string, int64, error) {
+	n, path, err := c.downloadFile(ctx, url, dir, opts, opts.Overwrite, func(header http.Header) string {
+		return filepath.Join(dir, downloadFilename(header, url))
+	})
+
+	return path, n, err
+}
+
+// downloadFile downloads the URL to a temporary file of dir, and renames it to the path returned
+// by target for the header of the response, replacing an existing file only if overwrite is set.
+func (c *Client) downloadFile(ctx context.Context, url, dir string, opts DownloadOptions, overwrite bool, target func(http.Header) string) (int64, string, error) {
+	if opts.ChunkSize <= 0 {
+		opts.ChunkSize = DefaultDownloadChunkSize
+	}
+
+	if opts.ChunkRetries == 0 {
+		opts.ChunkRetries = DefaultDownloadChunkRetries
+	}
+	opts.ChunkRetries = max(opts.ChunkRetries, 0)
+
+	file, err := os.CreateTemp(dir, ".download-*")
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to create file: %w", err)
+	}
+
+	n, header, err := c.download(ctx, url, file, opts)
+	if err == nil && opts.Checksum != "" {
+		err = verifyFileChecksum(file, opts.ChecksumAlgorithm, opts.Checksum)
+	}
+	if err == nil {
+		err = file.Chmod(0o644)
+	}
+	if closeErr := file.Close(); err == nil && closeErr != nil {
+		err = fmt.Errorf("failed to close file: %w", closeErr)
+	}
+
+	if err != nil {
+		_ = os.Remove(file.Name())
+		return n, "", err
+	}
+
+	path := target(header)
+
+	rename := os.Rename
+	if !overwrite {
+		rename = renameNoReplace
+	}
+
+	if err := rename(file.Name(), path); err != nil {
+		_ = os.Remove(file.Name())
+		return n, "", fmt.Errorf("failed to rename file: %w", err)
+	}
+
+	return n, path, nil
+}
+
+// renameNoReplace renames the file like os.Rename, but fails with an error wrapping fs.ErrExist
+// if the new path already exists.
+func renameNoReplace(oldpath, newpath string) error {
+	if err := os.Link(oldpath, newpath); err != nil {
+		return err
+	}
+
+	return os.Remove(oldpath)
+}
+
+// download writes the URL to the file and returns the number of bytes written and the response header.
+func (c *Client) download(ctx context.Context, url string, file *os.File, opts DownloadOptions) (int64, http.Header, error) {
+	if opts.Concurrency > 1 {
+		probe, err := c.probeRanges(ctx, url, opts.RequestOptions)
+		if err != nil {
+			return 0, nil, err
+		}
+
+		if supportsRanges(probe) && probe.ContentLength > opts.ChunkSize {
+			n, err := c.downloadChunks(ctx, url, file, probe.ContentLength, strongETag(probe.Header), opts)
+			if err == nil {
+				err = verifyDownloadChecksums(file, probe.Header, opts.RequestOptions)
+			}
+			return n, probe.Header, err
+		}
+	}
+
+	return c.getInto(ctx, url, file, opts.RequestOptions)
+}
+
+// strongETag returns the ETag of the header if it is a strong validator, which can be used with
+// If-Range, or an empty string.
+func strongETag(header http.Header) string {
+	etag := header.Get("ETag")
+	if strings.HasPrefix(etag, "W/") {
+		return ""
+	}
+
+	return etag
+}
+
+// verifyDownloadChecksums verifies the file downloaded in chunks against the checksums expected by
+// the request options, using the digest header of the HEAD response for VerifyDigestHeader.
+func verifyDownloadChecksums(file *os.File, header http.Header, opts []RequestOption) error {
+	var o requestOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if o.checksum != nil {
+		if err := verifyFileChecksum(file, o.checksum.algorithm, o.checksum.digest); err != nil {
+			return err
+		}
+	}
+
+	if o.digestHeader != nil {
+		algorithm, expected, ok := parseDigestHeader(header)
+		if !ok && *o.digestHeader {
+			return ErrMissingDigest
+		}
+
+		if ok {
+			return verifyFileDigest(file, algorithm, expected)
+		}
+	}
+
+	return nil
+}
+
+// withoutChecksums removes the checksums expected for a request of a download verified as a whole.
+func withoutChecksums() RequestOption {
+	return func(o *requestOptions) {
+		o.checksum = nil
+		o.digestHeader = nil
+	}
+}
+
+// downloadFilename returns a safe filename for the download from the Content-Disposition header,
+// or from the URL path.
+func downloadFilename(header http.Header, rawURL string) string {
+	if _, params, err := mime.ParseMediaType(header.Get("Content-Disposition")); err == nil {
+		if name := safeFilename(params["filename"]); name != "" {
+			return name
+		}
+	}
+
+	if u, err := url.Parse(rawURL); err == nil {
+		if name := safeFilename(u.Path); name != "" {
+			return name
+		}
+	}
+
+	return "download"
+}
+
+// safeFilename returns the last element of the name, or an empty string if it isn't a usable filename.
+func safeFilename(name string) string {
+	name = path.Base(strings.ReplaceAll(name, "\\", "/"))
+	if name == "/" || strings.HasPrefix(name, ".") {
+		return ""
+	}
+
+	return name
+}
+
+// probeRanges sends a HEAD request to find the size of the resource and whether the server
+// supports range requests for it.
+func (c *Client) probeRanges(ctx context.Context, url string, opts []RequestOption) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.Do(ConfigureRequest(req, append(opts[:len(opts):len(opts)], withoutChecksums())...))
+	if err != nil {
+		return nil, err
+	}
+	_ = DrainAndClose(resp)
+
+	return resp, nil
+}
+
+func supportsRanges(resp *http.Response) bool {
+	return isSuccessStatus(resp) && resp.ContentLength > 0 &&
+		strings.EqualFold(resp.Header.Get("Accept-Ranges"), "bytes")
+}
+
+// downloadChunks downloads the resource in chunks written at their offset in the file.
+func (c *Client) downloadChunks(ctx context.Context, url string, file *os.File, size int64, etag string, opts DownloadOptions) (int64, error) {
+	if err := file.Truncate(size); err != nil {
+		return 0, fmt.Errorf("failed to allocate file: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	offsets := make(chan int64)
+	go func() {
+		defer close(offsets)
+		for offset := int64(0); offset < size; offset += opts.ChunkSize {
+			select {
+			case offsets <- offset:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	var errOnce sync.Once
+	var firstErr error
+	for i := 0; i < opts.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for offset := range offsets {
+				end := min(offset+opts.ChunkSize, size) - 1
+				if err := c.downloadChunk(ctx, url, file, offset, end, etag, opts); err != nil {
+					errOnce.Do(func() {
+						firstErr = err
+						cancel()
+					})
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return 0, firstErr
+	}
+
+	return size, nil
+}
+
+// downloadChunk downloads the bytes from start to end (inclusive) of the resource, retrying the
+// whole chunk if it fails.
+func (c *Client) downloadChunk(ctx context.Context, url string, file *os.File, start, end int64, etag string, opts DownloadOptions) error {
+	var err error
+	for attempt := 0; attempt <= opts.ChunkRetries; attempt++ {
+		if attempt > 0 {
+			if err := c.sleep(ctx, time.Duration(attempt-1)*time.Second); err != nil {
+				return err
+			}
+		}
+
+		err = c.fetchChunk(ctx, url, file, start, end, etag, opts.RequestOptions)
+		if err == nil || ctx.Err() != nil {
+			return err
+		}
+	}
+
+	return fmt.Errorf("failed to download bytes %d-%d: %w", start, end, err)
+}
+
+func (c *Client) fetchChunk(ctx context.Context, url string, file *os.File, start, end int64, etag string, opts []RequestOption) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", start, end))
+	if etag != "" {
+		req.Header.Set("If-Range", etag)
+	}
+
+	resp, err := c.Do(ConfigureRequest(req, append(opts[:len(opts):len(opts)], withoutChecksums(), Unbuffered())...))
+	if err != nil {
+		return err
+	}
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(resp.Body)
+
+	if resp.StatusCode != http.StatusPartialContent {
+		if !isSuccessStatus(resp) {
+			return newHTTPError(resp, c.config().Redactor)
+		}
+		return fmt.Errorf("server ignored range request: unexpected response status: %s", resp.Status)
+	}
+
+	want := end - start + 1
+	n, err := io.Copy(io.NewOffsetWriter(file, start), io.LimitReader(resp.Body, want))
+	if err != nil {
+		return fmt.Errorf("failed to copy response body: %w", err)
+	}
+
+	if n != want {
+		return fmt.Errorf("failed to copy response body: %w", io.ErrUnexpectedEOF)
+	}
+
+	return nil
+}
@@ -0,0 +1,319 @@
+package clink_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/davesavic/clink"
+)
+
+func TestClient_Download(t *testing.T) {
+	content := bytes.Repeat([]byte("0123456789abcdef"), 4096)
+	sum := sha256.Sum256(content)
+	contentSum := hex.EncodeToString(sum[:])
+
+	testCases := []struct {
+		name       string
+		handler    func(http.ResponseWriter, *http.Request, int) bool
+		opts       clink.DownloadOptions
+		resultFunc func(*testing.T, string, int64, error, []string)
+	}{
+		{
+			name: "concurrent chunks",
+			opts: clink.DownloadOptions{Concurrency: 4, ChunkSize: 10_000},
+			resultFunc: func(t *testing.T, path string, n int64, err error, ranges []string) {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+
+				data, _ := os.ReadFile(path)
+				if n != int64(len(content)) || !bytes.Equal(data, content) {
+					t.Errorf("expected the file to be reassembled, got %d bytes", len(data))
+				}
+
+				if len(ranges) != 7 {
+					t.Errorf("expected 7 range requests, got: %v", ranges)
+				}
+			},
+		},
+		{
+			name: "failed chunk is retried",
+			opts: clink.DownloadOptions{Concurrency: 2, ChunkSize: 30_000},
+			handler: func(w http.ResponseWriter, r *http.Request, call int) bool {
+				if r.Header.Get("Range") == "bytes=30000-59999" && call == 1 {
+					w.WriteHeader(http.StatusInternalServerError)
+					return true
+				}
+				return false
+			},
+			resultFunc: func(t *testing.T, path string, _ int64, err error, ranges []string) {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+
+				data, _ := os.ReadFile(path)
+				if !bytes.Equal(data, content) {
+					t.Error("expected the file to be reassembled")
+				}
+
+				if len(ranges) != 4 {
+					t.Errorf("expected 4 range requests, got: %v", ranges)
+				}
+			},
+		},
+		{
+			name: "failed chunk out of retries",
+			opts: clink.DownloadOptions{Concurrency: 2, ChunkSize: 30_000, ChunkRetries: -1},
+			handler: func(w http.ResponseWriter, r *http.Request, _ int) bool {
+				if r.Header.Get("Range") == "bytes=30000-59999" {
+					w.WriteHeader(http.StatusInternalServerError)
+					return true
+				}
+				return false
+			},
+			resultFunc: func(t *testing.T, path string, _ int64, err error, _ []string) {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+
+				if _, statErr := os.Stat(path); !os.IsNotExist(statErr) {
+					t.Errorf("expected the partial file to be removed, got: %v", statErr)
+				}
+			},
+		},
+		{
+			name: "server without range support",
+			opts: clink.DownloadOptions{Concurrency: 4, ChunkSize: 10_000},
+			handler: func(w http.ResponseWriter, _ *http.Request, _ int) bool {
+				_, _ = w.Write(content)
+				return true
+			},
+			resultFunc: func(t *testing.T, path string, _ int64, err error, ranges []string) {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+
+				data, _ := os.ReadFile(path)
+				if !bytes.Equal(data, content) {
+					t.Error("expected the file to be downloaded")
+				}
+
+				if len(ranges) != 0 {
+					t.Errorf("expected no range requests, got: %v", ranges)
+				}
+			},
+		},
+		{
+			name: "weak etag is not sent with if-range",
+			opts: clink.DownloadOptions{Concurrency: 4, ChunkSize: 10_000},
+			handler: func(w http.ResponseWriter, _ *http.Request, _ int) bool {
+				w.Header().Set("ETag", `W/"v1"`)
+				return false
+			},
+			resultFunc: func(t *testing.T, path string, _ int64, err error, ranges []string) {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+
+				if data, _ := os.ReadFile(path); !bytes.Equal(data, content) || len(ranges) != 7 {
+					t.Errorf("expected the file to be downloaded in chunks, got %d bytes and ranges %v", len(data), ranges)
+				}
+			},
+		},
+		{
+			name: "chunked download matches checksum",
+			opts: clink.DownloadOptions{Concurrency: 4, ChunkSize: 10_000, Checksum: contentSum, ChecksumAlgorithm: clink.SHA256},
+			resultFunc: func(t *testing.T, path string, _ int64, err error, _ []string) {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			},
+		},
+		{
+			name: "chunked download with checksum mismatch",
+			opts: clink.DownloadOptions{Concurrency: 4, ChunkSize: 10_000, Checksum: strings.Repeat("0", 64), ChecksumAlgorithm: clink.SHA256},
+			resultFunc: func(t *testing.T, path string, _ int64, err error, _ []string) {
+				var mismatch *clink.ChecksumMismatchError
+				if !errors.As(err, &mismatch) {
+					t.Fatalf("expected a checksum mismatch, got: %v", err)
+				}
+
+				if _, statErr := os.Stat(path); !os.IsNotExist(statErr) {
+					t.Errorf("expected the file to be removed, got: %v", statErr)
+				}
+			},
+		},
+		{
+			name: "expected checksum request option with chunks",
+			opts: clink.DownloadOptions{Concurrency: 4, ChunkSize: 10_000, RequestOptions: []clink.RequestOption{clink.ExpectChecksum(clink.SHA256, contentSum)}},
+			resultFunc: func(t *testing.T, path string, _ int64, err error, ranges []string) {
+				if err != nil || len(ranges) != 7 {
+					t.Fatalf("expected the chunks to be verified as a whole, got: %v, %v", err, ranges)
+				}
+			},
+		},
+		{
+			name: "digest header with chunks",
+			opts: clink.DownloadOptions{Concurrency: 4, ChunkSize: 10_000, RequestOptions: []clink.RequestOption{clink.VerifyDigestHeader(true)}},
+			handler: func(w http.ResponseWriter, _ *http.Request, _ int) bool {
+				w.Header().Set("Content-Digest", "sha-256=:"+base64.StdEncoding.EncodeToString(make([]byte, 32))+":")
+				return false
+			},
+			resultFunc: func(t *testing.T, path string, _ int64, err error, _ []string) {
+				var mismatch *clink.ChecksumMismatchError
+				if !errors.As(err, &mismatch) {
+					t.Fatalf("expected a checksum mismatch, got: %v", err)
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var ranges []string
+			calls := map[string]int{}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				rng := r.Header.Get("Range")
+				calls[rng]++
+				call := calls[rng]
+				if rng != "" {
+					ranges = append(ranges, rng)
+				}
+				mu.Unlock()
+
+				if tc.handler != nil && tc.handler(w, r, call) {
+					return
+				}
+				http.ServeContent(w, r, "file.bin", time.Time{}, bytes.NewReader(content))
+			}))
+			defer server.Close()
+
+			path := filepath.Join(t.TempDir(), "file.bin")
+			n, err := clink.NewClient().Download(context.Background(), server.URL, path, tc.opts)
+
+			mu.Lock()
+			defer mu.Unlock()
+			tc.resultFunc(t, path, n, err, ranges)
+		})
+	}
+}
+
+func TestClient_Download_KeepsExistingFile(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Length", "100")
+		_, _ = w.Write([]byte("truncated"))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "file.bin")
+	if err := os.WriteFile(path, []byte("previous"), 0o600); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	if _, err := clink.NewClient().Download(context.Background(), server.URL, path, clink.DownloadOptions{}); err == nil {
+		t.Fatal("expected an error for the interrupted download")
+	}
+
+	data, _ := os.ReadFile(path)
+	if string(data) != "previous" {
+		t.Errorf("expected the existing file to be kept, got: %q", data)
+	}
+
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Errorf("expected the temporary file to be removed, got %d entries", len(entries))
+	}
+}
+
+func TestClient_DownloadToDir(t *testing.T) {
+	testCases := []struct {
+		name        string
+		disposition string
+		urlPath     string
+		expected    string
+	}{
+		{name: "content disposition", disposition: `attachment; filename="report.pdf"`, urlPath: "/files/1", expected: "report.pdf"},
+		{name: "encoded filename", disposition: `attachment; filename*=UTF-8''r%C3%A9sum%C3%A9.txt`, urlPath: "/files/1", expected: "résumé.txt"},
+		{name: "path traversal", disposition: `attachment; filename="../../etc/passwd"`, urlPath: "/files/1", expected: "passwd"},
+		{name: "url path", urlPath: "/files/archive.zip", expected: "archive.zip"},
+		{name: "hidden file", disposition: `attachment; filename=".bashrc"`, urlPath: "/", expected: "download"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				if tc.disposition != "" {
+					w.Header().Set("Content-Disposition", tc.disposition)
+				}
+				_, _ = w.Write([]byte("content"))
+			}))
+			defer server.Close()
+
+			dir := t.TempDir()
+			path, n, err := clink.NewClient().DownloadToDir(context.Background(), server.URL+tc.urlPath, dir, clink.DownloadOptions{})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if path != filepath.Join(dir, tc.expected) || n != int64(len("content")) {
+				t.Errorf("expected %s, got: %s (%d bytes)", tc.expected, path, n)
+			}
+
+			if data, _ := os.ReadFile(path); string(data) != "content" {
+				t.Errorf("unexpected file content: %q", data)
+			}
+		})
+	}
+}
+
+func TestClient_DownloadToDir_Overwrite(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Disposition", `attachment; filename="report.pdf"`)
+		_, _ = w.Write([]byte("content"))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "report.pdf")
+	if err := os.WriteFile(path, []byte("previous"), 0o600); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	c := clink.NewClient()
+
+	if _, _, err := c.DownloadToDir(context.Background(), server.URL, dir, clink.DownloadOptions{}); !errors.Is(err, fs.ErrExist) {
+		t.Fatalf("expected the existing file not to be replaced, got: %v", err)
+	}
+
+	if data, _ := os.ReadFile(path); string(data) != "previous" {
+		t.Errorf("expected the existing file to be kept, got: %q", data)
+	}
+
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("expected the temporary file to be removed, got %d entries", len(entries))
+	}
+
+	if _, _, err := c.DownloadToDir(context.Background(), server.URL, dir, clink.DownloadOptions{Overwrite: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if data, _ := os.ReadFile(path); string(data) != "content" {
+		t.Errorf("expected the existing file to be replaced, got: %q", data)
+	}
+}
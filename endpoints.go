@@ -0,0 +1,246 @@
+package clink
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrNoEndpoints is returned when a request can't be sent because service discovery found no endpoint.
+var ErrNoEndpoints = errors.New("no endpoints available")
+
+// DefaultFailoverStatuses are the response statuses for which a request fails over to the next
+// endpoint, unless set with WithFailoverStatuses.
+var DefaultFailoverStatuses = []int{http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout}
+
+// endpointDownTime is how long a failed endpoint is skipped before requests are sent to it again.
+const endpointDownTime = 30 * time.Second
+
+// WithEndpoints sets the base URLs of the client: requests with a relative URL are sent to the
+// primary endpoint, or to the endpoint picked by the balancer set with WithBalancer, and fail over
+// to the next endpoint when an endpoint can't be reached or responds with one of the failover
+// statuses (see WithFailoverStatuses). A failed endpoint is skipped for 30 seconds, after which
+// requests go back to it. The primary endpoint is also set as the client's BaseURL.
+func WithEndpoints(primary string, fallbacks ...string) Option {
+	return func(c *Client) {
+		var endpoints []*trackedEndpoint
+		for _, raw := range append([]string{primary}, fallbacks...) {
+			if !validEndpoint(raw) {
+				c.addConfigError("WithEndpoints: invalid endpoint %q", raw)
+				return
+			}
+			endpoints = append(endpoints, &trackedEndpoint{url: raw})
+		}
+
+		c.BaseURL = primary
+		c.endpointSet().endpoints = endpoints
+	}
+}
+
+// WithFailoverStatuses sets the response statuses for which a request fails over to the next
+// endpoint set with WithEndpoints. It replaces DefaultFailoverStatuses.
+func WithFailoverStatuses(statuses ...int) Option {
+	return func(c *Client) {
+		c.endpointSet().statuses = statuses
+	}
+}
+
+func (c *Client) endpointSet() *endpointSet {
+	if c.endpoints == nil {
+		c.endpoints = &endpointSet{statuses: DefaultFailoverStatuses}
+	}
+
+	return c.endpoints
+}
+
+func validEndpoint(raw string) bool {
+	u, err := url.Parse(raw)
+	return err == nil && u.IsAbs() && u.Host != ""
+}
+
+type endpointSet struct {
+	statuses  []int
+	balancer  Balancer
+	discovery *discovery
+	outliers  *OutlierDetection
+
+	mu        sync.RWMutex
+	endpoints []*trackedEndpoint
+}
+
+type trackedEndpoint struct {
+	url       string
+	pending   atomic.Int64
+	unhealthy atomic.Bool
+	outlier   outlierStats
+
+	mu        sync.Mutex
+	downUntil time.Time
+}
+
+// enabled reports whether requests with a relative URL are sent to the endpoints of the set.
+func (s *endpointSet) enabled() bool {
+	return s.discovery != nil || len(s.list()) > 0
+}
+
+// list returns the current endpoints. The returned slice must not be modified.
+func (s *endpointSet) list() []*trackedEndpoint {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.endpoints
+}
+
+// update replaces the endpoints with the given URLs, keeping the state of the endpoints that are
+// still present.
+func (s *endpointSet) update(urls []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current := make(map[string]*trackedEndpoint, len(s.endpoints))
+	for _, e := range s.endpoints {
+		current[e.url] = e
+	}
+
+	endpoints := make([]*trackedEndpoint, 0, len(urls))
+	for _, u := range urls {
+		e, ok := current[u]
+		if !ok {
+			e = &trackedEndpoint{url: u}
+		}
+		endpoints = append(endpoints, e)
+	}
+
+	s.endpoints = endpoints
+}
+
+// order returns the endpoints in the order they should be tried: the available ones first, starting
+// with the one picked by the balancer if there is one, then the ones that recently failed, are
+// unhealthy or are ejected.
+func (s *endpointSet) order(req *http.Request, now time.Time) []*trackedEndpoint {
+	endpoints := s.list()
+	available := make([]*trackedEndpoint, 0, len(endpoints))
+	var down []*trackedEndpoint
+
+	for _, e := range endpoints {
+		if e.down(now) || e.unhealthy.Load() || e.outlier.ejected(now) {
+			down = append(down, e)
+		} else {
+			available = append(available, e)
+		}
+	}
+
+	if s.balancer != nil && len(available) > 1 {
+		states := make([]Endpoint, len(available))
+		for i, e := range available {
+			states[i] = Endpoint{URL: e.url, Pending: int(e.pending.Load())}
+		}
+
+		if i := s.balancer.Pick(req, states); i > 0 && i < len(available) {
+			picked := available[i]
+			copy(available[1:i+1], available[:i])
+			available[0] = picked
+		}
+	}
+
+	return append(available, down...)
+}
+
+// failed reports whether the result of a request to an endpoint should fail over to the next one.
+func (s *endpointSet) failed(req *http.Request, resp *http.Response, err error) bool {
+	if err != nil {
+		return req.Context().Err() == nil
+	}
+
+	return slices.Contains(s.statuses, resp.StatusCode)
+}
+
+func (e *trackedEndpoint) down(now time.Time) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return now.Before(e.downUntil)
+}
+
+func (e *trackedEndpoint) report(failed bool, now time.Time) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if failed {
+		e.downUntil = now.Add(endpointDownTime)
+	} else {
+		e.downUntil = time.Time{}
+	}
+}
+
+// fetchEndpoints fetches the request with the relative URL ref from each endpoint in turn, until
+// one of them doesn't fail. It returns the total number of attempts.
+func (c *Client) fetchEndpoints(req *http.Request, ref *url.URL, cacheURL string, cached *cacheEntry) (*http.Response, int, error) {
+	var body []byte
+	if requestOptionsFrom(req.Context()).bodySource == nil && req.Body != nil && req.Body != http.NoBody {
+		var err error
+		body, err = c.readRequestBody(req)
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+
+	if d := c.endpoints.discovery; d != nil {
+		d.run(req.Context(), c)
+	}
+
+	if c.health != nil {
+		c.health.run(c)
+	}
+
+	query := req.URL.RawQuery
+	endpoints := c.endpoints.order(req, c.clock.Now())
+	if len(endpoints) == 0 {
+		return nil, 0, ErrNoEndpoints
+	}
+
+	var total int
+	for i, e := range endpoints {
+		u, err := resolveURL(e.url, ref)
+		if err != nil {
+			return nil, total, fmt.Errorf("failed to resolve request url: %w", err)
+		}
+		u.RawQuery = query
+		req.URL = u
+		req.Host = u.Host
+
+		if body != nil {
+			req.Body = io.NopCloser(bytes.NewReader(body))
+		}
+
+		e.pending.Add(1)
+		start := c.clock.Now()
+		resp, attempts, err := c.fetch(req, cacheURL, cached)
+		e.pending.Add(-1)
+		total += attempts
+
+		if d := c.endpoints.outliers; d != nil && req.Context().Err() == nil {
+			e.outlier.record(d, resp, err, c.elapsed(start), c.clock.Now())
+		}
+
+		failed := c.endpoints.failed(req, resp, err)
+		if req.Context().Err() == nil {
+			e.report(failed, c.clock.Now())
+		}
+
+		if !failed || i == len(endpoints)-1 {
+			return resp, total, err
+		}
+
+		_ = DrainAndClose(resp)
+	}
+
+	return nil, total, ErrNoEndpoints
+}
@@ -0,0 +1,166 @@
+package clink_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/davesavic/clink"
+)
+
+func TestWithEndpoints(t *testing.T) {
+	newServer := func(name string, status *atomic.Int64, hits *atomic.Int64) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			hits.Add(1)
+			body, _ := io.ReadAll(r.Body)
+			if code := status.Load(); code != 0 {
+				w.WriteHeader(int(code))
+			}
+			_, _ = w.Write([]byte(name + ":" + r.URL.Path + "?" + r.URL.RawQuery + ":" + string(body)))
+		}))
+	}
+
+	testCases := []struct {
+		name       string
+		primary    int64
+		opts       []clink.Option
+		resultFunc func(*testing.T, string, int, int64, int64)
+	}{
+		{
+			name: "primary available",
+			resultFunc: func(t *testing.T, body string, status int, primaryHits, fallbackHits int64) {
+				if body != "primary:/api/users?page=2:payload" || fallbackHits != 0 {
+					t.Errorf("expected the primary endpoint to be used, got: %s (%d fallback hits)", body, fallbackHits)
+				}
+			},
+		},
+		{
+			name:    "failover status",
+			primary: http.StatusServiceUnavailable,
+			resultFunc: func(t *testing.T, body string, status int, primaryHits, fallbackHits int64) {
+				if body != "fallback:/api/users?page=2:payload" || status != http.StatusOK {
+					t.Errorf("expected the request to fail over with its body, got: %d %s", status, body)
+				}
+
+				if primaryHits != 1 {
+					t.Errorf("expected the failed primary to be skipped afterwards, got %d hits", primaryHits)
+				}
+			},
+		},
+		{
+			name:    "status not configured for failover",
+			primary: http.StatusInternalServerError,
+			resultFunc: func(t *testing.T, body string, status int, primaryHits, fallbackHits int64) {
+				if status != http.StatusInternalServerError || fallbackHits != 0 {
+					t.Errorf("expected the primary response, got: %d %s", status, body)
+				}
+			},
+		},
+		{
+			name:    "configured failover status",
+			primary: http.StatusInternalServerError,
+			opts:    []clink.Option{clink.WithFailoverStatuses(http.StatusInternalServerError)},
+			resultFunc: func(t *testing.T, body string, status int, primaryHits, fallbackHits int64) {
+				if !strings.HasPrefix(body, "fallback:") {
+					t.Errorf("expected the request to fail over, got: %d %s", status, body)
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var primaryStatus, fallbackStatus, primaryHits, fallbackHits atomic.Int64
+			primaryStatus.Store(tc.primary)
+			primary := newServer("primary", &primaryStatus, &primaryHits)
+			defer primary.Close()
+			fallback := newServer("fallback", &fallbackStatus, &fallbackHits)
+			defer fallback.Close()
+
+			c := clink.NewClient(append(tc.opts, clink.WithEndpoints(primary.URL+"/api", fallback.URL+"/api"))...)
+
+			var body string
+			var status int
+			for i := 0; i < 2; i++ {
+				req, _ := http.NewRequest(http.MethodPost, "/users?page=2", strings.NewReader("payload"))
+				resp, err := c.Do(req)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				data, _ := io.ReadAll(resp.Body)
+				_ = resp.Body.Close()
+				body, status = string(data), resp.StatusCode
+			}
+
+			tc.resultFunc(t, body, status, primaryHits.Load(), fallbackHits.Load())
+		})
+	}
+}
+
+func TestWithEndpoints_Unreachable(t *testing.T) {
+	unreachable := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	unreachable.Close()
+
+	fallback := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("fallback"))
+	}))
+	defer fallback.Close()
+
+	c := clink.NewClient(clink.WithEndpoints(unreachable.URL, fallback.URL))
+
+	resp, err := c.Get("/")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if body, _ := io.ReadAll(resp.Body); string(body) != "fallback" {
+		t.Errorf("expected the fallback endpoint to be used, got: %s", body)
+	}
+}
+
+func TestWithEndpoints_InvalidEndpoint(t *testing.T) {
+	c := clink.NewClient(clink.WithEndpoints("http://primary.example.com", "not a url"))
+
+	if _, err := c.Get("/"); !errors.Is(err, clink.ErrInvalidOption) {
+		t.Errorf("expected ErrInvalidOption, got: %v", err)
+	}
+}
+
+func TestWithEndpoints_Cache(t *testing.T) {
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer primary.Close()
+
+	var fallbackHits atomic.Int64
+	fallback := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fallbackHits.Add(1)
+		w.Header().Set("Cache-Control", "max-age=60")
+		_, _ = w.Write([]byte("fallback"))
+	}))
+	defer fallback.Close()
+
+	c := clink.NewClient(clink.WithEndpoints(primary.URL, fallback.URL), clink.WithCache(clink.NewMemoryCache()))
+
+	for i := 0; i < 2; i++ {
+		resp, err := c.Get("/users")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+
+		if string(body) != "fallback" {
+			t.Errorf("expected the fallback response, got: %s", body)
+		}
+	}
+
+	if n := fallbackHits.Load(); n != 1 {
+		t.Errorf("expected the failed over response to be served from the cache, got %d fallback hits", n)
+	}
+}
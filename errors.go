@@ -0,0 +1,109 @@
+package clink
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// MaxErrorBodyBytes is the maximum number of response body bytes kept in an HTTPError.
+const MaxErrorBodyBytes = 4096
+
+// HTTPError is returned by the client when a response has a failure status
+// and error-on-status is enabled (see WithErrorOnStatus), and when the retries of a request
+// are exhausted on a response with a status of 400 or above.
+type HTTPError struct {
+	Method     string
+	URL        string
+	StatusCode int
+	Status     string
+	// Header holds the response header with sensitive values redacted.
+	Header http.Header
+	// Body holds at most MaxErrorBodyBytes of the response body, with sensitive JSON fields redacted.
+	Body []byte
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("%s %s: unexpected response status: %s", e.Method, e.URL, e.Status)
+}
+
+// WithErrorOnStatus makes the client return an *HTTPError for responses matching the predicate.
+// If predicate is nil, responses with a status code of 400 or above are treated as errors.
+func WithErrorOnStatus(predicate func(*http.Response) bool) Option {
+	return func(c *Client) {
+		if predicate == nil {
+			predicate = isFailureStatus
+		}
+
+		c.IsErrorStatusFunc = predicate
+	}
+}
+
+// WithErrorDecoder sets a function that decodes failure responses into application-specific errors.
+// The decoder is called for responses matching the error-on-status predicate, or for non-2xx responses
+// if WithErrorOnStatus is not used. A non-nil error returned by the decoder is returned by the client.
+// If the decoder returns nil, the response is handled as usual and its body can still be read.
+func WithErrorDecoder(decoder func(*http.Response) error) Option {
+	return func(c *Client) {
+		c.ErrorDecoder = decoder
+	}
+}
+
+// checkResponse applies the error decoder and error-on-status predicate to the response.
+func (c *Client) checkResponse(resp *http.Response) error {
+	return c.statusError(resp, c.IsErrorStatusFunc != nil && c.IsErrorStatusFunc(resp))
+}
+
+// statusError applies the error decoder to the response, and returns an *HTTPError if the
+// decoder returns nil and isError is set.
+func (c *Client) statusError(resp *http.Response, isError bool) error {
+	if c.ErrorDecoder != nil && (isError || c.IsErrorStatusFunc == nil && !isSuccessStatus(resp)) {
+		body, err := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		if err != nil {
+			return fmt.Errorf("failed to read response body: %w", err)
+		}
+
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		if err := c.ErrorDecoder(resp); err != nil {
+			return err
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	if isError {
+		return newHTTPError(resp, c.Redactor)
+	}
+
+	return nil
+}
+
+func isSuccessStatus(resp *http.Response) bool {
+	return resp.StatusCode >= 200 && resp.StatusCode < 300
+}
+
+func isFailureStatus(resp *http.Response) bool {
+	return resp.StatusCode >= http.StatusBadRequest
+}
+
+// newHTTPError reads a bounded copy of the response body, closes it and
+// returns an *HTTPError describing the response with sensitive data redacted.
+func newHTTPError(resp *http.Response, redactor *Redactor) *HTTPError {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, MaxErrorBodyBytes))
+	_ = resp.Body.Close()
+
+	e := &HTTPError{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		Header:     redactor.Header(resp.Header),
+		Body:       redactor.JSON(body),
+	}
+
+	if resp.Request != nil {
+		e.Method = resp.Request.Method
+		e.URL = redactor.URL(resp.Request.URL)
+	}
+
+	return e
+}
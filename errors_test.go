@@ -0,0 +1,207 @@
+package clink_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/davesavic/clink"
+)
+
+func TestHTTPError_BodyIsBounded(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(strings.Repeat("a", clink.MaxErrorBodyBytes*2)))
+	}))
+	defer server.Close()
+
+	client := clink.NewClient(clink.WithClient(server.Client()), clink.WithErrorOnStatus(nil))
+
+	_, err := client.Get(server.URL + "/path")
+
+	var httpErr *clink.HTTPError
+	if !errors.As(err, &httpErr) {
+		t.Fatalf("expected http error, got: %v", err)
+	}
+
+	if len(httpErr.Body) != clink.MaxErrorBodyBytes {
+		t.Errorf("expected body to be truncated to %d bytes, got: %d", clink.MaxErrorBodyBytes, len(httpErr.Body))
+	}
+
+	if httpErr.URL != server.URL+"/path" {
+		t.Errorf("expected url to be %s, got: %s", server.URL+"/path", httpErr.URL)
+	}
+
+	if !strings.Contains(httpErr.Error(), "500 Internal Server Error") {
+		t.Errorf("expected error message to contain status, got: %s", httpErr.Error())
+	}
+}
+
+func TestErrorOnStatus(t *testing.T) {
+	testCases := []struct {
+		name       string
+		status     int
+		opts       []clink.Option
+		resultFunc func(*http.Response, error) bool
+	}{
+		{
+			name:   "failure status without option",
+			status: http.StatusNotFound,
+			resultFunc: func(resp *http.Response, err error) bool {
+				return err == nil && resp.StatusCode == http.StatusNotFound
+			},
+		},
+		{
+			name:   "failure status with default predicate",
+			status: http.StatusNotFound,
+			opts:   []clink.Option{clink.WithErrorOnStatus(nil)},
+			resultFunc: func(resp *http.Response, err error) bool {
+				var httpErr *clink.HTTPError
+				return resp == nil && errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusNotFound &&
+					httpErr.Method == http.MethodGet && string(httpErr.Body) == "failure" &&
+					httpErr.Header.Get("X-Failure") == "true"
+			},
+		},
+		{
+			name:   "retries exhausted on failure status",
+			status: http.StatusServiceUnavailable,
+			opts: []clink.Option{
+				clink.WithErrorOnStatus(nil),
+				clink.WithRetries(1, func(request *http.Request, response *http.Response, err error) bool {
+					return response != nil && response.StatusCode == http.StatusServiceUnavailable
+				}),
+			},
+			resultFunc: func(resp *http.Response, err error) bool {
+				var httpErr *clink.HTTPError
+				return errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusServiceUnavailable
+			},
+		},
+		{
+			name:   "success status with default predicate",
+			status: http.StatusOK,
+			opts:   []clink.Option{clink.WithErrorOnStatus(nil)},
+			resultFunc: func(resp *http.Response, err error) bool {
+				return err == nil && resp.StatusCode == http.StatusOK
+			},
+		},
+		{
+			name:   "custom predicate",
+			status: http.StatusFound,
+			opts: []clink.Option{clink.WithErrorOnStatus(func(resp *http.Response) bool {
+				return resp.StatusCode != http.StatusOK
+			})},
+			resultFunc: func(resp *http.Response, err error) bool {
+				var httpErr *clink.HTTPError
+				return errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusFound
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("X-Failure", "true")
+				w.WriteHeader(tc.status)
+				_, _ = w.Write([]byte("failure"))
+			}))
+			defer server.Close()
+
+			client := clink.NewClient(append(tc.opts, clink.WithClient(server.Client()))...)
+
+			resp, err := client.Get(server.URL)
+			if !tc.resultFunc(resp, err) {
+				t.Errorf("unexpected result: %v, %v", resp, err)
+			}
+		})
+	}
+}
+
+type apiError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *apiError) Error() string {
+	return e.Code + ": " + e.Message
+}
+
+func TestErrorDecoder(t *testing.T) {
+	decoder := func(resp *http.Response) error {
+		var envelope struct {
+			Error *apiError `json:"error"`
+		}
+		if err := clink.ResponseToJson(resp, &envelope); err != nil || envelope.Error == nil {
+			return nil
+		}
+		return envelope.Error
+	}
+
+	testCases := []struct {
+		name       string
+		status     int
+		body       string
+		opts       []clink.Option
+		resultFunc func(*http.Response, error) bool
+	}{
+		{
+			name:   "decodes error envelope",
+			status: http.StatusBadRequest,
+			body:   `{"error": {"code": "invalid", "message": "bad input"}}`,
+			resultFunc: func(resp *http.Response, err error) bool {
+				var e *apiError
+				return resp == nil && errors.As(err, &e) && e.Code == "invalid"
+			},
+		},
+		{
+			name:   "success responses are not decoded",
+			status: http.StatusOK,
+			body:   `{"error": {"code": "invalid", "message": "bad input"}}`,
+			resultFunc: func(resp *http.Response, err error) bool {
+				return err == nil && resp.StatusCode == http.StatusOK
+			},
+		},
+		{
+			name:   "unrecognised body keeps response readable",
+			status: http.StatusBadRequest,
+			body:   `not json`,
+			resultFunc: func(resp *http.Response, err error) bool {
+				if err != nil {
+					return false
+				}
+				body, _ := io.ReadAll(resp.Body)
+				return string(body) == "not json"
+			},
+		},
+		{
+			name:   "unrecognised body falls back to http error",
+			status: http.StatusBadRequest,
+			body:   `not json`,
+			opts:   []clink.Option{clink.WithErrorOnStatus(nil)},
+			resultFunc: func(resp *http.Response, err error) bool {
+				var httpErr *clink.HTTPError
+				return errors.As(err, &httpErr) && string(httpErr.Body) == "not json"
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tc.status)
+				_, _ = w.Write([]byte(tc.body))
+			}))
+			defer server.Close()
+
+			opts := append(tc.opts, clink.WithClient(server.Client()), clink.WithErrorDecoder(decoder))
+			client := clink.NewClient(opts...)
+
+			resp, err := client.Get(server.URL)
+			if !tc.resultFunc(resp, err) {
+				t.Errorf("unexpected result: %v, %v", resp, err)
+			}
+		})
+	}
+}
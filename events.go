@@ -0,0 +1,141 @@
+package clink
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Event is an event emitted by the client. It is one of RequestStarted, RateLimited,
+// AttemptFinished, RetryScheduled, CircuitOpened or ResponseReceived.
+type Event interface {
+	event()
+}
+
+// RequestStarted is emitted when the client starts sending a request.
+type RequestStarted struct {
+	Request *http.Request
+}
+
+// RateLimited is emitted when a request waited for the rate limiter.
+type RateLimited struct {
+	Request *http.Request
+	Wait    time.Duration
+}
+
+// AttemptFinished is emitted after each attempt to send a request, including retries.
+// Either Response or Err is set.
+type AttemptFinished struct {
+	Request  *http.Request
+	Attempt  int
+	Response *http.Response
+	Err      error
+	Duration time.Duration
+}
+
+// RetryScheduled is emitted when a request will be retried after Delay.
+type RetryScheduled struct {
+	Request  *http.Request
+	Attempt  int
+	Delay    time.Duration
+	Response *http.Response
+	Err      error
+}
+
+// CircuitOpened is emitted when the circuit breaker stops sending the requests with the circuit
+// Key for Timeout, after the failure of Request.
+type CircuitOpened struct {
+	Request *http.Request
+	Key     string
+	Timeout time.Duration
+}
+
+// ResponseReceived is emitted when the client has finished a request. Err is set if the request
+// failed, in which case Response may be nil.
+type ResponseReceived struct {
+	Request  *http.Request
+	Response *http.Response
+	Attempts int
+	Duration time.Duration
+	Err      error
+}
+
+func (RequestStarted) event()   {}
+func (RateLimited) event()      {}
+func (AttemptFinished) event()  {}
+func (RetryScheduled) event()   {}
+func (CircuitOpened) event()    {}
+func (ResponseReceived) event() {}
+
+// WithEventHandler subscribes the handler to the events emitted by the client.
+// The option can be used multiple times to add several handlers.
+func WithEventHandler(handler func(Event)) Option {
+	return func(c *Client) {
+		c.events.subscribe(handler)
+	}
+}
+
+// Subscribe adds a handler for the events emitted by the client and returns a function that
+// removes it. Handlers are called synchronously, in the order they were added, from the goroutine
+// sending the request, so they should not block. It has no effect on an immutable client (see
+// WithImmutable), whose handlers are added with WithEventHandler instead.
+func (c *Client) Subscribe(handler func(Event)) (unsubscribe func()) {
+	if !c.checkMutable("Subscribe") {
+		return func() {}
+	}
+
+	return c.events.subscribe(handler)
+}
+
+// OnEvent adapts a handler for a single event type to a handler for all events, for use with
+// WithEventHandler and Subscribe. Events of other types are ignored.
+func OnEvent[E Event](handler func(E)) func(Event) {
+	return func(e Event) {
+		if typed, ok := e.(E); ok {
+			handler(typed)
+		}
+	}
+}
+
+// eventBus dispatches events to subscribed handlers.
+type eventBus struct {
+	mu       sync.RWMutex
+	nextID   int
+	handlers []eventHandler
+}
+
+type eventHandler struct {
+	id int
+	fn func(Event)
+}
+
+func (b *eventBus) subscribe(fn func(Event)) func() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.nextID++
+	id := b.nextID
+	b.handlers = append(b.handlers, eventHandler{id: id, fn: fn})
+
+	return func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+
+		for i, h := range b.handlers {
+			if h.id == id {
+				b.handlers = append(b.handlers[:i:i], b.handlers[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+func (b *eventBus) emit(e Event) {
+	b.mu.RLock()
+	handlers := b.handlers
+	b.mu.RUnlock()
+
+	for _, h := range handlers {
+		h.fn(e)
+	}
+}
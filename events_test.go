@@ -0,0 +1,109 @@
+package clink_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/davesavic/clink"
+)
+
+func TestEvents(t *testing.T) {
+	testCases := []struct {
+		name       string
+		statuses   []int
+		opts       []clink.Option
+		resultFunc func(events []string) bool
+	}{
+		{
+			name:     "emits events of a successful request",
+			statuses: []int{http.StatusOK},
+			resultFunc: func(events []string) bool {
+				return fmt.Sprint(events) == "[started attempt:1:200 received:200]"
+			},
+		},
+		{
+			name:     "emits retry events",
+			statuses: []int{http.StatusServiceUnavailable, http.StatusOK},
+			opts: []clink.Option{
+				clink.WithRetries(1, func(_ *http.Request, resp *http.Response, _ error) bool {
+					return resp != nil && resp.StatusCode >= 500
+				}),
+			},
+			resultFunc: func(events []string) bool {
+				return fmt.Sprint(events) == "[started attempt:1:503 retry:1 attempt:2:200 received:200]"
+			},
+		},
+		{
+			name:     "emits rate limit events",
+			statuses: []int{http.StatusOK},
+			opts:     []clink.Option{clink.WithRateLimit(600)},
+			resultFunc: func(events []string) bool {
+				return fmt.Sprint(events) == "[started ratelimited attempt:1:200 received:200]"
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var calls int
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(tc.statuses[calls])
+				calls++
+			}))
+			defer server.Close()
+
+			var events []string
+			handler := func(e clink.Event) {
+				switch e := e.(type) {
+				case clink.RequestStarted:
+					events = append(events, "started")
+				case clink.RateLimited:
+					events = append(events, "ratelimited")
+				case clink.AttemptFinished:
+					events = append(events, fmt.Sprintf("attempt:%d:%d", e.Attempt, e.Response.StatusCode))
+				case clink.RetryScheduled:
+					events = append(events, fmt.Sprintf("retry:%d", e.Attempt))
+				case clink.ResponseReceived:
+					events = append(events, fmt.Sprintf("received:%d", e.Response.StatusCode))
+				}
+			}
+
+			c := clink.NewClient(append(tc.opts, clink.WithEventHandler(handler))...)
+
+			resp, err := c.Get(server.URL)
+			if err != nil {
+				t.Fatalf("failed to make request: %v", err)
+			}
+			_ = resp.Body.Close()
+
+			if !tc.resultFunc(events) {
+				t.Errorf("unexpected events: %v", events)
+			}
+		})
+	}
+}
+
+func TestSubscribe(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {}))
+	defer server.Close()
+
+	var first, second int
+	c := clink.NewClient(clink.WithEventHandler(clink.OnEvent(func(clink.RequestStarted) { first++ })))
+	unsubscribe := c.Subscribe(clink.OnEvent(func(clink.ResponseReceived) { second++ }))
+
+	for i := 0; i < 2; i++ {
+		resp, err := c.Get(server.URL)
+		if err != nil {
+			t.Fatalf("failed to make request: %v", err)
+		}
+		_ = resp.Body.Close()
+
+		unsubscribe()
+	}
+
+	if first != 2 || second != 1 {
+		t.Errorf("expected first handler to be called twice and second once, got: %d, %d", first, second)
+	}
+}
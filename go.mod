@@ -1,5 +1,14 @@
 module github.com/davesavic/clink
 
-go 1.21.4
+go 1.23
 
-require golang.org/x/time v0.5.0 // indirect
+require (
+	golang.org/x/time v0.5.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	github.com/kr/pretty v0.3.1 // indirect
+	github.com/rogpeppe/go-internal v1.10.0 // indirect
+	gopkg.in/check.v1 v1.0.0-20201130134442-10cb98267c6c // indirect
+)
@@ -0,0 +1,77 @@
+package clink
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// InvalidHeaderError is returned when a request header has an invalid name or value, for example
+// a value containing CR or LF characters that could inject other headers. The value is left out
+// of the error since it may be sensitive.
+type InvalidHeaderError struct {
+	Key    string
+	Reason string
+}
+
+func (e *InvalidHeaderError) Error() string {
+	return fmt.Sprintf("invalid header %q: %s", e.Key, e.Reason)
+}
+
+// SanitizeHeaderValue returns the value with its control characters removed, other than
+// horizontal tabs, so that it can be safely used as a header value.
+func SanitizeHeaderValue(value string) string {
+	return strings.Map(func(r rune) rune {
+		if r != '\t' && (r < ' ' || r == 0x7f) {
+			return -1
+		}
+		return r
+	}, value)
+}
+
+// checkHeaders returns an *InvalidHeaderError for the first invalid header.
+func checkHeaders(header http.Header) error {
+	for key, values := range header {
+		for _, value := range values {
+			if err := checkHeader(key, value); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkHeader returns an *InvalidHeaderError if the header name isn't a token (RFC 9110 section
+// 5.1) or the value contains control characters other than horizontal tabs.
+func checkHeader(key, value string) error {
+	if key == "" {
+		return &InvalidHeaderError{Key: key, Reason: "empty name"}
+	}
+
+	for i := 0; i < len(key); i++ {
+		if !isTokenChar(key[i]) {
+			return &InvalidHeaderError{Key: key, Reason: fmt.Sprintf("invalid character %q in name", key[i])}
+		}
+	}
+
+	for i := 0; i < len(value); i++ {
+		switch b := value[i]; {
+		case b == '\r' || b == '\n':
+			return &InvalidHeaderError{Key: key, Reason: "line break in value"}
+		case b != '\t' && (b < ' ' || b == 0x7f):
+			return &InvalidHeaderError{Key: key, Reason: fmt.Sprintf("control character %q in value", b)}
+		}
+	}
+
+	return nil
+}
+
+func isTokenChar(b byte) bool {
+	switch {
+	case b >= 'a' && b <= 'z', b >= 'A' && b <= 'Z', b >= '0' && b <= '9':
+		return true
+	default:
+		return strings.IndexByte("!#$%&'*+-.^_`|~", b) >= 0
+	}
+}
@@ -0,0 +1,116 @@
+package clink_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/davesavic/clink"
+)
+
+func TestHeaderValidation(t *testing.T) {
+	testCases := []struct {
+		name       string
+		opts       []clink.Option
+		header     http.Header
+		resultFunc func(error) bool
+	}{
+		{
+			name:   "valid headers",
+			opts:   []clink.Option{clink.WithHeader("X-Api-Key", "secret\twith tab"), clink.WithUserAgent("clink/1.0")},
+			header: http.Header{"X-Trace": {"abc"}},
+			resultFunc: func(err error) bool {
+				return err == nil
+			},
+		},
+		{
+			name: "line break in client header",
+			opts: []clink.Option{clink.WithHeader("X-Api-Key", "secret\r\nX-Admin: true")},
+			resultFunc: func(err error) bool {
+				var invalid *clink.InvalidHeaderError
+				return errors.Is(err, clink.ErrInvalidOption) && errors.As(err, &invalid) && invalid.Key == "X-Api-Key"
+			},
+		},
+		{
+			name: "invalid client header name",
+			opts: []clink.Option{clink.WithHeaders(map[string]string{"X Api Key": "secret"})},
+			resultFunc: func(err error) bool {
+				var invalid *clink.InvalidHeaderError
+				return errors.Is(err, clink.ErrInvalidOption) && errors.As(err, &invalid)
+			},
+		},
+		{
+			name: "line break in bearer token",
+			opts: []clink.Option{clink.WithBearerAuth("token\nX-Admin: true")},
+			resultFunc: func(err error) bool {
+				var invalid *clink.InvalidHeaderError
+				return errors.As(err, &invalid) && invalid.Key == "Authorization"
+			},
+		},
+		{
+			name:   "line break in request header",
+			header: http.Header{"X-Trace": {"abc\nX-Admin: true"}},
+			resultFunc: func(err error) bool {
+				var invalid *clink.InvalidHeaderError
+				return !errors.Is(err, clink.ErrInvalidOption) && errors.As(err, &invalid) && invalid.Key == "X-Trace"
+			},
+		},
+		{
+			name:   "control character in request header",
+			header: http.Header{"X-Trace": {"abc\x00"}},
+			resultFunc: func(err error) bool {
+				var invalid *clink.InvalidHeaderError
+				return errors.As(err, &invalid)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var hits atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				hits.Add(1)
+			}))
+			defer server.Close()
+
+			client := clink.NewClient(tc.opts...)
+
+			req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+			for key, values := range tc.header {
+				req.Header[key] = values
+			}
+
+			resp, err := client.Do(req)
+			if err == nil {
+				_ = resp.Body.Close()
+			}
+
+			if !tc.resultFunc(err) {
+				t.Errorf("unexpected error: %v", err)
+			}
+
+			if err != nil && hits.Load() != 0 {
+				t.Errorf("expected invalid request not to be sent")
+			}
+		})
+	}
+}
+
+func TestSanitizeHeaderValue(t *testing.T) {
+	testCases := []struct {
+		value    string
+		expected string
+	}{
+		{value: "abc", expected: "abc"},
+		{value: "abc\r\nX-Admin: true", expected: "abcX-Admin: true"},
+		{value: "a\tb\x00c\x7f", expected: "a\tbc"},
+	}
+
+	for _, tc := range testCases {
+		if got := clink.SanitizeHeaderValue(tc.value); got != tc.expected {
+			t.Errorf("expected %q, got %q", tc.expected, got)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package clink
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// Default settings of HealthCheck.
+const (
+	DefaultHealthCheckInterval = 10 * time.Second
+	DefaultHealthCheckTimeout  = 5 * time.Second
+	DefaultHealthyThreshold    = 2
+	DefaultUnhealthyThreshold  = 3
+	DefaultHealthCheckPath     = "/"
+)
+
+// HealthCheck configures the active health checking of the endpoints set with WithEndpoints.
+// Zero fields are set to their default.
+type HealthCheck struct {
+	// Path is the path requested on each endpoint, resolved like a request URL. A check succeeds if
+	// the endpoint responds with a 2xx status. DefaultHealthCheckPath is used if it is empty.
+	Path string
+	// Interval is the time between checks. DefaultHealthCheckInterval is used if it is not positive.
+	Interval time.Duration
+	// Timeout is the timeout of a check. DefaultHealthCheckTimeout is used if it is not positive.
+	Timeout time.Duration
+	// HealthyThreshold is the number of consecutive successful checks after which an unhealthy
+	// endpoint is used again. DefaultHealthyThreshold is used if it is not positive.
+	HealthyThreshold int
+	// UnhealthyThreshold is the number of consecutive failed checks after which an endpoint is
+	// no longer used. DefaultUnhealthyThreshold is used if it is not positive.
+	UnhealthyThreshold int
+}
+
+// WithHealthCheck checks the health of the endpoints set with WithEndpoints in the background,
+// starting with the first request of the client. Unhealthy endpoints are only used when every
+// endpoint is unavailable, until they recover. Checks stop when Client.Shutdown is called.
+func WithHealthCheck(check HealthCheck) Option {
+	return func(c *Client) {
+		if check.Path == "" {
+			check.Path = DefaultHealthCheckPath
+		}
+
+		if check.Interval <= 0 {
+			check.Interval = DefaultHealthCheckInterval
+		}
+
+		if check.Timeout <= 0 {
+			check.Timeout = DefaultHealthCheckTimeout
+		}
+
+		if check.HealthyThreshold <= 0 {
+			check.HealthyThreshold = DefaultHealthyThreshold
+		}
+
+		if check.UnhealthyThreshold <= 0 {
+			check.UnhealthyThreshold = DefaultUnhealthyThreshold
+		}
+
+		c.health = &healthChecker{check: check, stop: make(chan struct{})}
+	}
+}
+
+// healthChecker periodically checks the endpoints of a client and marks them healthy or unhealthy.
+type healthChecker struct {
+	check    HealthCheck
+	start    sync.Once
+	stopOnce sync.Once
+	stop     chan struct{}
+}
+
+// healthCounts are the consecutive check results of an endpoint.
+type healthCounts struct {
+	successes int
+	failures  int
+}
+
+func (h *healthChecker) run(c *Client) {
+	h.start.Do(func() {
+		go h.loop(c)
+	})
+}
+
+func (h *healthChecker) close() {
+	h.stopOnce.Do(func() {
+		close(h.stop)
+	})
+}
+
+func (h *healthChecker) loop(c *Client) {
+	counts := make(map[*trackedEndpoint]*healthCounts)
+
+	ticker := time.NewTicker(h.check.Interval)
+	defer ticker.Stop()
+
+	for {
+		h.checkAll(c, counts)
+
+		select {
+		case <-ticker.C:
+		case <-h.stop:
+			return
+		}
+	}
+}
+
+// checkAll checks every endpoint concurrently and updates their health.
+func (h *healthChecker) checkAll(c *Client, counts map[*trackedEndpoint]*healthCounts) {
+	endpoints := c.endpoints.list()
+	results := make([]bool, len(endpoints))
+
+	var wg sync.WaitGroup
+	for i, e := range endpoints {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = h.probe(c, e)
+		}()
+	}
+	wg.Wait()
+
+	for i, e := range endpoints {
+		count, ok := counts[e]
+		if !ok {
+			count = &healthCounts{}
+			counts[e] = count
+		}
+
+		if results[i] {
+			count.successes++
+			count.failures = 0
+			if count.successes >= h.check.HealthyThreshold {
+				e.unhealthy.Store(false)
+			}
+		} else {
+			count.failures++
+			count.successes = 0
+			if count.failures >= h.check.UnhealthyThreshold {
+				e.unhealthy.Store(true)
+			}
+		}
+	}
+}
+
+// probe sends a health check request to the endpoint and reports whether it succeeded.
+func (h *healthChecker) probe(c *Client, e *trackedEndpoint) bool {
+	ref, err := url.Parse(h.check.Path)
+	if err != nil {
+		return false
+	}
+
+	u, err := resolveURL(e.url, ref)
+	if err != nil {
+		return false
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.check.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return false
+	}
+
+	resp, err := c.HttpClient.Do(req)
+	if err != nil {
+		return false
+	}
+	_ = DrainAndClose(resp)
+
+	return isSuccessStatus(resp)
+}
@@ -0,0 +1,70 @@
+package clink_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/davesavic/clink"
+)
+
+func TestWithHealthCheck(t *testing.T) {
+	var primaryHealthy atomic.Bool
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/healthz" && !primaryHealthy.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte("primary"))
+	}))
+	defer primary.Close()
+
+	fallback := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("fallback"))
+	}))
+	defer fallback.Close()
+
+	c := clink.NewClient(
+		clink.WithEndpoints(primary.URL+"/api", fallback.URL+"/api"),
+		clink.WithHealthCheck(clink.HealthCheck{
+			Path:               "/healthz",
+			Interval:           5 * time.Millisecond,
+			HealthyThreshold:   2,
+			UnhealthyThreshold: 2,
+		}),
+	)
+	defer c.Shutdown(context.Background())
+
+	get := func() string {
+		resp, err := c.Get("/users")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		defer resp.Body.Close()
+
+		body, _ := io.ReadAll(resp.Body)
+		return string(body)
+	}
+
+	waitFor := func(expected string) {
+		t.Helper()
+
+		deadline := time.Now().Add(time.Second)
+		for get() != expected {
+			if time.Now().After(deadline) {
+				t.Fatalf("expected requests to be sent to the %s endpoint", expected)
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+
+	// The primary endpoint serves requests, but fails its health checks.
+	waitFor("fallback")
+
+	primaryHealthy.Store(true)
+	waitFor("primary")
+}
@@ -0,0 +1,122 @@
+package clink
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// HostPolicyError is returned when a request, or one of its redirects, is refused because its host
+// isn't allowed by WithAllowedHosts or is blocked by WithBlockedHosts.
+type HostPolicyError struct {
+	Host string
+	// Blocked reports whether the host matches a pattern of WithBlockedHosts, rather than matching
+	// no pattern of WithAllowedHosts.
+	Blocked bool
+}
+
+func (e *HostPolicyError) Error() string {
+	if e.Blocked {
+		return fmt.Sprintf("host %q is blocked", e.Host)
+	}
+
+	return fmt.Sprintf("host %q is not allowed", e.Host)
+}
+
+// WithAllowedHosts restricts the requests of the client, including redirects, to the hosts matching
+// one of the patterns, such as "api.example.com" or "*.example.com" (see path.Match). Hosts are
+// matched without their port, case-insensitively. Requests to other hosts fail with a
+// *HostPolicyError. The option can be used multiple times to allow more hosts. When used with
+// WithClient, it must come after it.
+func WithAllowedHosts(patterns ...string) Option {
+	return func(c *Client) {
+		if !validHostPatterns(c, "WithAllowedHosts", patterns) {
+			return
+		}
+
+		policy := c.hostPolicySet()
+		policy.allowed = append(policy.allowed, patterns...)
+		c.installRedirectHook("WithAllowedHosts")
+	}
+}
+
+// WithBlockedHosts refuses the requests of the client, including redirects, to the hosts matching one
+// of the patterns, matched like WithAllowedHosts. Blocked hosts are refused even if they are allowed.
+// The option can be used multiple times to block more hosts. When used with WithClient, it must come after it.
+func WithBlockedHosts(patterns ...string) Option {
+	return func(c *Client) {
+		if !validHostPatterns(c, "WithBlockedHosts", patterns) {
+			return
+		}
+
+		policy := c.hostPolicySet()
+		policy.blocked = append(policy.blocked, patterns...)
+		c.installRedirectHook("WithBlockedHosts")
+	}
+}
+
+// checkDestination returns an error if a request or redirect to the URL is refused by the host
+// policy of the client or by WithHTTPSOnly.
+func (c *Client) checkDestination(u *url.URL) error {
+	if c.httpsOnly && !strings.EqualFold(u.Scheme, "https") {
+		return fmt.Errorf("%w: %s", ErrInsecureScheme, u.Redacted())
+	}
+
+	if c.hostPolicy != nil {
+		return c.hostPolicy.check(u)
+	}
+
+	return nil
+}
+
+func (c *Client) hostPolicySet() *hostPolicy {
+	if c.hostPolicy == nil {
+		c.hostPolicy = &hostPolicy{}
+	}
+
+	return c.hostPolicy
+}
+
+func validHostPatterns(c *Client, option string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			c.addConfigError("%s: invalid pattern %q", option, pattern)
+			return false
+		}
+	}
+
+	return true
+}
+
+// hostPolicy is the set of hosts a client may send requests to.
+type hostPolicy struct {
+	// allowed are the patterns of the allowed hosts, or nil if every host is allowed.
+	allowed []string
+	blocked []string
+}
+
+// check returns a *HostPolicyError if the host of the URL isn't allowed.
+func (p *hostPolicy) check(u *url.URL) error {
+	host := strings.ToLower(u.Hostname())
+
+	if matchHost(p.blocked, host) {
+		return &HostPolicyError{Host: host, Blocked: true}
+	}
+
+	if p.allowed != nil && !matchHost(p.allowed, host) {
+		return &HostPolicyError{Host: host}
+	}
+
+	return nil
+}
+
+func matchHost(patterns []string, host string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(strings.ToLower(pattern), host); ok {
+			return true
+		}
+	}
+
+	return false
+}
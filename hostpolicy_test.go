@@ -0,0 +1,95 @@
+package clink_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/davesavic/clink"
+)
+
+func TestHostPolicy(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/redirect" {
+			http.Redirect(w, r, r.URL.Query().Get("to"), http.StatusFound)
+		}
+	}))
+	defer server.Close()
+
+	_, port, _ := strings.Cut(strings.TrimPrefix(server.URL, "http://"), ":")
+	localhost := "http://localhost:" + port
+
+	testCases := []struct {
+		name       string
+		opts       []clink.Option
+		url        string
+		resultFunc func(*testing.T, error)
+	}{
+		{
+			name: "allowed host",
+			opts: []clink.Option{clink.WithAllowedHosts("127.0.0.*")},
+			url:  server.URL,
+			resultFunc: func(t *testing.T, err error) {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+			},
+		},
+		{
+			name: "host not allowed",
+			opts: []clink.Option{clink.WithAllowedHosts("api.example.com", "*.example.com")},
+			url:  server.URL,
+			resultFunc: func(t *testing.T, err error) {
+				var policyErr *clink.HostPolicyError
+				if !errors.As(err, &policyErr) || policyErr.Host != "127.0.0.1" || policyErr.Blocked {
+					t.Errorf("expected a HostPolicyError, got: %v", err)
+				}
+			},
+		},
+		{
+			name: "blocked host",
+			opts: []clink.Option{clink.WithAllowedHosts("*"), clink.WithBlockedHosts("LOCALHOST")},
+			url:  localhost,
+			resultFunc: func(t *testing.T, err error) {
+				var policyErr *clink.HostPolicyError
+				if !errors.As(err, &policyErr) || !policyErr.Blocked {
+					t.Errorf("expected a blocked HostPolicyError, got: %v", err)
+				}
+			},
+		},
+		{
+			name: "redirect to host not allowed",
+			opts: []clink.Option{clink.WithAllowedHosts("127.0.0.1")},
+			url:  server.URL + "/redirect?to=" + localhost,
+			resultFunc: func(t *testing.T, err error) {
+				var policyErr *clink.HostPolicyError
+				if !errors.As(err, &policyErr) || policyErr.Host != "localhost" {
+					t.Errorf("expected the redirect to be refused, got: %v", err)
+				}
+			},
+		},
+		{
+			name: "invalid pattern",
+			opts: []clink.Option{clink.WithBlockedHosts("[")},
+			url:  server.URL,
+			resultFunc: func(t *testing.T, err error) {
+				if !errors.Is(err, clink.ErrInvalidOption) {
+					t.Errorf("expected ErrInvalidOption, got: %v", err)
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, err := clink.NewClient(tc.opts...).Get(tc.url)
+			if err == nil {
+				_ = resp.Body.Close()
+			}
+
+			tc.resultFunc(t, err)
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package clink
+
+import (
+	"crypto/rand"
+	"fmt"
+	"net/http"
+)
+
+// DefaultIdempotencyKeyHeader is the header used to send idempotency keys.
+const DefaultIdempotencyKeyHeader = "Idempotency-Key"
+
+// WithIdempotencyKey attaches a generated Idempotency-Key header to requests using unsafe methods
+// (POST, PUT, PATCH, DELETE, ...). The same key is sent on every retry of the request so that the
+// server can safely deduplicate them. Requests that already carry the header are left untouched.
+func WithIdempotencyKey() Option {
+	return func(c *Client) {
+		c.IdempotencyKeyHeader = DefaultIdempotencyKeyHeader
+	}
+}
+
+// isSafeMethod reports whether the method is safe as defined by RFC 9110.
+func isSafeMethod(method string) bool {
+	switch method {
+	case "", http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return true
+	}
+	return false
+}
+
+// newUUID returns a random (version 4) UUID.
+func newUUID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
@@ -0,0 +1,103 @@
+package clink_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/davesavic/clink"
+)
+
+func TestIdempotencyKey(t *testing.T) {
+	testCases := []struct {
+		name       string
+		method     string
+		header     string
+		resultFunc func(keys []string) bool
+	}{
+		{
+			name:   "key is reused across retries of unsafe methods",
+			method: http.MethodPost,
+			resultFunc: func(keys []string) bool {
+				return len(keys) == 2 && len(keys[0]) == 36 && keys[0] == keys[1]
+			},
+		},
+		{
+			name:   "key is not added to safe methods",
+			method: http.MethodGet,
+			resultFunc: func(keys []string) bool {
+				return len(keys) == 2 && keys[0] == "" && keys[1] == ""
+			},
+		},
+		{
+			name:   "existing key is preserved",
+			method: http.MethodPatch,
+			header: "my-key",
+			resultFunc: func(keys []string) bool {
+				return len(keys) == 2 && keys[0] == "my-key" && keys[1] == "my-key"
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var keys []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				keys = append(keys, r.Header.Get(clink.DefaultIdempotencyKeyHeader))
+				if len(keys) == 1 {
+					w.WriteHeader(http.StatusServiceUnavailable)
+				}
+			}))
+			defer server.Close()
+
+			client := clink.NewClient(
+				clink.WithClient(server.Client()),
+				clink.WithIdempotencyKey(),
+				clink.WithRetries(1, func(request *http.Request, response *http.Response, err error) bool {
+					return response != nil && response.StatusCode == http.StatusServiceUnavailable
+				}),
+			)
+
+			req, err := http.NewRequest(tc.method, server.URL, strings.NewReader("body"))
+			if err != nil {
+				t.Fatalf("failed to create request: %v", err)
+			}
+			if tc.header != "" {
+				req.Header.Set(clink.DefaultIdempotencyKeyHeader, tc.header)
+			}
+
+			if _, err := client.Do(req); err != nil {
+				t.Fatalf("failed to make request: %v", err)
+			}
+
+			if !tc.resultFunc(keys) {
+				t.Errorf("unexpected idempotency keys: %v", keys)
+			}
+		})
+	}
+}
+
+func TestIdempotencyKey_RequestReused(t *testing.T) {
+	var keys []string
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get(clink.DefaultIdempotencyKeyHeader))
+	}))
+	defer server.Close()
+
+	client := clink.NewClient(clink.WithIdempotencyKey())
+
+	req, _ := http.NewRequest(http.MethodPost, server.URL, nil)
+	for range 2 {
+		if _, err := client.Do(req); err != nil {
+			t.Fatalf("failed to make request: %v", err)
+		}
+	}
+
+	if req.Header.Get(clink.DefaultIdempotencyKeyHeader) != "" {
+		t.Errorf("expected the request header to be left unchanged, got: %v", req.Header)
+	}
+	if len(keys) != 2 || keys[0] == "" || keys[0] == keys[1] {
+		t.Errorf("expected a new key for each send, got: %v", keys)
+	}
+}
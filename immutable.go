@@ -0,0 +1,63 @@
+package clink
+
+import (
+	"log/slog"
+	"maps"
+	"slices"
+)
+
+// WithImmutable makes the client immutable: its configuration is frozen once the options are
+// applied, so goroutines sharing the client always send requests with the same configuration.
+// Later changes to the exported fields of the client have no effect, and calls to SetDebug and
+// Subscribe are ignored with a warning logged. Reconfigured clients are derived with With, which
+// returns a new immutable client.
+func WithImmutable() Option {
+	return func(c *Client) {
+		c.immutable = true
+	}
+}
+
+// freeze keeps a private copy of the client configuration, used by the client from then on.
+func (c *Client) freeze() {
+	frozen := *c
+	frozen.Headers = maps.Clone(c.Headers)
+	frozen.QueryParams = maps.Clone(c.QueryParams)
+	frozen.Codecs = maps.Clone(c.Codecs)
+	frozen.RedirectHeaderPolicy = RedirectHeaderPolicy{
+		Strip:   slices.Clone(c.RedirectHeaderPolicy.Strip),
+		Forward: slices.Clone(c.RedirectHeaderPolicy.Forward),
+	}
+
+	client := *c.HttpClient
+	frozen.HttpClient = &client
+	if c.redirectHook {
+		client.CheckRedirect = frozen.checkRedirect
+	}
+
+	c.frozen = &frozen
+}
+
+// config returns the frozen configuration of an immutable client, or the client itself.
+func (c *Client) config() *Client {
+	if c.frozen != nil {
+		return c.frozen
+	}
+
+	return c
+}
+
+// checkMutable reports whether the client can be changed by the named method, logging a warning
+// if the client is immutable.
+func (c *Client) checkMutable(method string) bool {
+	if c.frozen == nil {
+		return true
+	}
+
+	logger := c.frozen.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	logger.Warn(method+" has no effect on an immutable client, use With to derive a client", "method", method)
+
+	return false
+}
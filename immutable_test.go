@@ -0,0 +1,115 @@
+package clink_test
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/davesavic/clink"
+)
+
+func TestWithImmutable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/redirect" {
+			http.Redirect(w, r, "/", http.StatusFound)
+			return
+		}
+		_, _ = w.Write([]byte(r.Header.Get("X-Tenant")))
+	}))
+	defer server.Close()
+
+	testCases := []struct {
+		name       string
+		client     func(base *clink.Client) *clink.Client
+		path       string
+		resultFunc func(*clink.Response, error) bool
+	}{
+		{
+			name: "exported fields changed",
+			client: func(base *clink.Client) *clink.Client {
+				base.Headers["X-Tenant"] = "other"
+				base.FollowRedirects = false
+				return base
+			},
+			path: "/redirect",
+			resultFunc: func(resp *clink.Response, err error) bool {
+				return err == nil && resp.StatusCode == http.StatusOK && resp.String() == "base"
+			},
+		},
+		{
+			name: "derived with options",
+			client: func(base *clink.Client) *clink.Client {
+				return base.With(clink.WithHeader("X-Tenant", "acme"))
+			},
+			resultFunc: func(resp *clink.Response, err error) bool {
+				return err == nil && resp.String() == "acme"
+			},
+		},
+		{
+			name: "derived from changed client",
+			client: func(base *clink.Client) *clink.Client {
+				base.Headers["X-Tenant"] = "other"
+				return base.Clone()
+			},
+			resultFunc: func(resp *clink.Response, err error) bool {
+				return err == nil && resp.String() == "base"
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			base := clink.NewClient(clink.WithImmutable(), clink.WithBaseURL(server.URL), clink.WithHeader("X-Tenant", "base"))
+			c := tc.client(base)
+
+			path := tc.path
+			if path == "" {
+				path = "/"
+			}
+
+			resp, err := doWrapped(c, path)
+			if !tc.resultFunc(resp, err) {
+				t.Errorf("unexpected result: %v", err)
+			}
+		})
+	}
+}
+
+func TestWithImmutable_RuntimeChanges(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {}))
+	defer server.Close()
+
+	var logs, dump bytes.Buffer
+	c := clink.NewClient(
+		clink.WithImmutable(),
+		clink.WithLogger(slog.New(slog.NewTextHandler(&logs, nil))),
+		clink.WithDebug(&dump),
+	)
+
+	c.SetDebug(false)
+
+	var events int
+	unsubscribe := c.Subscribe(func(clink.Event) { events++ })
+	defer unsubscribe()
+
+	resp, err := c.Get(server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_ = resp.Body.Close()
+
+	if dump.Len() == 0 {
+		t.Error("expected SetDebug to have no effect")
+	}
+
+	if events != 0 {
+		t.Errorf("expected Subscribe to have no effect, got %d events", events)
+	}
+
+	if n := strings.Count(logs.String(), "immutable client"); n != 2 {
+		t.Errorf("expected 2 warnings, got: %s", logs.String())
+	}
+}
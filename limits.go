@@ -0,0 +1,121 @@
+package clink
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// ResponseTooLargeError is returned when a response body exceeds the limit set by WithMaxResponseBytes.
+type ResponseTooLargeError struct {
+	Limit int64
+}
+
+func (e *ResponseTooLargeError) Error() string {
+	return fmt.Sprintf("response body exceeds limit of %d bytes", e.Limit)
+}
+
+// WithMaxResponseBytes limits the size of response bodies to n bytes.
+// Responses with a larger Content-Length are rejected by the client, and reading past the limit
+// of a body with an unknown length returns a *ResponseTooLargeError.
+func WithMaxResponseBytes(n int64) Option {
+	return func(c *Client) {
+		c.MaxResponseBytes = n
+	}
+}
+
+// RequestTooLargeError is returned when a request body exceeds the limit set with WithMaxRequestBytes.
+type RequestTooLargeError struct {
+	Limit int64
+}
+
+func (e *RequestTooLargeError) Error() string {
+	return fmt.Sprintf("request body exceeds limit of %d bytes", e.Limit)
+}
+
+// WithMaxRequestBytes limits the size of request bodies to n bytes. Requests with a larger
+// Content-Length, buffered body or file body (see FileBody) are rejected before being sent.
+// Other streamed bodies are aborted once they exceed the limit. Rejected requests fail with a
+// *RequestTooLargeError.
+func WithMaxRequestBytes(n int64) Option {
+	return func(c *Client) {
+		c.MaxRequestBytes = n
+	}
+}
+
+// readRequestBody reads and closes the body of the request, failing with a *RequestTooLargeError
+// without reading it all if it exceeds MaxRequestBytes.
+func (c *Client) readRequestBody(req *http.Request) ([]byte, error) {
+	limit := c.MaxRequestBytes
+	if limit > 0 && req.ContentLength > limit {
+		_ = req.Body.Close()
+		return nil, &RequestTooLargeError{Limit: limit}
+	}
+
+	var r io.Reader = req.Body
+	if limit > 0 {
+		r = io.LimitReader(req.Body, limit+1)
+	}
+
+	body, err := io.ReadAll(r)
+	if err != nil {
+		_ = req.Body.Close()
+		return nil, fmt.Errorf("failed to read request body: %w", err)
+	}
+
+	if err := req.Body.Close(); err != nil {
+		return nil, fmt.Errorf("failed to close request body: %w", err)
+	}
+
+	if limit > 0 && int64(len(body)) > limit {
+		return nil, &RequestTooLargeError{Limit: limit}
+	}
+
+	return body, nil
+}
+
+// checkBodySourceSize returns a *RequestTooLargeError if the size of the source is known and
+// exceeds MaxRequestBytes.
+func (c *Client) checkBodySourceSize(source BodySource) error {
+	sized, ok := source.(interface{ size() (int64, error) })
+	if c.MaxRequestBytes <= 0 || !ok {
+		return nil
+	}
+
+	if n, err := sized.size(); err == nil && n > c.MaxRequestBytes {
+		return &RequestTooLargeError{Limit: c.MaxRequestBytes}
+	}
+
+	return nil
+}
+
+// limitedBody is a body that fails with tooLarge once more than limit bytes are read.
+type limitedBody struct {
+	io.ReadCloser
+	remaining int64
+	tooLarge  error
+}
+
+func newLimitedBody(body io.ReadCloser, limit int64) *limitedBody {
+	return &limitedBody{ReadCloser: body, remaining: limit, tooLarge: &ResponseTooLargeError{Limit: limit}}
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.remaining <= 0 {
+		var probe [1]byte
+		n, err := b.ReadCloser.Read(probe[:])
+		if n > 0 {
+			return 0, b.tooLarge
+		}
+		return 0, err
+	}
+
+	if int64(len(p)) > b.remaining {
+		p = p[:b.remaining]
+	}
+
+	n, err := b.ReadCloser.Read(p)
+	b.remaining -= int64(n)
+
+	return n, err
+}
@@ -0,0 +1,165 @@
+package clink_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/davesavic/clink"
+)
+
+func TestMaxResponseBytes(t *testing.T) {
+	testCases := []struct {
+		name       string
+		body       string
+		chunked    bool
+		resultFunc func(*http.Response, error) bool
+	}{
+		{
+			name: "body within limit",
+			body: strings.Repeat("a", 10),
+			resultFunc: func(resp *http.Response, err error) bool {
+				if err != nil {
+					return false
+				}
+				body, err := io.ReadAll(resp.Body)
+				return err == nil && len(body) == 10
+			},
+		},
+		{
+			name: "content length exceeds limit",
+			body: strings.Repeat("a", 11),
+			resultFunc: func(resp *http.Response, err error) bool {
+				var tooLarge *clink.ResponseTooLargeError
+				return resp == nil && errors.As(err, &tooLarge) && tooLarge.Limit == 10
+			},
+		},
+		{
+			name:    "streamed body exceeds limit",
+			body:    strings.Repeat("a", 11),
+			chunked: true,
+			resultFunc: func(resp *http.Response, err error) bool {
+				if err != nil {
+					return false
+				}
+				_, err = io.ReadAll(resp.Body)
+				var tooLarge *clink.ResponseTooLargeError
+				return errors.As(err, &tooLarge)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tc.chunked {
+					_, _ = w.Write([]byte(tc.body[:5]))
+					w.(http.Flusher).Flush()
+					_, _ = w.Write([]byte(tc.body[5:]))
+					return
+				}
+				_, _ = w.Write([]byte(tc.body))
+			}))
+			defer server.Close()
+
+			client := clink.NewClient(clink.WithClient(server.Client()), clink.WithMaxResponseBytes(10))
+
+			resp, err := client.Get(server.URL)
+			if !tc.resultFunc(resp, err) {
+				t.Errorf("unexpected result: %v, %v", resp, err)
+			}
+		})
+	}
+}
+
+func TestMaxRequestBytes(t *testing.T) {
+	var hits atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		_, _ = io.Copy(io.Discard, r.Body)
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "upload.bin")
+	if err := os.WriteFile(path, []byte(strings.Repeat("x", 20)), 0o644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	testCases := []struct {
+		name     string
+		request  func() *http.Request
+		tooLarge bool
+		sent     bool
+	}{
+		{
+			name: "body within limit",
+			request: func() *http.Request {
+				req, _ := http.NewRequest(http.MethodPost, server.URL, strings.NewReader("0123456789"))
+				return req
+			},
+			sent: true,
+		},
+		{
+			name: "content length over limit",
+			request: func() *http.Request {
+				req, _ := http.NewRequest(http.MethodPost, server.URL, strings.NewReader(strings.Repeat("x", 11)))
+				return req
+			},
+			tooLarge: true,
+		},
+		{
+			name: "unknown length over limit",
+			request: func() *http.Request {
+				req, _ := http.NewRequest(http.MethodPost, server.URL, io.MultiReader(strings.NewReader(strings.Repeat("x", 11))))
+				return req
+			},
+			tooLarge: true,
+		},
+		{
+			name: "file over limit",
+			request: func() *http.Request {
+				req, _ := http.NewRequest(http.MethodPost, server.URL, nil)
+				return clink.ConfigureRequest(req, clink.StreamBody(clink.FileBody(path)))
+			},
+			tooLarge: true,
+		},
+		{
+			name: "stream over limit",
+			request: func() *http.Request {
+				req, _ := http.NewRequest(http.MethodPost, server.URL, nil)
+				return clink.ConfigureRequest(req, clink.StreamBody(clink.BodySourceFunc(func() (io.ReadCloser, error) {
+					return io.NopCloser(strings.NewReader(strings.Repeat("x", 20))), nil
+				})))
+			},
+			tooLarge: true,
+			sent:     true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			hits.Store(0)
+			c := clink.NewClient(clink.WithMaxRequestBytes(10))
+
+			resp, err := c.Do(tc.request())
+			if err == nil {
+				_ = resp.Body.Close()
+			}
+
+			var tooLarge *clink.RequestTooLargeError
+			if errors.As(err, &tooLarge) != tc.tooLarge {
+				t.Errorf("expected too large to be %v, got: %v", tc.tooLarge, err)
+			}
+
+			if !tc.sent && hits.Load() != 0 {
+				t.Errorf("expected the request not to be sent")
+			}
+		})
+	}
+}
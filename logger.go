@@ -0,0 +1,87 @@
+package clink
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// LogLevels configures the levels at which the client logs its activity.
+type LogLevels struct {
+	// Request is the level of the message logged when a request starts.
+	Request slog.Level
+	// Response is the level of the message logged when a request finishes.
+	Response slog.Level
+	// Retry is the level of the message logged when a request is retried.
+	Retry slog.Level
+	// RateLimit is the level of the message logged when a request waited for the rate limiter.
+	RateLimit slog.Level
+	// Error is the level of the message logged when a request fails.
+	Error slog.Level
+}
+
+// DefaultLogLevels are the log levels used by clients unless WithLogLevels is used.
+var DefaultLogLevels = LogLevels{
+	Request:   slog.LevelDebug,
+	Response:  slog.LevelInfo,
+	Retry:     slog.LevelWarn,
+	RateLimit: slog.LevelDebug,
+	Error:     slog.LevelError,
+}
+
+// WithLogger sets the logger used to log request starts and finishes, statuses, durations,
+// retries and rate limiter waits.
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *Client) {
+		c.Logger = logger
+	}
+}
+
+// WithLogLevels sets the levels at which the client logs its activity.
+func WithLogLevels(levels LogLevels) Option {
+	return func(c *Client) {
+		c.LogLevels = levels
+	}
+}
+
+// log logs the message with the request attributes if the client has a logger.
+func (c *Client) log(req *http.Request, level slog.Level, msg string, attrs ...slog.Attr) {
+	if c.Logger == nil {
+		return
+	}
+
+	ctx := req.Context()
+	if !c.Logger.Enabled(ctx, level) {
+		return
+	}
+
+	attrs = append([]slog.Attr{
+		slog.String("method", req.Method),
+		slog.String("url", c.Redactor.URL(req.URL)),
+	}, attrs...)
+
+	if id, ok := RequestIDFromContext(ctx); ok {
+		attrs = append(attrs, slog.String("request_id", id))
+	}
+
+	c.Logger.LogAttrs(context.WithoutCancel(ctx), level, msg, attrs...)
+}
+
+// logRetry logs that the request is about to be retried after the given delay.
+func (c *Client) logRetry(req *http.Request, resp *http.Response, err error, attempt int, delay time.Duration) {
+	attrs := []slog.Attr{
+		slog.Int("attempt", attempt),
+		slog.Duration("delay", delay),
+	}
+
+	if resp != nil {
+		attrs = append(attrs, slog.Int("status", resp.StatusCode))
+	}
+
+	if err != nil {
+		attrs = append(attrs, slog.Any("error", err))
+	}
+
+	c.log(req, c.LogLevels.Retry, "retrying request", attrs...)
+}
@@ -0,0 +1,130 @@
+package clink_test
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/davesavic/clink"
+)
+
+func TestWithLogger(t *testing.T) {
+	testCases := []struct {
+		name       string
+		status     int
+		opts       func(*slog.Logger) []clink.Option
+		resultFunc func(string) bool
+	}{
+		{
+			name:   "logs request finish with status and duration",
+			status: http.StatusOK,
+			opts: func(l *slog.Logger) []clink.Option {
+				return []clink.Option{clink.WithLogger(l)}
+			},
+			resultFunc: func(out string) bool {
+				return strings.Contains(out, `level=INFO msg="request finished" method=GET`) &&
+					strings.Contains(out, "status=200") &&
+					strings.Contains(out, "duration=") &&
+					strings.Contains(out, "attempts=1")
+			},
+		},
+		{
+			name:   "logs request start at debug level",
+			status: http.StatusOK,
+			opts: func(l *slog.Logger) []clink.Option {
+				return []clink.Option{clink.WithLogger(l)}
+			},
+			resultFunc: func(out string) bool {
+				return strings.Contains(out, `level=DEBUG msg="request started"`)
+			},
+		},
+		{
+			name:   "logs retries",
+			status: http.StatusServiceUnavailable,
+			opts: func(l *slog.Logger) []clink.Option {
+				return []clink.Option{
+					clink.WithLogger(l),
+					clink.WithRetries(1, func(_ *http.Request, resp *http.Response, _ error) bool {
+						return resp != nil && resp.StatusCode >= 500
+					}),
+				}
+			},
+			resultFunc: func(out string) bool {
+				return strings.Contains(out, `level=WARN msg="retrying request"`) &&
+					strings.Contains(out, "attempt=1") &&
+					strings.Contains(out, "status=503")
+			},
+		},
+		{
+			name:   "logs failures at error level",
+			status: http.StatusNotFound,
+			opts: func(l *slog.Logger) []clink.Option {
+				return []clink.Option{clink.WithLogger(l), clink.WithErrorOnStatus(nil)}
+			},
+			resultFunc: func(out string) bool {
+				return strings.Contains(out, `level=ERROR msg="request failed"`)
+			},
+		},
+		{
+			name:   "includes request id",
+			status: http.StatusOK,
+			opts: func(l *slog.Logger) []clink.Option {
+				return []clink.Option{
+					clink.WithLogger(l),
+					clink.WithRequestID(func() string { return "abc" }, ""),
+				}
+			},
+			resultFunc: func(out string) bool {
+				return strings.Contains(out, "request_id=abc")
+			},
+		},
+		{
+			name:   "uses configured levels",
+			status: http.StatusOK,
+			opts: func(l *slog.Logger) []clink.Option {
+				levels := clink.DefaultLogLevels
+				levels.Response = slog.LevelWarn
+				return []clink.Option{clink.WithLogger(l), clink.WithLogLevels(levels)}
+			},
+			resultFunc: func(out string) bool {
+				return strings.Contains(out, `level=WARN msg="request finished"`)
+			},
+		},
+		{
+			name:   "redacts url password",
+			status: http.StatusOK,
+			opts: func(l *slog.Logger) []clink.Option {
+				return []clink.Option{clink.WithLogger(l)}
+			},
+			resultFunc: func(out string) bool {
+				return !strings.Contains(out, "secret")
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(tc.status)
+			}))
+			defer server.Close()
+
+			var buf bytes.Buffer
+			logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+			c := clink.NewClient(tc.opts(logger)...)
+			url := strings.Replace(server.URL, "http://", "http://user:secret@", 1)
+			resp, err := c.Get(url)
+			if err == nil {
+				_ = resp.Body.Close()
+			}
+
+			if !tc.resultFunc(buf.String()) {
+				t.Errorf("unexpected log output: %s", buf.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package clink
+
+import (
+	"net/http"
+	"time"
+)
+
+// Metrics receives measurements of the requests sent by a client.
+// Implementations must be safe for concurrent use. See the metrics/prometheus
+// package for a ready Prometheus implementation.
+type Metrics interface {
+	// RequestStarted is called when a request starts.
+	RequestStarted(method, host string)
+	// RequestFinished is called when a request finishes. The status code is 0 if the request failed
+	// without a response.
+	RequestFinished(method, host string, statusCode int, duration time.Duration, err error)
+	// RequestRetried is called each time a request is retried.
+	RequestRetried(method, host string)
+	// RateLimitWaited is called with the time a request waited for the rate limiter.
+	RateLimitWaited(method, host string, wait time.Duration)
+}
+
+// WithMetrics sets the collector that receives the client's request metrics.
+func WithMetrics(metrics Metrics) Option {
+	return func(c *Client) {
+		c.Metrics = metrics
+	}
+}
+
+// StatusClass returns the class of the status code, such as "2xx", or "error" if it is 0.
+func StatusClass(statusCode int) string {
+	if statusCode < 100 || statusCode > 599 {
+		return "error"
+	}
+
+	return string(rune('0'+statusCode/100)) + "xx"
+}
+
+func (c *Client) metricsStarted(req *http.Request) {
+	if c.Metrics != nil {
+		c.Metrics.RequestStarted(req.Method, req.URL.Host)
+	}
+}
+
+func (c *Client) metricsFinished(req *http.Request, resp *http.Response, duration time.Duration, err error) {
+	if c.Metrics == nil {
+		return
+	}
+
+	var statusCode int
+	if resp != nil {
+		statusCode = resp.StatusCode
+	}
+
+	c.Metrics.RequestFinished(req.Method, req.URL.Host, statusCode, duration, err)
+}
+
+func (c *Client) metricsRetried(req *http.Request) {
+	if c.Metrics != nil {
+		c.Metrics.RequestRetried(req.Method, req.URL.Host)
+	}
+}
+
+func (c *Client) metricsRateLimitWaited(req *http.Request, wait time.Duration) {
+	if c.Metrics != nil {
+		c.Metrics.RateLimitWaited(req.Method, req.URL.Host, wait)
+	}
+}
@@ -0,0 +1,87 @@
+// Package prometheus provides a clink metrics collector backed by Prometheus.
+//
+// Register it on a client with clink.WithMetrics(prometheus.NewCollector(registerer)).
+package prometheus
+
+import (
+	"time"
+
+	"github.com/davesavic/clink"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Namespace is the namespace of the metrics registered by the collector.
+const Namespace = "clink"
+
+// Collector records client metrics as Prometheus metrics labeled by method and host.
+type Collector struct {
+	duration      *prometheus.HistogramVec
+	inFlight      *prometheus.GaugeVec
+	responses     *prometheus.CounterVec
+	retries       *prometheus.CounterVec
+	rateLimitWait *prometheus.HistogramVec
+}
+
+var _ clink.Metrics = (*Collector)(nil)
+
+// NewCollector creates a collector and registers its metrics with the registerer.
+// If registerer is nil, prometheus.DefaultRegisterer is used.
+func NewCollector(registerer prometheus.Registerer) *Collector {
+	if registerer == nil {
+		registerer = prometheus.DefaultRegisterer
+	}
+
+	labels := []string{"method", "host"}
+
+	c := &Collector{
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Name:      "request_duration_seconds",
+			Help:      "Duration of HTTP requests, including retries.",
+			Buckets:   prometheus.DefBuckets,
+		}, labels),
+		inFlight: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Name:      "requests_in_flight",
+			Help:      "Number of HTTP requests in flight.",
+		}, labels),
+		responses: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "requests_total",
+			Help:      "Number of finished HTTP requests by status class.",
+		}, append(labels, "status_class")),
+		retries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "request_retries_total",
+			Help:      "Number of HTTP request retries.",
+		}, labels),
+		rateLimitWait: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Name:      "rate_limit_wait_seconds",
+			Help:      "Time requests waited for the rate limiter.",
+			Buckets:   prometheus.DefBuckets,
+		}, labels),
+	}
+
+	registerer.MustRegister(c.duration, c.inFlight, c.responses, c.retries, c.rateLimitWait)
+
+	return c
+}
+
+func (c *Collector) RequestStarted(method, host string) {
+	c.inFlight.WithLabelValues(method, host).Inc()
+}
+
+func (c *Collector) RequestFinished(method, host string, statusCode int, duration time.Duration, _ error) {
+	c.inFlight.WithLabelValues(method, host).Dec()
+	c.duration.WithLabelValues(method, host).Observe(duration.Seconds())
+	c.responses.WithLabelValues(method, host, clink.StatusClass(statusCode)).Inc()
+}
+
+func (c *Collector) RequestRetried(method, host string) {
+	c.retries.WithLabelValues(method, host).Inc()
+}
+
+func (c *Collector) RateLimitWaited(method, host string, wait time.Duration) {
+	c.rateLimitWait.WithLabelValues(method, host).Observe(wait.Seconds())
+}
@@ -0,0 +1,96 @@
+package prometheus_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/davesavic/clink"
+	clinkprometheus "github.com/davesavic/clink/metrics/prometheus"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestCollector(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	registry := prometheus.NewRegistry()
+
+	client := clink.NewClient(
+		clink.WithClient(server.Client()),
+		clink.WithMetrics(clinkprometheus.NewCollector(registry)),
+		clink.WithRetries(1, func(_ *http.Request, resp *http.Response, _ error) bool {
+			return resp != nil && resp.StatusCode >= 500
+		}),
+	)
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("failed to make request: %v", err)
+	}
+	_ = resp.Body.Close()
+
+	u, _ := url.Parse(server.URL)
+
+	testCases := []struct {
+		name     string
+		metric   string
+		expected string
+	}{
+		{
+			name:   "counts responses by status class",
+			metric: "clink_requests_total",
+			expected: `
+# HELP clink_requests_total Number of finished HTTP requests by status class.
+# TYPE clink_requests_total counter
+clink_requests_total{host="%s",method="GET",status_class="2xx"} 1
+`,
+		},
+		{
+			name:   "counts retries",
+			metric: "clink_request_retries_total",
+			expected: `
+# HELP clink_request_retries_total Number of HTTP request retries.
+# TYPE clink_request_retries_total counter
+clink_request_retries_total{host="%s",method="GET"} 1
+`,
+		},
+		{
+			name:   "in-flight gauge returns to zero",
+			metric: "clink_requests_in_flight",
+			expected: `
+# HELP clink_requests_in_flight Number of HTTP requests in flight.
+# TYPE clink_requests_in_flight gauge
+clink_requests_in_flight{host="%s",method="GET"} 0
+`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			expected := strings.NewReader(fmt.Sprintf(tc.expected, u.Host))
+			if err := testutil.GatherAndCompare(registry, expected, tc.metric); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+
+	t.Run("observes request duration", func(t *testing.T) {
+		count, err := testutil.GatherAndCount(registry, "clink_request_duration_seconds")
+		if err != nil || count != 1 {
+			t.Errorf("expected one duration series, got: %d (%v)", count, err)
+		}
+	})
+}
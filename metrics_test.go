@@ -0,0 +1,106 @@
+package clink_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/davesavic/clink"
+)
+
+type recordingMetrics struct {
+	mu       sync.Mutex
+	started  int
+	finished []int
+	retried  int
+}
+
+func (m *recordingMetrics) RequestStarted(_, _ string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.started++
+}
+
+func (m *recordingMetrics) RequestFinished(_, _ string, statusCode int, _ time.Duration, _ error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.finished = append(m.finished, statusCode)
+}
+
+func (m *recordingMetrics) RequestRetried(_, _ string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.retried++
+}
+
+func (m *recordingMetrics) RateLimitWaited(_, _ string, _ time.Duration) {}
+
+func TestWithMetrics(t *testing.T) {
+	testCases := []struct {
+		name       string
+		status     int
+		opts       []clink.Option
+		resultFunc func(*recordingMetrics) bool
+	}{
+		{
+			name:   "records started and finished requests",
+			status: http.StatusOK,
+			resultFunc: func(m *recordingMetrics) bool {
+				return m.started == 1 && len(m.finished) == 1 && m.finished[0] == http.StatusOK && m.retried == 0
+			},
+		},
+		{
+			name:   "records retries",
+			status: http.StatusBadGateway,
+			opts: []clink.Option{
+				clink.WithRetries(2, func(_ *http.Request, resp *http.Response, _ error) bool {
+					return resp != nil && resp.StatusCode >= 500
+				}),
+			},
+			resultFunc: func(m *recordingMetrics) bool {
+				return m.started == 1 && m.retried == 2 && m.finished[0] == http.StatusBadGateway
+			},
+		},
+		{
+			name:   "records status of failure responses",
+			status: http.StatusNotFound,
+			opts:   []clink.Option{clink.WithErrorOnStatus(nil)},
+			resultFunc: func(m *recordingMetrics) bool {
+				return len(m.finished) == 1 && m.finished[0] == http.StatusNotFound
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(tc.status)
+			}))
+			defer server.Close()
+
+			metrics := &recordingMetrics{}
+			c := clink.NewClient(append(tc.opts, clink.WithMetrics(metrics))...)
+
+			resp, err := c.Get(server.URL)
+			if err == nil {
+				_ = resp.Body.Close()
+			}
+
+			if !tc.resultFunc(metrics) {
+				t.Errorf("unexpected metrics: %+v", metrics)
+			}
+		})
+	}
+}
+
+func TestStatusClass(t *testing.T) {
+	testCases := map[int]string{0: "error", 200: "2xx", 404: "4xx", 503: "5xx"}
+
+	for status, expected := range testCases {
+		if got := clink.StatusClass(status); got != expected {
+			t.Errorf("expected %s for %d, got: %s", expected, status, got)
+		}
+	}
+}
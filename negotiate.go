@@ -0,0 +1,101 @@
+package clink
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Accept sets the Accept header of the request to the given media ranges, overriding the Accept
+// header of the client. Each range can carry a q-value giving its relative preference, for example:
+//
+//	clink.Accept("application/json", "text/csv;q=0.5")
+//
+// The response can then be decoded according to the content type picked by the server with Negotiate.
+func Accept(mediaRanges ...string) RequestOption {
+	return func(o *requestOptions) {
+		o.accept = append(o.accept[:len(o.accept):len(o.accept)], mediaRanges...)
+	}
+}
+
+// acceptHeaderValue validates the media ranges set with Accept and returns them as an Accept header value.
+func acceptHeaderValue(mediaRanges []string) (string, error) {
+	for _, mediaRange := range mediaRanges {
+		mediaType, params, err := mime.ParseMediaType(mediaRange)
+		if err != nil || !strings.Contains(mediaType, "/") {
+			return "", fmt.Errorf("invalid accepted media range %q", mediaRange)
+		}
+
+		if q, ok := params["q"]; ok {
+			if value, err := strconv.ParseFloat(q, 64); err != nil || value < 0 || value > 1 {
+				return "", fmt.Errorf("invalid q-value in accepted media range %q", mediaRange)
+			}
+		}
+	}
+
+	return strings.Join(mediaRanges, ", "), nil
+}
+
+// Negotiate decodes the response body with the handler registered for the Content-Type of the
+// response, and closes the body. Handlers are keyed by media type; a handler registered for a
+// media type also handles its structured syntax suffix (application/json handles
+// application/problem+json), and handlers can be registered for a whole type (text/*) or for any
+// content (*/*). It returns an error wrapping ErrUnsupportedContentType if no handler matches.
+//
+//	err := clink.Negotiate(resp, map[string]func(io.Reader) error{
+//		"application/json": func(r io.Reader) error { return json.NewDecoder(r).Decode(&items) },
+//		"text/csv": func(r io.Reader) (err error) { rows, err = csv.NewReader(r).ReadAll(); return err },
+//	})
+func Negotiate(response *http.Response, handlers map[string]func(body io.Reader) error) error {
+	if response == nil {
+		return fmt.Errorf("response is nil")
+	}
+
+	if response.Body == nil {
+		return fmt.Errorf("response body is nil")
+	}
+
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(response.Body)
+
+	contentType := response.Header.Get("Content-Type")
+
+	handler := negotiatedHandler(handlers, contentType)
+	if handler == nil {
+		return fmt.Errorf("%w: %s", ErrUnsupportedContentType, contentType)
+	}
+
+	if err := handler(response.Body); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return nil
+}
+
+// negotiatedHandler returns the handler for the content type, trying the media type, the type of
+// its structured syntax suffix, the wildcard for its type and the wildcard for any type in turn.
+func negotiatedHandler(handlers map[string]func(io.Reader) error, contentType string) func(io.Reader) error {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = ""
+	}
+
+	typ, subtype, _ := strings.Cut(mediaType, "/")
+	candidates := []string{mediaType}
+	if _, suffix, ok := strings.Cut(subtype, "+"); ok {
+		candidates = append(candidates, typ+"/"+suffix)
+	}
+	candidates = append(candidates, typ+"/*", "*/*")
+
+	for _, candidate := range candidates {
+		if handler, ok := handlers[candidate]; ok && candidate != "/*" {
+			return handler
+		}
+	}
+
+	return nil
+}
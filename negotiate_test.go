@@ -0,0 +1,199 @@
+package clink_test
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/davesavic/clink"
+)
+
+func TestAccept(t *testing.T) {
+	accepts := make(chan string, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		accepts <- r.Header.Get("Accept")
+	}))
+	defer server.Close()
+
+	testCases := []struct {
+		name        string
+		client      *clink.Client
+		mediaRanges []string
+		expected    string
+		invalid     bool
+	}{
+		{
+			name:        "media ranges with q-values",
+			client:      clink.NewClient(),
+			mediaRanges: []string{"application/json", "text/csv;q=0.5"},
+			expected:    "application/json, text/csv;q=0.5",
+		},
+		{
+			name:        "overrides the client header",
+			client:      clink.NewClient(clink.WithHeader("Accept", "application/xml")),
+			mediaRanges: []string{"text/csv"},
+			expected:    "text/csv",
+		},
+		{
+			name:     "client header without ranges",
+			client:   clink.NewClient(clink.WithHeader("Accept", "application/xml")),
+			expected: "application/xml",
+		},
+		{
+			name:        "invalid media range",
+			client:      clink.NewClient(),
+			mediaRanges: []string{"json"},
+			invalid:     true,
+		},
+		{
+			name:        "invalid q-value",
+			client:      clink.NewClient(),
+			mediaRanges: []string{"text/csv;q=2"},
+			invalid:     true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, server.URL, nil)
+			if len(tc.mediaRanges) > 0 {
+				req = clink.ConfigureRequest(req, clink.Accept(tc.mediaRanges...))
+			}
+
+			resp, err := tc.client.Do(req)
+			if tc.invalid {
+				if err == nil {
+					t.Errorf("expected an error for an invalid media range")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			_ = resp.Body.Close()
+
+			if accept := <-accepts; accept != tc.expected {
+				t.Errorf("expected accept header %q, got: %q", tc.expected, accept)
+			}
+		})
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.Header.Get("Accept"), "text/csv") {
+			w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+			_, _ = w.Write([]byte("name\nann\nbob\n"))
+			return
+		}
+
+		w.Header().Set("Content-Type", r.URL.Query().Get("type"))
+		_, _ = w.Write([]byte(`[{"name":"ann"},{"name":"bob"}]`))
+	}))
+	defer server.Close()
+
+	testCases := []struct {
+		name       string
+		path       string
+		accept     []string
+		handlers   func(*[]string) map[string]func(io.Reader) error
+		resultFunc func([]string, error) bool
+	}{
+		{
+			name:   "json",
+			path:   "?type=application/json",
+			accept: []string{"application/json", "text/csv;q=0.5"},
+			resultFunc: func(names []string, err error) bool {
+				return err == nil && len(names) == 2 && names[1] == "bob"
+			},
+		},
+		{
+			name:   "csv",
+			accept: []string{"text/csv", "application/json;q=0.5"},
+			resultFunc: func(names []string, err error) bool {
+				return err == nil && len(names) == 2 && names[0] == "ann"
+			},
+		},
+		{
+			name: "structured syntax suffix",
+			path: "?type=application/vnd.users%2Bjson",
+			resultFunc: func(names []string, err error) bool {
+				return err == nil && len(names) == 2
+			},
+		},
+		{
+			name: "wildcard",
+			path: "?type=text/plain",
+			handlers: func(names *[]string) map[string]func(io.Reader) error {
+				return map[string]func(io.Reader) error{
+					"text/*": func(r io.Reader) error {
+						*names = append(*names, "text")
+						return nil
+					},
+				}
+			},
+			resultFunc: func(names []string, err error) bool {
+				return err == nil && len(names) == 1 && names[0] == "text"
+			},
+		},
+		{
+			name: "unsupported content type",
+			path: "?type=application/xml",
+			resultFunc: func(names []string, err error) bool {
+				return errors.Is(err, clink.ErrUnsupportedContentType)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodGet, server.URL+tc.path, nil)
+			if len(tc.accept) > 0 {
+				req = clink.ConfigureRequest(req, clink.Accept(tc.accept...))
+			}
+
+			resp, err := clink.NewClient().Do(req)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var names []string
+			handlers := map[string]func(io.Reader) error{
+				"application/json": func(r io.Reader) error {
+					var users []struct{ Name string }
+					if err := json.NewDecoder(r).Decode(&users); err != nil {
+						return err
+					}
+					for _, user := range users {
+						names = append(names, user.Name)
+					}
+					return nil
+				},
+				"text/csv": func(r io.Reader) error {
+					records, err := csv.NewReader(r).ReadAll()
+					if err != nil {
+						return err
+					}
+					for _, record := range records[1:] {
+						names = append(names, record[0])
+					}
+					return nil
+				},
+			}
+			if tc.handlers != nil {
+				handlers = tc.handlers(&names)
+			}
+
+			err = clink.Negotiate(resp, handlers)
+			if !tc.resultFunc(names, err) {
+				t.Errorf("unexpected result: %v, %v", names, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package clink
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// The notify functions report the progress of a request to the logger, metrics and event handlers.
+
+func (c *Client) notifyStarted(req *http.Request) {
+	c.stats.inFlight.Add(1)
+	c.log(req, c.LogLevels.Request, "request started")
+	c.metricsStarted(req)
+	c.events.emit(RequestStarted{Request: req})
+}
+
+func (c *Client) notifyRateLimited(req *http.Request, wait time.Duration) {
+	if wait >= time.Millisecond {
+		c.log(req, c.LogLevels.RateLimit, "request rate limited", slog.Duration("wait", wait))
+	}
+	c.metricsRateLimitWaited(req, wait)
+	c.events.emit(RateLimited{Request: req, Wait: wait})
+}
+
+func (c *Client) notifyAttempt(req *http.Request, attempt int, sent int, resp *http.Response, err error, duration time.Duration) {
+	c.stats.bytesSent.Add(int64(sent))
+	c.events.emit(AttemptFinished{Request: req, Attempt: attempt, Response: resp, Err: err, Duration: duration})
+}
+
+func (c *Client) notifyRetry(req *http.Request, attempt int, resp *http.Response, err error, delay time.Duration) {
+	c.stats.retries.Add(1)
+	c.logRetry(req, resp, err, attempt, delay)
+	c.metricsRetried(req)
+	c.events.emit(RetryScheduled{Request: req, Attempt: attempt, Delay: delay, Response: resp, Err: err})
+}
+
+func (c *Client) notifyCircuitOpened(req *http.Request, key string, timeout time.Duration) {
+	c.log(req, c.LogLevels.Error, "circuit breaker opened", slog.String("circuit", key), slog.Duration("timeout", timeout))
+	c.events.emit(CircuitOpened{Request: req, Key: key, Timeout: timeout})
+}
+
+func (c *Client) notifyFinished(req *http.Request, resp *http.Response, attempts int, duration time.Duration, err error) {
+	c.stats.inFlight.Add(-1)
+	c.stats.requests.Add(1)
+	c.stats.latency.Add(int64(duration))
+	c.metricsFinished(req, resp, duration, err)
+
+	if err != nil {
+		c.stats.errors.Add(1)
+		c.log(req, c.LogLevels.Error, "request failed",
+			slog.Duration("duration", duration),
+			slog.Int("attempts", attempts),
+			slog.Any("error", err),
+		)
+	} else {
+		attrs := []slog.Attr{
+			slog.Int("status", resp.StatusCode),
+			slog.Duration("duration", duration),
+			slog.Int("attempts", attempts),
+		}
+		if attempts == 0 {
+			attrs = append(attrs, slog.Bool("cached", true))
+		}
+		c.log(req, c.LogLevels.Response, "request finished", attrs...)
+	}
+
+	c.events.emit(ResponseReceived{Request: req, Response: resp, Attempts: attempts, Duration: duration, Err: err})
+}
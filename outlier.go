@@ -0,0 +1,132 @@
+package clink
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Default settings of OutlierDetection.
+const (
+	DefaultOutlierInterval    = 10 * time.Second
+	DefaultOutlierErrorRate   = 0.5
+	DefaultOutlierMinRequests = 10
+	DefaultBaseEjectionTime   = 30 * time.Second
+	DefaultMaxEjectionTime    = 5 * time.Minute
+)
+
+// OutlierDetection configures the ejection of the endpoints set with WithEndpoints whose requests
+// fail too often. Zero fields are set to their default.
+type OutlierDetection struct {
+	// Interval is the period over which the error rate of an endpoint is computed.
+	// DefaultOutlierInterval is used if it is not positive.
+	Interval time.Duration
+	// ErrorRate is the rate of failed requests, between 0 and 1, above which an endpoint is ejected.
+	// DefaultOutlierErrorRate is used if it is not positive.
+	ErrorRate float64
+	// MinRequests is the number of requests an endpoint must receive during an interval before it
+	// can be ejected. DefaultOutlierMinRequests is used if it is not positive.
+	MinRequests int
+	// MaxLatency is the latency above which a request counts as failed. Latency is ignored if it
+	// is zero.
+	MaxLatency time.Duration
+	// BaseEjectionTime is how long an endpoint is ejected the first time. It doubles every time the
+	// endpoint is ejected again, up to MaxEjectionTime. DefaultBaseEjectionTime is used if it is
+	// not positive.
+	BaseEjectionTime time.Duration
+	// MaxEjectionTime is the maximum time an endpoint is ejected. DefaultMaxEjectionTime is used if
+	// it is not positive.
+	MaxEjectionTime time.Duration
+}
+
+// WithOutlierDetection tracks the error rate and latency of the requests sent to each endpoint set
+// with WithEndpoints, and ejects the endpoints whose requests fail too often (see OutlierDetection).
+// A request fails if the endpoint can't be reached or responds with a 5xx status. Like endpoints
+// failing their health checks, ejected endpoints are only used when every endpoint is unavailable.
+// An endpoint that stays below the error rate for an interval after an ejection gets a shorter
+// ejection time the next time it is ejected.
+func WithOutlierDetection(detection OutlierDetection) Option {
+	return func(c *Client) {
+		if detection.Interval <= 0 {
+			detection.Interval = DefaultOutlierInterval
+		}
+
+		if detection.ErrorRate <= 0 {
+			detection.ErrorRate = DefaultOutlierErrorRate
+		}
+
+		if detection.MinRequests <= 0 {
+			detection.MinRequests = DefaultOutlierMinRequests
+		}
+
+		if detection.BaseEjectionTime <= 0 {
+			detection.BaseEjectionTime = DefaultBaseEjectionTime
+		}
+
+		if detection.MaxEjectionTime <= 0 {
+			detection.MaxEjectionTime = DefaultMaxEjectionTime
+		}
+
+		c.endpointSet().outliers = &detection
+	}
+}
+
+// outlierStats are the request results of an endpoint during the current interval.
+type outlierStats struct {
+	mu           sync.Mutex
+	windowStart  time.Time
+	requests     int
+	failures     int
+	ejections    int
+	ejectedUntil time.Time
+}
+
+func (s *outlierStats) ejected(now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return now.Before(s.ejectedUntil)
+}
+
+// record adds the result of a request to the stats, and ejects the endpoint if its error rate
+// exceeds the threshold.
+func (s *outlierStats) record(d *OutlierDetection, resp *http.Response, err error, latency time.Duration, now time.Time) {
+	failed := err != nil || resp.StatusCode >= http.StatusInternalServerError ||
+		(d.MaxLatency > 0 && latency > d.MaxLatency)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if now.Before(s.ejectedUntil) {
+		return
+	}
+
+	if now.Sub(s.windowStart) >= d.Interval {
+		if s.ejections > 0 && s.requests > 0 && !s.windowStart.IsZero() {
+			s.ejections--
+		}
+		s.windowStart = now
+		s.requests = 0
+		s.failures = 0
+	}
+
+	s.requests++
+	if failed {
+		s.failures++
+	}
+
+	if s.requests < d.MinRequests || float64(s.failures)/float64(s.requests) <= d.ErrorRate {
+		return
+	}
+
+	ejection := d.BaseEjectionTime << min(s.ejections, 30)
+	if ejection <= 0 || ejection > d.MaxEjectionTime {
+		ejection = d.MaxEjectionTime
+	}
+
+	s.ejections++
+	s.ejectedUntil = now.Add(ejection)
+	s.windowStart = time.Time{}
+	s.requests = 0
+	s.failures = 0
+}
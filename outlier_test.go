@@ -0,0 +1,91 @@
+package clink_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/davesavic/clink"
+)
+
+func TestWithOutlierDetection(t *testing.T) {
+	testCases := []struct {
+		name       string
+		handler    http.HandlerFunc
+		detection  clink.OutlierDetection
+		resultFunc func(*testing.T, []string)
+	}{
+		{
+			name: "error rate exceeded",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusInternalServerError)
+				_, _ = w.Write([]byte("primary"))
+			},
+			detection: clink.OutlierDetection{MinRequests: 2},
+			resultFunc: func(t *testing.T, bodies []string) {
+				if bodies[0] != "primary" || bodies[1] != "primary" || bodies[2] != "fallback" {
+					t.Errorf("expected the primary endpoint to be ejected after 2 errors, got: %v", bodies)
+				}
+			},
+		},
+		{
+			name: "slow requests",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				time.Sleep(20 * time.Millisecond)
+				_, _ = w.Write([]byte("primary"))
+			},
+			detection: clink.OutlierDetection{MinRequests: 2, MaxLatency: 5 * time.Millisecond},
+			resultFunc: func(t *testing.T, bodies []string) {
+				if bodies[2] != "fallback" {
+					t.Errorf("expected the slow primary endpoint to be ejected, got: %v", bodies)
+				}
+			},
+		},
+		{
+			name: "error rate not exceeded",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write([]byte("primary"))
+			},
+			detection: clink.OutlierDetection{MinRequests: 2},
+			resultFunc: func(t *testing.T, bodies []string) {
+				for _, body := range bodies {
+					if body != "primary" {
+						t.Errorf("expected the primary endpoint to be used, got: %v", bodies)
+						return
+					}
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			primary := httptest.NewServer(tc.handler)
+			defer primary.Close()
+			fallback := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write([]byte("fallback"))
+			}))
+			defer fallback.Close()
+
+			c := clink.NewClient(
+				clink.WithEndpoints(primary.URL, fallback.URL),
+				clink.WithOutlierDetection(tc.detection),
+			)
+
+			var bodies []string
+			for i := 0; i < 3; i++ {
+				resp, err := c.Get("/")
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				body, _ := io.ReadAll(resp.Body)
+				_ = resp.Body.Close()
+				bodies = append(bodies, string(body))
+			}
+
+			tc.resultFunc(t, bodies)
+		})
+	}
+}
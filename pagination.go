@@ -0,0 +1,125 @@
+package clink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"net/http"
+	"net/url"
+)
+
+// ErrMaxItemsExceeded is returned by CollectAllPages when the paginated endpoint has more items
+// than the configured maximum.
+var ErrMaxItemsExceeded = errors.New("paginated endpoint exceeds the maximum number of items")
+
+// ErrPaginationLoop is returned by Pages when the URL of the next page is the URL of a page already fetched.
+var ErrPaginationLoop = errors.New("paginated endpoint returned a page already fetched")
+
+// Pages returns an iterator over the pages of a paginated API, starting at firstURL. Each page is
+// decoded into a T with the client's codecs, and next returns the URL of the following page, or an
+// empty string after the last page. The Accept header of the requests is set to the content types
+// of the codecs a T can be decoded with, unless it is set with WithHeader. The URL returned by next
+// is resolved against the URL of the current page, so APIs paginating with a cursor in the body
+// can be followed with:
+//
+//	next := func(page UsersPage) string {
+//		if page.NextCursor == "" {
+//			return ""
+//		}
+//		return "?cursor=" + url.QueryEscape(page.NextCursor)
+//	}
+//
+// Responses with a status other than 2xx yield an *HTTPError, and a next page URL already fetched
+// yields ErrPaginationLoop. Iteration stops after the first error.
+func Pages[T any](ctx context.Context, client *Client, firstURL string, next func(T) string) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		accept := acceptHeader(client.config().Codecs, &zero)
+
+		visited := make(map[string]bool)
+		pageURL := firstURL
+		for pageURL != "" {
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, pageURL, nil)
+			if err != nil {
+				yield(zero, fmt.Errorf("failed to create request: %w", err))
+				return
+			}
+			if accept != "" {
+				req.Header.Set("Accept", accept)
+			}
+
+			resp, err := client.Do(req)
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+
+			if !isSuccessStatus(resp) {
+				yield(zero, newHTTPError(resp, client.config().Redactor))
+				return
+			}
+
+			var page T
+			if err := client.Decode(resp, &page); err != nil {
+				yield(zero, err)
+				return
+			}
+
+			if resp.Request != nil && resp.Request.URL != nil {
+				visited[resp.Request.URL.String()] = true
+			}
+			visited[pageURL] = true
+
+			pageURL, err = resolveNextPage(resp.Request, next(page))
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+			if visited[pageURL] {
+				if yield(page, nil) {
+					yield(zero, fmt.Errorf("%w: %s", ErrPaginationLoop, pageURL))
+				}
+				return
+			}
+
+			if !yield(page, nil) {
+				return
+			}
+		}
+	}
+}
+
+// resolveNextPage resolves the URL of the next page against the URL of the request of the current one.
+func resolveNextPage(req *http.Request, next string) (string, error) {
+	if next == "" || req == nil || req.URL == nil {
+		return next, nil
+	}
+
+	u, err := url.Parse(next)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse next page url: %w", err)
+	}
+
+	return req.URL.ResolveReference(u).String(), nil
+}
+
+// CollectAllPages walks every page of a paginated endpoint like Pages and returns the concatenated
+// items extracted from each page by items. If maxItems is positive and the endpoint has more items,
+// the first maxItems items are returned with ErrMaxItemsExceeded. If Pages fails, for example with
+// an *HTTPError or ErrPaginationLoop, the items collected so far are returned with its error.
+func CollectAllPages[P, T any](ctx context.Context, client *Client, firstURL string, next func(P) string, items func(P) []T, maxItems int) ([]T, error) {
+	var all []T
+	for page, err := range Pages(ctx, client, firstURL, next) {
+		if err != nil {
+			return all, err
+		}
+
+		all = append(all, items(page)...)
+		if maxItems > 0 && len(all) > maxItems {
+			return all[:maxItems], ErrMaxItemsExceeded
+		}
+	}
+
+	return all, nil
+}
@@ -0,0 +1,340 @@
+package clink_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/davesavic/clink"
+)
+
+type usersPage struct {
+	Users      []string `json:"users"`
+	NextCursor string   `json:"next_cursor"`
+}
+
+func TestPages(t *testing.T) {
+	pages := map[string]usersPage{
+		"":  {Users: []string{"ann", "bob"}, NextCursor: "b"},
+		"b": {Users: []string{"cat"}, NextCursor: "c"},
+		"c": {Users: []string{"dan"}},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, ok := pages[r.URL.Query().Get("cursor")]
+		if !ok || r.URL.Path != "/users" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(page)
+	}))
+	defer server.Close()
+
+	next := func(page usersPage) string {
+		if page.NextCursor == "" {
+			return ""
+		}
+		return "?cursor=" + url.QueryEscape(page.NextCursor)
+	}
+
+	testCases := []struct {
+		name       string
+		client     *clink.Client
+		firstURL   string
+		limit      int
+		resultFunc func(*testing.T, []string, error)
+	}{
+		{
+			name:     "all pages",
+			client:   clink.NewClient(),
+			firstURL: server.URL + "/users",
+			resultFunc: func(t *testing.T, users []string, err error) {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+
+				if len(users) != 4 || users[0] != "ann" || users[3] != "dan" {
+					t.Errorf("expected the users of every page, got: %v", users)
+				}
+			},
+		},
+		{
+			name:     "relative first url",
+			client:   clink.NewClient(clink.WithBaseURL(server.URL)),
+			firstURL: "/users",
+			resultFunc: func(t *testing.T, users []string, err error) {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+
+				if len(users) != 4 {
+					t.Errorf("expected the users of every page, got: %v", users)
+				}
+			},
+		},
+		{
+			name:     "early stop",
+			client:   clink.NewClient(),
+			firstURL: server.URL + "/users",
+			limit:    1,
+			resultFunc: func(t *testing.T, users []string, err error) {
+				if len(users) != 2 {
+					t.Errorf("expected the users of the first page only, got: %v", users)
+				}
+			},
+		},
+		{
+			name:     "error status",
+			client:   clink.NewClient(),
+			firstURL: server.URL + "/missing",
+			resultFunc: func(t *testing.T, users []string, err error) {
+				var httpErr *clink.HTTPError
+				if !errors.As(err, &httpErr) || httpErr.StatusCode != http.StatusNotFound {
+					t.Errorf("expected a 404 HTTPError, got: %v", err)
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var users []string
+			var err error
+			var count int
+			for page, pageErr := range clink.Pages(context.Background(), tc.client, tc.firstURL, next) {
+				if pageErr != nil {
+					err = pageErr
+					break
+				}
+
+				users = append(users, page.Users...)
+				count++
+				if count == tc.limit {
+					break
+				}
+			}
+
+			tc.resultFunc(t, users, err)
+		})
+	}
+}
+
+func TestPagesLoop(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(usersPage{Users: []string{"ann"}, NextCursor: "a"})
+	}))
+	defer server.Close()
+
+	next := func(page usersPage) string {
+		return "?cursor=" + page.NextCursor
+	}
+
+	var users []string
+	var err error
+	for page, pageErr := range clink.Pages(context.Background(), clink.NewClient(), server.URL+"/users", next) {
+		if pageErr != nil {
+			err = pageErr
+			break
+		}
+		users = append(users, page.Users...)
+	}
+
+	if !errors.Is(err, clink.ErrPaginationLoop) {
+		t.Errorf("expected ErrPaginationLoop, got: %v", err)
+	}
+
+	if requests != 2 || len(users) != 2 {
+		t.Errorf("expected 2 pages before the loop is detected, got %d requests and users %v", requests, users)
+	}
+}
+
+func TestCollectAllPages(t *testing.T) {
+	pages := map[string]usersPage{
+		"":  {Users: []string{"ann", "bob"}, NextCursor: "b"},
+		"b": {Users: []string{"cat", "dan"}},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(pages[r.URL.Query().Get("cursor")])
+	}))
+	defer server.Close()
+
+	next := func(page usersPage) string {
+		if page.NextCursor == "" {
+			return ""
+		}
+		return "?cursor=" + page.NextCursor
+	}
+	items := func(page usersPage) []string {
+		return page.Users
+	}
+
+	testCases := []struct {
+		name       string
+		maxItems   int
+		resultFunc func(*testing.T, []string, error)
+	}{
+		{
+			name: "all items",
+			resultFunc: func(t *testing.T, users []string, err error) {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+
+				if strings.Join(users, ",") != "ann,bob,cat,dan" {
+					t.Errorf("expected the items of every page, got: %v", users)
+				}
+			},
+		},
+		{
+			name:     "items at the cap",
+			maxItems: 4,
+			resultFunc: func(t *testing.T, users []string, err error) {
+				if err != nil || len(users) != 4 {
+					t.Errorf("expected all 4 items without error, got: %v, %v", users, err)
+				}
+			},
+		},
+		{
+			name:     "items over the cap",
+			maxItems: 3,
+			resultFunc: func(t *testing.T, users []string, err error) {
+				if !errors.Is(err, clink.ErrMaxItemsExceeded) {
+					t.Errorf("expected ErrMaxItemsExceeded, got: %v", err)
+				}
+
+				if strings.Join(users, ",") != "ann,bob,cat" {
+					t.Errorf("expected the first 3 items, got: %v", users)
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			users, err := clink.CollectAllPages(context.Background(), clink.NewClient(), server.URL, next, items, tc.maxItems)
+			tc.resultFunc(t, users, err)
+		})
+	}
+}
+
+func TestCollectAllPages_ErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("cursor") != "" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(usersPage{Users: []string{"ann"}, NextCursor: "b"})
+	}))
+	defer server.Close()
+
+	next := func(page usersPage) string {
+		if page.NextCursor == "" {
+			return ""
+		}
+		return "?cursor=" + page.NextCursor
+	}
+	items := func(page usersPage) []string {
+		return page.Users
+	}
+
+	users, err := clink.CollectAllPages(context.Background(), clink.NewClient(), server.URL, next, items, 0)
+
+	var httpErr *clink.HTTPError
+	if !errors.As(err, &httpErr) || httpErr.StatusCode != http.StatusInternalServerError {
+		t.Errorf("expected a 500 HTTPError, got: %v", err)
+	}
+
+	if strings.Join(users, ",") != "ann" {
+		t.Errorf("expected the items of the first page, got: %v", users)
+	}
+}
+
+func TestPagesAcceptHeader(t *testing.T) {
+	accepts := make(chan string, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		accepts <- r.Header.Get("Accept")
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write([]byte("page"))
+	}))
+	defer server.Close()
+
+	testCases := []struct {
+		name     string
+		client   *clink.Client
+		pages    func(*clink.Client) error
+		expected string
+	}{
+		{
+			name:   "struct page",
+			client: clink.NewClient(),
+			pages: func(c *clink.Client) error {
+				for _, err := range clink.Pages(context.Background(), c, server.URL, func(usersPage) string { return "" }) {
+					return err
+				}
+				return nil
+			},
+			expected: "application/json, application/xml;q=0.9, application/yaml;q=0.9",
+		},
+		{
+			name:   "string page",
+			client: clink.NewClient(),
+			pages: func(c *clink.Client) error {
+				for _, err := range clink.Pages(context.Background(), c, server.URL, func(string) string { return "" }) {
+					return err
+				}
+				return nil
+			},
+			expected: "text/plain",
+		},
+		{
+			name:   "form page",
+			client: clink.NewClient(),
+			pages: func(c *clink.Client) error {
+				_, err := clink.GetAll[url.Values](context.Background(), c, []string{server.URL}, 1)
+				return err
+			},
+			expected: "application/x-www-form-urlencoded",
+		},
+		{
+			name:   "custom codec",
+			client: clink.NewClient(clink.WithCodec(upperCodec{})),
+			pages: func(c *clink.Client) error {
+				_, err := clink.GetAll[usersPage](context.Background(), c, []string{server.URL}, 1)
+				return err
+			},
+			expected: "application/json, application/x-upper;q=0.9, application/xml;q=0.9, application/yaml;q=0.9",
+		},
+		{
+			name:   "overridden by the client",
+			client: clink.NewClient(clink.WithHeader("Accept", "application/vnd.api+json")),
+			pages: func(c *clink.Client) error {
+				_, err := clink.GetAll[usersPage](context.Background(), c, []string{server.URL}, 1)
+				return err
+			},
+			expected: "application/vnd.api+json",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_ = tc.pages(tc.client)
+
+			if accept := <-accepts; accept != tc.expected {
+				t.Errorf("expected accept header %q, got: %q", tc.expected, accept)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package clink
+
+import "time"
+
+// ForJSONAPI creates a client for JSON APIs with the given options applied after the preset:
+//   - requests time out after 30 seconds;
+//   - requests that can't be sent or get a 429, 502, 503 or 504 response are retried 3 times;
+//   - unsafe requests carry an idempotency key, so that they can be retried safely;
+//   - responses with a status of 400 or above are returned as an *HTTPError;
+//   - the JSON media type is accepted, and response bodies are limited to 10 MiB.
+func ForJSONAPI(opts ...Option) *Client {
+	return NewClient(append([]Option{
+		WithTimeout(30 * time.Second),
+		WithRetries(3, retryStatuses(DefaultConfigRetryStatuses)),
+		WithIdempotencyKey(),
+		WithErrorOnStatus(nil),
+		WithHeader("Accept", "application/json"),
+		WithMaxResponseBytes(10 << 20),
+	}, opts...)...)
+}
+
+// ForScraping creates a client for fetching pages of untrusted websites with the given options
+// applied after the preset:
+//   - requests time out after 60 seconds and are retried twice like with ForJSONAPI;
+//   - at most 60 requests are sent per minute, and at most 2 at a time to each host;
+//   - cookies are kept in memory and up to 10 redirects are followed;
+//   - connections to private, loopback and link-local addresses are refused (see WithSSRFProtection);
+//   - HTML is accepted, and response bodies are limited to 50 MiB.
+//
+// Since SSRF protection needs the client's own transport, WithClient and WithTransport can't be
+// given: they are reported as a configuration error.
+func ForScraping(opts ...Option) *Client {
+	return NewClient(append([]Option{
+		WithTimeout(60 * time.Second),
+		WithRetries(2, retryStatuses(DefaultConfigRetryStatuses)),
+		WithRateLimit(60),
+		WithMaxConcurrencyPerHost(2),
+		WithCookieJar(nil),
+		WithRedirectPolicy(10, true),
+		WithSSRFProtection(),
+		WithUserAgent("Mozilla/5.0 (compatible; clink)"),
+		WithHeader("Accept", "text/html,application/xhtml+xml;q=0.9,*/*;q=0.8"),
+		WithMaxResponseBytes(50 << 20),
+	}, opts...)...)
+}
+
+// ForInternalService creates a client for calls between services with the given options applied
+// after the preset:
+//   - requests time out after 5 seconds and are retried twice like with ForJSONAPI;
+//   - requests are stamped with a request ID (see WithRequestID);
+//   - a circuit breaker with the default settings stops calling failing hosts (see WithCircuitBreaker);
+//   - host names are looked up at most every 30 seconds (see WithDNSCache).
+func ForInternalService(opts ...Option) *Client {
+	return NewClient(append([]Option{
+		WithTimeout(5 * time.Second),
+		WithRetries(2, retryStatuses(DefaultConfigRetryStatuses)),
+		WithRequestID(nil, ""),
+		WithCircuitBreaker(CircuitBreaker{}),
+		WithDNSCache(30 * time.Second),
+	}, opts...)...)
+}
@@ -0,0 +1,80 @@
+package clink_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/davesavic/clink"
+)
+
+func TestPresets(t *testing.T) {
+	testCases := []struct {
+		name       string
+		client     func(...clink.Option) *clink.Client
+		status     int
+		resultFunc func(*http.Request, int32, error) bool
+	}{
+		{
+			name:   "json api",
+			client: clink.ForJSONAPI,
+			status: http.StatusNotFound,
+			resultFunc: func(req *http.Request, hits int32, err error) bool {
+				var httpErr *clink.HTTPError
+				return errors.As(err, &httpErr) && hits == 1 &&
+					req.Header.Get("Accept") == "application/json" && req.Header.Get(clink.DefaultIdempotencyKeyHeader) != ""
+			},
+		},
+		{
+			name:   "json api retry",
+			client: clink.ForJSONAPI,
+			status: http.StatusServiceUnavailable,
+			resultFunc: func(_ *http.Request, hits int32, err error) bool {
+				return err != nil && hits == 4
+			},
+		},
+		{
+			name:   "scraping",
+			client: clink.ForScraping,
+			status: http.StatusOK,
+			resultFunc: func(_ *http.Request, hits int32, err error) bool {
+				return errors.Is(err, clink.ErrBlockedAddress) && hits == 0
+			},
+		},
+		{
+			name:   "internal service",
+			client: clink.ForInternalService,
+			status: http.StatusOK,
+			resultFunc: func(req *http.Request, hits int32, err error) bool {
+				return err == nil && hits == 1 && req.Header.Get(clink.DefaultRequestIDHeader) != ""
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var hits atomic.Int32
+			var received *http.Request
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				hits.Add(1)
+				received = r
+				w.WriteHeader(tc.status)
+			}))
+			defer server.Close()
+
+			c := tc.client(clink.WithTestMode())
+
+			req, _ := http.NewRequest(http.MethodPost, server.URL, nil)
+			resp, err := c.Do(req)
+			if err == nil {
+				_ = resp.Body.Close()
+			}
+
+			if !tc.resultFunc(received, hits.Load(), err) {
+				t.Errorf("unexpected result: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package clink
+
+import (
+	"io"
+	"time"
+)
+
+// Progress describes the progress of sending a request body.
+type Progress struct {
+	// Attempt is the attempt sending the body, starting at 1. Sent restarts from zero when the body
+	// is sent again for a retry.
+	Attempt int
+	// Sent is the number of body bytes sent in the attempt.
+	Sent int64
+	// Total is the size of the body, or -1 if it is unknown (see StreamBody).
+	Total int64
+	// Rate is the average number of bytes sent per second in the attempt.
+	Rate float64
+}
+
+// ProgressReporter receives the progress of request bodies as they are sent.
+type ProgressReporter interface {
+	Progress(Progress)
+}
+
+// ProgressFunc adapts a function to a ProgressReporter.
+type ProgressFunc func(Progress)
+
+func (f ProgressFunc) Progress(p Progress) {
+	f(p)
+}
+
+// UploadProgress reports the progress of sending the request body to the reporter, which is
+// called from the goroutine sending the body each time a part of it is sent.
+func UploadProgress(reporter ProgressReporter) RequestOption {
+	return func(o *requestOptions) {
+		o.progress = reporter
+	}
+}
+
+// progressBody reports the bytes read from a request body for an attempt.
+type progressBody struct {
+	io.ReadCloser
+	reporter ProgressReporter
+	progress Progress
+	start    time.Time
+}
+
+func newProgressBody(body io.ReadCloser, reporter ProgressReporter, total int64, attempt int) *progressBody {
+	return &progressBody{
+		ReadCloser: body,
+		reporter:   reporter,
+		progress:   Progress{Attempt: attempt, Total: total},
+		start:      time.Now(),
+	}
+}
+
+func (b *progressBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if n > 0 {
+		b.progress.Sent += int64(n)
+		if elapsed := time.Since(b.start).Seconds(); elapsed > 0 {
+			b.progress.Rate = float64(b.progress.Sent) / elapsed
+		}
+		b.reporter.Progress(b.progress)
+	}
+
+	return n, err
+}
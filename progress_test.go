@@ -0,0 +1,98 @@
+package clink_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/davesavic/clink"
+)
+
+func TestUploadProgress(t *testing.T) {
+	content := strings.Repeat("x", 100_000)
+
+	testCases := []struct {
+		name       string
+		configure  func(*http.Request) *http.Request
+		resultFunc func(*testing.T, []clink.Progress)
+	}{
+		{
+			name: "buffered body re-sent on retry",
+			configure: func(req *http.Request) *http.Request {
+				return req
+			},
+			resultFunc: func(t *testing.T, reports []clink.Progress) {
+				last := map[int]clink.Progress{}
+				for _, p := range reports {
+					if p.Sent < last[p.Attempt].Sent {
+						t.Errorf("expected the bytes sent to increase within an attempt, got: %+v", reports)
+					}
+					last[p.Attempt] = p
+				}
+
+				for attempt := 1; attempt <= 2; attempt++ {
+					p := last[attempt]
+					if p.Sent != int64(len(content)) || p.Total != int64(len(content)) {
+						t.Errorf("expected attempt %d to report the whole body, got: %+v", attempt, p)
+					}
+
+					if p.Rate <= 0 {
+						t.Errorf("expected a positive rate, got: %+v", p)
+					}
+				}
+			},
+		},
+		{
+			name: "streamed body",
+			configure: func(req *http.Request) *http.Request {
+				return clink.ConfigureRequest(req, clink.StreamBody(clink.BodySourceFunc(func() (io.ReadCloser, error) {
+					return io.NopCloser(strings.NewReader(content)), nil
+				})))
+			},
+			resultFunc: func(t *testing.T, reports []clink.Progress) {
+				last := reports[len(reports)-1]
+				if last.Attempt != 2 || last.Sent != int64(len(content)) || last.Total != -1 {
+					t.Errorf("expected the second attempt to report the whole body of unknown size, got: %+v", last)
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var calls atomic.Int64
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, _ = io.Copy(io.Discard, r.Body)
+				if calls.Add(1) == 1 {
+					w.WriteHeader(http.StatusServiceUnavailable)
+				}
+			}))
+			defer server.Close()
+
+			c := clink.NewClient(clink.WithRetries(1, func(_ *http.Request, resp *http.Response, err error) bool {
+				return err == nil && resp.StatusCode == http.StatusServiceUnavailable
+			}))
+
+			var reports []clink.Progress
+			req, _ := http.NewRequest(http.MethodPost, server.URL, strings.NewReader(content))
+			req = clink.ConfigureRequest(tc.configure(req), clink.UploadProgress(clink.ProgressFunc(func(p clink.Progress) {
+				reports = append(reports, p)
+			})))
+
+			resp, err := c.Do(req)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			_ = resp.Body.Close()
+
+			if len(reports) == 0 {
+				t.Fatal("expected progress reports")
+			}
+
+			tc.resultFunc(t, reports)
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package clink_test
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/davesavic/clink"
+)
+
+func TestWithProxy(t *testing.T) {
+	var proxied, auth string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = r.URL.String()
+		auth = r.Header.Get("Proxy-Authorization")
+	}))
+	defer proxy.Close()
+
+	proxyURL := strings.Replace(proxy.URL, "http://", "http://user:pass@", 1)
+	c := clink.NewClient(clink.WithProxy(proxyURL))
+
+	resp, err := c.Get("http://example.invalid/path")
+	if err != nil {
+		t.Fatalf("failed to make request: %v", err)
+	}
+	_ = resp.Body.Close()
+
+	if proxied != "http://example.invalid/path" {
+		t.Errorf("expected request to go through proxy, got: %q", proxied)
+	}
+
+	if expected := "Basic " + base64.StdEncoding.EncodeToString([]byte("user:pass")); auth != expected {
+		t.Errorf("expected proxy auth %q, got: %q", expected, auth)
+	}
+}
+
+func TestWithProxy_InvalidConfig(t *testing.T) {
+	testCases := []struct {
+		name string
+		opts []clink.Option
+	}{
+		{
+			name: "invalid url",
+			opts: []clink.Option{clink.WithProxy("http://[::1")},
+		},
+		{
+			name: "unsupported scheme",
+			opts: []clink.Option{clink.WithProxy("ftp://proxy:21")},
+		},
+		{
+			name: "missing host",
+			opts: []clink.Option{clink.WithProxy("http://")},
+		},
+		{
+			name: "custom client",
+			opts: []clink.Option{clink.WithClient(&http.Client{}), clink.WithProxy("http://proxy:8080")},
+		},
+		{
+			name: "environment proxy on custom client",
+			opts: []clink.Option{clink.WithClient(&http.Client{}), clink.WithProxyFromEnvironment()},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := clink.NewClient(tc.opts...).Get("http://example.invalid")
+			if !errors.Is(err, clink.ErrInvalidOption) {
+				t.Errorf("expected invalid option error, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestWithProxyPool(t *testing.T) {
+	newProxy := func(name string, hits *[]string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+			*hits = append(*hits, name)
+		}))
+	}
+
+	testCases := []struct {
+		name       string
+		requests   int
+		deadProxy  bool
+		resultFunc func(hits []string, errs int) bool
+	}{
+		{
+			name:     "rotates round robin",
+			requests: 4,
+			resultFunc: func(hits []string, errs int) bool {
+				return strings.Join(hits, ",") == "a,b,a,b" && errs == 0
+			},
+		},
+		{
+			name:      "ejects failing proxy",
+			requests:  8,
+			deadProxy: true,
+			resultFunc: func(hits []string, errs int) bool {
+				// The dead proxy fails on requests 1, 3 and 5, then only the healthy one is used.
+				return errs == 3 && len(hits) == 5
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var hits []string
+			a := newProxy("a", &hits)
+			defer a.Close()
+			b := newProxy("b", &hits)
+			defer b.Close()
+
+			urls := []string{a.URL, b.URL}
+			if tc.deadProxy {
+				b.Close()
+				urls = []string{b.URL, a.URL}
+			}
+
+			c := clink.NewClient(clink.WithProxyPool(urls, clink.ProxyRoundRobin))
+
+			var errs int
+			for i := 0; i < tc.requests; i++ {
+				resp, err := c.Get("http://example.invalid")
+				if err != nil {
+					errs++
+					continue
+				}
+				_ = resp.Body.Close()
+			}
+
+			if !tc.resultFunc(hits, errs) {
+				t.Errorf("unexpected result: hits %v, errors %d", hits, errs)
+			}
+		})
+	}
+}
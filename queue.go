@@ -0,0 +1,160 @@
+package clink
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+)
+
+// Default settings of the worker pool used by Client.Queue, unless set with WithWorkerPool.
+const (
+	DefaultQueueWorkers = 4
+	DefaultQueueSize    = 100
+)
+
+var (
+	// ErrQueueFull is returned by Client.Queue when the queue of the worker pool is full.
+	ErrQueueFull = errors.New("request queue is full")
+	// ErrQueueClosed is returned by Client.Queue after Client.Shutdown has been called.
+	ErrQueueClosed = errors.New("request queue is closed")
+)
+
+// WithWorkerPool sets the number of workers sending the requests of Client.Queue and the number
+// of requests that can wait in the queue. DefaultQueueWorkers are used if workers is 0.
+func WithWorkerPool(workers, queueSize int) Option {
+	return func(c *Client) {
+		if workers < 0 {
+			c.addConfigError("WithWorkerPool: workers must not be negative")
+			return
+		}
+
+		if queueSize <= 0 {
+			c.addConfigError("WithWorkerPool: queue size must be positive")
+			return
+		}
+
+		c.queue = newWorkQueue(workers, queueSize)
+	}
+}
+
+// Queue enqueues the request to be sent by the client's worker pool, honoring rate limits and
+// retries like Do. The callback is called from a worker with the result and must close the body
+// of the response. Queue doesn't block: it returns ErrQueueFull if the queue is full, and
+// ErrQueueClosed after Shutdown.
+func (c *Client) Queue(req *http.Request, callback func(*http.Response, error)) error {
+	return c.queue.push(c, queuedRequest{req: req, callback: callback})
+}
+
+// Shutdown stops accepting queued requests and waits until the requests already queued have been
+// sent and their callbacks have returned, or until ctx is done. It also stops the health checks of
+// WithHealthCheck, the background discovery of WithDiscovery and the delivery queues created with
+// NewDeliveryQueue.
+func (c *Client) Shutdown(ctx context.Context) error {
+	if c.health != nil {
+		c.health.close()
+	}
+
+	if c.endpoints != nil && c.endpoints.discovery != nil {
+		c.endpoints.discovery.close()
+	}
+
+	return errors.Join(c.queue.shutdown(ctx), c.deliveries.close(ctx))
+}
+
+// Close shuts down the client like Shutdown, waiting for the queued requests without a deadline,
+// then closes its idle connections. The client can still send requests with Do afterwards, but not
+// queue them.
+func (c *Client) Close() error {
+	err := c.Shutdown(context.Background())
+	c.CloseIdleConnections()
+
+	return err
+}
+
+// CloseIdleConnections closes the connections of the client that are not in use.
+func (c *Client) CloseIdleConnections() {
+	c.HttpClient.CloseIdleConnections()
+}
+
+type queuedRequest struct {
+	req      *http.Request
+	callback func(*http.Response, error)
+}
+
+// workQueue is a queue of requests sent by a pool of workers started on first use.
+type workQueue struct {
+	workers int
+	start   sync.Once
+	wg      sync.WaitGroup
+
+	mu     sync.RWMutex
+	closed bool
+	items  chan queuedRequest
+}
+
+func newWorkQueue(workers, size int) *workQueue {
+	if workers <= 0 {
+		workers = DefaultQueueWorkers
+	}
+
+	return &workQueue{workers: workers, items: make(chan queuedRequest, size)}
+}
+
+func (q *workQueue) push(c *Client, item queuedRequest) error {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	if q.closed {
+		return ErrQueueClosed
+	}
+
+	q.start.Do(func() {
+		q.wg.Add(q.workers)
+		for i := 0; i < q.workers; i++ {
+			go q.work(c)
+		}
+	})
+
+	select {
+	case q.items <- item:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+func (q *workQueue) work(c *Client) {
+	defer q.wg.Done()
+
+	for item := range q.items {
+		resp, err := c.Do(item.req)
+		if item.callback != nil {
+			item.callback(resp, err)
+		} else if resp != nil {
+			_ = DrainAndClose(resp)
+		}
+	}
+}
+
+func (q *workQueue) shutdown(ctx context.Context) error {
+	q.mu.Lock()
+	if !q.closed {
+		q.closed = true
+		close(q.items)
+	}
+	q.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		q.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,159 @@
+package clink_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/davesavic/clink"
+)
+
+func TestClient_Queue(t *testing.T) {
+	var inFlight, maxInFlight atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			max := maxInFlight.Load()
+			if n <= max || maxInFlight.CompareAndSwap(max, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+	}))
+	defer server.Close()
+
+	c := clink.NewClient(clink.WithWorkerPool(2, 10))
+
+	var mu sync.Mutex
+	var statuses []int
+	for i := 0; i < 10; i++ {
+		req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+		err := c.Queue(req, func(resp *http.Response, err error) {
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+			_ = resp.Body.Close()
+
+			mu.Lock()
+			statuses = append(statuses, resp.StatusCode)
+			mu.Unlock()
+		})
+		if err != nil {
+			t.Fatalf("failed to queue request: %v", err)
+		}
+	}
+
+	if err := c.Shutdown(context.Background()); err != nil {
+		t.Fatalf("failed to shut down: %v", err)
+	}
+
+	if len(statuses) != 10 {
+		t.Errorf("expected all queued requests to be sent before shutdown returns, got: %d", len(statuses))
+	}
+
+	if max := maxInFlight.Load(); max > 2 {
+		t.Errorf("expected at most 2 requests in flight, got: %d", max)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	if err := c.Queue(req, nil); !errors.Is(err, clink.ErrQueueClosed) {
+		t.Errorf("expected queue closed error, got: %v", err)
+	}
+}
+
+func TestClient_QueueFull(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+
+	c := clink.NewClient(clink.WithWorkerPool(1, 1))
+
+	var errs []error
+	for i := 0; i < 3; i++ {
+		req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+		errs = append(errs, c.Queue(req, nil))
+		time.Sleep(10 * time.Millisecond)
+	}
+	close(release)
+
+	if errs[0] != nil || errs[1] != nil || !errors.Is(errs[2], clink.ErrQueueFull) {
+		t.Errorf("expected third request to be rejected, got: %v", errs)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := c.Shutdown(ctx); err != nil {
+		t.Errorf("failed to shut down: %v", err)
+	}
+}
+
+func TestWithWorkerPool_Invalid(t *testing.T) {
+	testCases := []struct {
+		name      string
+		workers   int
+		queueSize int
+	}{
+		{name: "negative workers", workers: -1, queueSize: 10},
+		{name: "zero queue size", workers: 2, queueSize: 0},
+		{name: "negative queue size", workers: 2, queueSize: -1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := clink.NewClient(clink.WithWorkerPool(tc.workers, tc.queueSize))
+
+			if _, err := c.Get("http://example.com"); !errors.Is(err, clink.ErrInvalidOption) {
+				t.Errorf("expected ErrInvalidOption, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestClient_Close(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	c := clink.NewClient()
+	deliveries := c.NewDeliveryQueue(clink.NewMemoryDeliveryStore(), clink.DeliveryOptions{})
+
+	var sent atomic.Bool
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	err := c.Queue(req, func(resp *http.Response, err error) {
+		if err == nil {
+			_ = clink.DrainAndClose(resp)
+		}
+		sent.Store(err == nil)
+	})
+	if err != nil {
+		t.Fatalf("failed to queue request: %v", err)
+	}
+
+	if err := c.Close(); err != nil {
+		t.Fatalf("failed to close: %v", err)
+	}
+
+	if !sent.Load() {
+		t.Errorf("expected queued request to be sent before closing")
+	}
+
+	if open := c.Stats().OpenConnections; open != 0 {
+		t.Errorf("expected idle connections to be closed, got %d open", open)
+	}
+
+	if err := c.Queue(req, func(*http.Response, error) {}); !errors.Is(err, clink.ErrQueueClosed) {
+		t.Errorf("expected ErrQueueClosed from the queue, got: %v", err)
+	}
+
+	if err := deliveries.Enqueue(req); !errors.Is(err, clink.ErrQueueClosed) {
+		t.Errorf("expected ErrQueueClosed from the delivery queue, got: %v", err)
+	}
+}
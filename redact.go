@@ -0,0 +1,228 @@
+package clink
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// RedactedValue replaces the values of redacted headers and JSON fields.
+const RedactedValue = "[REDACTED]"
+
+// DefaultRedactedHeaders are the headers redacted by every Redactor unless explicitly allowed.
+var DefaultRedactedHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+
+// DefaultRedactedFields are the JSON fields redacted by every Redactor unless explicitly allowed.
+var DefaultRedactedFields = []string{"password", "access_token", "refresh_token", "client_secret"}
+
+// DefaultRedactedQueryParams are the URL query parameters redacted by every Redactor unless explicitly allowed.
+var DefaultRedactedQueryParams = []string{"access_token", "api_key", "apikey", "client_secret", "password", "signature", "token"}
+
+// Redactor removes sensitive header values and JSON fields from data that leaves the client,
+// such as logs, errors and debug output. A nil Redactor redacts the defaults.
+type Redactor struct {
+	// Headers are additional header names whose values are redacted.
+	Headers []string
+	// AllowHeaders are header names that are never redacted, including default ones.
+	AllowHeaders []string
+	// Fields are additional JSON object keys whose values are redacted, at any depth.
+	Fields []string
+	// AllowFields are JSON object keys that are never redacted, including default ones.
+	AllowFields []string
+	// QueryParams are additional URL query parameter names whose values are redacted.
+	QueryParams []string
+	// AllowQueryParams are URL query parameter names that are never redacted, including default ones.
+	AllowQueryParams []string
+}
+
+// WithRedactor sets the redactor used for logs, errors and debug output.
+func WithRedactor(r *Redactor) Option {
+	return func(c *Client) {
+		c.Redactor = r
+	}
+}
+
+// RedactsHeader reports whether the values of the named header are redacted.
+func (r *Redactor) RedactsHeader(name string) bool {
+	if r == nil {
+		return containsFold(DefaultRedactedHeaders, name)
+	}
+
+	if containsFold(r.AllowHeaders, name) {
+		return false
+	}
+
+	return containsFold(DefaultRedactedHeaders, name) || containsFold(r.Headers, name)
+}
+
+// RedactsField reports whether the values of the named JSON field are redacted.
+func (r *Redactor) RedactsField(name string) bool {
+	if r == nil {
+		return containsFold(DefaultRedactedFields, name)
+	}
+
+	if containsFold(r.AllowFields, name) {
+		return false
+	}
+
+	return containsFold(DefaultRedactedFields, name) || containsFold(r.Fields, name)
+}
+
+// RedactsQueryParam reports whether the values of the named URL query parameter are redacted.
+func (r *Redactor) RedactsQueryParam(name string) bool {
+	if r == nil {
+		return containsFold(DefaultRedactedQueryParams, name)
+	}
+
+	if containsFold(r.AllowQueryParams, name) {
+		return false
+	}
+
+	return containsFold(DefaultRedactedQueryParams, name) || containsFold(r.QueryParams, name)
+}
+
+// URL returns the URL as a string with its password and redacted query parameter values replaced
+// by RedactedValue.
+func (r *Redactor) URL(u *url.URL) string {
+	if u == nil {
+		return ""
+	}
+
+	if u.RawQuery == "" {
+		return u.Redacted()
+	}
+
+	query, err := url.ParseQuery(u.RawQuery)
+	if err != nil {
+		return u.Redacted()
+	}
+
+	var redacted bool
+	for name, values := range query {
+		if !r.RedactsQueryParam(name) {
+			continue
+		}
+
+		for i := range values {
+			values[i] = RedactedValue
+		}
+		redacted = true
+	}
+
+	if !redacted {
+		return u.Redacted()
+	}
+
+	clone := *u
+	clone.RawQuery = query.Encode()
+
+	return clone.Redacted()
+}
+
+// Header returns a copy of the header with redacted values replaced by RedactedValue.
+func (r *Redactor) Header(header http.Header) http.Header {
+	if header == nil {
+		return nil
+	}
+
+	redacted := header.Clone()
+	for name, values := range redacted {
+		if !r.RedactsHeader(name) {
+			continue
+		}
+
+		for i := range values {
+			values[i] = RedactedValue
+		}
+	}
+
+	return redacted
+}
+
+// JSON returns the body with redacted field values replaced by RedactedValue.
+// Bodies that aren't valid JSON, such as truncated ones, are scrubbed of the string, number and
+// literal values of redacted fields instead. Bodies without redacted fields are returned unchanged.
+func (r *Redactor) JSON(body []byte) []byte {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+
+	var v any
+	if err := dec.Decode(&v); err != nil || dec.More() {
+		return r.scrubJSON(body)
+	}
+
+	if !r.redactValue(v) {
+		return body
+	}
+
+	redacted, err := json.Marshal(v)
+	if err != nil {
+		return body
+	}
+
+	return redacted
+}
+
+// jsonFieldPattern matches a JSON object key and its value, if the value is a string, possibly
+// unterminated, a number or a literal.
+var jsonFieldPattern = regexp.MustCompile(`"((?:[^"\\]|\\.)*)"(\s*:\s*)("(?:[^"\\]|\\.)*"?|[^\s,{}\[\]"]+)`)
+
+// scrubJSON replaces the values of redacted fields in a body that can't be decoded as JSON.
+func (r *Redactor) scrubJSON(body []byte) []byte {
+	var redacted bool
+
+	scrubbed := jsonFieldPattern.ReplaceAllFunc(body, func(match []byte) []byte {
+		groups := jsonFieldPattern.FindSubmatch(match)
+
+		var key string
+		if err := json.Unmarshal([]byte(`"`+string(groups[1])+`"`), &key); err != nil || !r.RedactsField(key) {
+			return match
+		}
+
+		redacted = true
+		return []byte(string(match[:len(match)-len(groups[3])]) + `"` + RedactedValue + `"`)
+	})
+
+	if !redacted {
+		return body
+	}
+
+	return scrubbed
+}
+
+// redactValue redacts fields of the decoded JSON value in place and reports whether any were redacted.
+func (r *Redactor) redactValue(v any) bool {
+	var redacted bool
+
+	switch v := v.(type) {
+	case map[string]any:
+		for key, value := range v {
+			if r.RedactsField(key) {
+				v[key] = RedactedValue
+				redacted = true
+				continue
+			}
+
+			redacted = r.redactValue(value) || redacted
+		}
+	case []any:
+		for _, value := range v {
+			redacted = r.redactValue(value) || redacted
+		}
+	}
+
+	return redacted
+}
+
+func containsFold(names []string, name string) bool {
+	for _, n := range names {
+		if strings.EqualFold(n, name) {
+			return true
+		}
+	}
+
+	return false
+}
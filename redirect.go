@@ -0,0 +1,148 @@
+package clink
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// DefaultMaxRedirects is the maximum number of redirects followed by default, like http.Client.
+const DefaultMaxRedirects = 10
+
+// ErrTooManyRedirects is returned when a request exceeds the maximum number of redirects.
+var ErrTooManyRedirects = errors.New("too many redirects")
+
+// WithRedirectPolicy sets how the client follows redirects. If follow is false, redirect responses
+// are returned to the caller as they are; otherwise at most maxRedirects redirects are followed
+// before ErrTooManyRedirects is returned. When used with WithClient, it must come after it;
+// the given http.Client is copied rather than modified.
+func WithRedirectPolicy(maxRedirects int, follow bool) Option {
+	return func(c *Client) {
+		if maxRedirects < 0 {
+			c.addConfigError("WithRedirectPolicy: max redirects must not be negative, got %d", maxRedirects)
+			return
+		}
+
+		c.MaxRedirects = maxRedirects
+		c.FollowRedirects = follow
+		c.installRedirectHook("WithRedirectPolicy")
+	}
+}
+
+// WithCheckRedirect adds a hook called before following each redirect, after the redirect policy.
+// It has the semantics of http.Client.CheckRedirect: returning http.ErrUseLastResponse returns the
+// redirect response, any other error fails the request. When used with WithClient, it must come after it.
+func WithCheckRedirect(check func(req *http.Request, via []*http.Request) error) Option {
+	return func(c *Client) {
+		c.CheckRedirectFunc = check
+		c.installRedirectHook("WithCheckRedirect")
+	}
+}
+
+// DefaultRedirectStrippedHeaders are the headers removed from requests redirected to another origin.
+var DefaultRedirectStrippedHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "WWW-Authenticate"}
+
+// RedirectHeaderPolicy controls which headers of a request are forwarded when it is redirected to
+// another origin (scheme, host or port). DefaultRedirectStrippedHeaders are always removed unless
+// explicitly forwarded. Redirects within the same origin forward every header.
+type RedirectHeaderPolicy struct {
+	// Strip are additional headers removed on redirects to another origin.
+	Strip []string
+	// Forward are headers forwarded on redirects to another origin, including default stripped ones.
+	Forward []string
+}
+
+// WithRedirectHeaderPolicy sets which headers are forwarded on redirects to another origin.
+// When used with WithClient, it must come after it.
+func WithRedirectHeaderPolicy(policy RedirectHeaderPolicy) Option {
+	return func(c *Client) {
+		c.RedirectHeaderPolicy = policy
+		c.installRedirectHook("WithRedirectHeaderPolicy")
+	}
+}
+
+// apply removes the headers that must not be forwarded from a request redirected from the original request.
+func (p RedirectHeaderPolicy) apply(req, original *http.Request) {
+	if sameOrigin(req.URL, original.URL) {
+		return
+	}
+
+	for _, name := range DefaultRedirectStrippedHeaders {
+		if !containsFold(p.Forward, name) {
+			req.Header.Del(name)
+		}
+	}
+
+	for _, name := range p.Strip {
+		if !containsFold(p.Forward, name) {
+			req.Header.Del(name)
+		}
+	}
+
+	// http.Client drops sensitive headers on redirects to other domains; restore forwarded ones.
+	for _, name := range p.Forward {
+		if values := original.Header.Values(name); len(values) > 0 && req.Header.Get(name) == "" {
+			req.Header[http.CanonicalHeaderKey(name)] = values
+		}
+	}
+}
+
+// sameOrigin reports whether the URLs have the same scheme, host and port.
+func sameOrigin(a, b *url.URL) bool {
+	return a.Scheme == b.Scheme && strings.EqualFold(a.Host, b.Host)
+}
+
+// installRedirectHook makes the http client use the client's redirect policy, set by the named option.
+func (c *Client) installRedirectHook(option string) {
+	if !slices.Contains(c.redirectOptions, option) {
+		c.redirectOptions = append(slices.Clip(c.redirectOptions), option)
+	}
+
+	if c.transport == nil {
+		client := *c.HttpClient
+		c.HttpClient = &client
+	}
+
+	c.HttpClient.CheckRedirect = c.checkRedirect
+	c.redirectHook = true
+}
+
+// replaceRedirectHook records a configuration error for WithClient replacing the http client if
+// earlier options installed the redirect hook on it, since their settings, such as WithHTTPSOnly,
+// would be silently dropped.
+func (c *Client) replaceRedirectHook() {
+	if len(c.redirectOptions) > 0 {
+		c.addConfigError("WithClient cannot be used after %s", strings.Join(c.redirectOptions, ", "))
+	}
+}
+
+// checkRedirect applies the redirect policy and hook of the client to a redirect.
+func (c *Client) checkRedirect(req *http.Request, via []*http.Request) error {
+	if !c.FollowRedirects {
+		return http.ErrUseLastResponse
+	}
+
+	if len(via) > c.MaxRedirects {
+		return fmt.Errorf("%w: stopped after %d redirects", ErrTooManyRedirects, c.MaxRedirects)
+	}
+
+	if err := c.checkDestination(req.URL); err != nil {
+		return err
+	}
+
+	if err := c.checkProxiedDestination(req); err != nil {
+		return err
+	}
+
+	c.RedirectHeaderPolicy.apply(req, via[0])
+	c.ReferrerPolicy.applyRedirect(req, via[len(via)-1])
+
+	if c.CheckRedirectFunc != nil {
+		return c.CheckRedirectFunc(req, via)
+	}
+
+	return nil
+}
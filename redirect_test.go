@@ -0,0 +1,180 @@
+package clink_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/davesavic/clink"
+)
+
+func newRedirectServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	// /n redirects to /n-1 until /0, which responds with 200.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n, _ := strconv.Atoi(r.URL.Path[1:])
+		if n > 0 {
+			http.Redirect(w, r, "/"+strconv.Itoa(n-1), http.StatusFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestWithRedirectPolicy(t *testing.T) {
+	testCases := []struct {
+		name       string
+		path       string
+		opts       []clink.Option
+		resultFunc func(resp *http.Response, err error) bool
+	}{
+		{
+			name: "follows redirects by default",
+			path: "/3",
+			resultFunc: func(resp *http.Response, err error) bool {
+				return err == nil && resp.StatusCode == http.StatusOK
+			},
+		},
+		{
+			name: "returns redirect response when not following",
+			path: "/3",
+			opts: []clink.Option{clink.WithRedirectPolicy(0, false)},
+			resultFunc: func(resp *http.Response, err error) bool {
+				return err == nil && resp.StatusCode == http.StatusFound && resp.Header.Get("Location") == "/2"
+			},
+		},
+		{
+			name: "follows up to the maximum",
+			path: "/2",
+			opts: []clink.Option{clink.WithRedirectPolicy(2, true)},
+			resultFunc: func(resp *http.Response, err error) bool {
+				return err == nil && resp.StatusCode == http.StatusOK
+			},
+		},
+		{
+			name: "fails over the maximum",
+			path: "/3",
+			opts: []clink.Option{clink.WithRedirectPolicy(2, true)},
+			resultFunc: func(_ *http.Response, err error) bool {
+				return errors.Is(err, clink.ErrTooManyRedirects)
+			},
+		},
+		{
+			name: "calls custom hook",
+			path: "/3",
+			opts: []clink.Option{clink.WithCheckRedirect(func(req *http.Request, _ []*http.Request) error {
+				if req.URL.Path == "/1" {
+					return http.ErrUseLastResponse
+				}
+				return nil
+			})},
+			resultFunc: func(resp *http.Response, err error) bool {
+				return err == nil && resp.StatusCode == http.StatusFound && resp.Header.Get("Location") == "/1"
+			},
+		},
+		{
+			name: "applies to custom client without modifying it",
+			path: "/3",
+			opts: []clink.Option{clink.WithClient(http.DefaultClient), clink.WithRedirectPolicy(0, false)},
+			resultFunc: func(resp *http.Response, err error) bool {
+				return err == nil && resp.StatusCode == http.StatusFound && http.DefaultClient.CheckRedirect == nil
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := newRedirectServer(t)
+
+			resp, err := clink.NewClient(tc.opts...).Get(server.URL + tc.path)
+			if err == nil {
+				_ = resp.Body.Close()
+			}
+
+			if !tc.resultFunc(resp, err) {
+				t.Errorf("unexpected result: %v, %v", resp, err)
+			}
+		})
+	}
+}
+
+func TestRedirectHeaderPolicy(t *testing.T) {
+	testCases := []struct {
+		name       string
+		opts       []clink.Option
+		sameOrigin bool
+		resultFunc func(header http.Header) bool
+	}{
+		{
+			name: "strips credentials on cross-origin redirect by default",
+			resultFunc: func(h http.Header) bool {
+				return h.Get("Authorization") == "" && h.Get("Cookie") == "" && h.Get("X-Api-Key") == "key"
+			},
+		},
+		{
+			name:       "forwards credentials on same-origin redirect",
+			sameOrigin: true,
+			resultFunc: func(h http.Header) bool {
+				return h.Get("Authorization") == "Bearer token" && h.Get("Cookie") == "a=1"
+			},
+		},
+		{
+			name: "strips configured headers",
+			opts: []clink.Option{clink.WithRedirectHeaderPolicy(clink.RedirectHeaderPolicy{Strip: []string{"X-Api-Key"}})},
+			resultFunc: func(h http.Header) bool {
+				return h.Get("X-Api-Key") == "" && h.Get("Authorization") == ""
+			},
+		},
+		{
+			name: "forwards allowed headers",
+			opts: []clink.Option{clink.WithRedirectHeaderPolicy(clink.RedirectHeaderPolicy{Forward: []string{"Authorization"}})},
+			resultFunc: func(h http.Header) bool {
+				return h.Get("Authorization") == "Bearer token" && h.Get("Cookie") == ""
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var received http.Header
+			target := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+				received = r.Header.Clone()
+			}))
+			defer target.Close()
+
+			origin := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/target" {
+					received = r.Header.Clone()
+					return
+				}
+				location := target.URL
+				if tc.sameOrigin {
+					location = "/target"
+				}
+				http.Redirect(w, r, location, http.StatusFound)
+			}))
+			defer origin.Close()
+
+			req, _ := http.NewRequest(http.MethodGet, origin.URL, nil)
+			req.Header.Set("Authorization", "Bearer token")
+			req.Header.Set("Cookie", "a=1")
+			req.Header.Set("X-Api-Key", "key")
+
+			resp, err := clink.NewClient(tc.opts...).Do(req)
+			if err != nil {
+				t.Fatalf("failed to make request: %v", err)
+			}
+			_ = resp.Body.Close()
+
+			if !tc.resultFunc(received) {
+				t.Errorf("unexpected forwarded headers: %v", received)
+			}
+		})
+	}
+}
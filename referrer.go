@@ -0,0 +1,70 @@
+package clink
+
+import (
+	"net/http"
+	"net/url"
+)
+
+// ReferrerPolicy controls the Referer header sent by the client.
+type ReferrerPolicy int
+
+const (
+	// ReferrerFull sends the full URL of the referring page, without credentials. On redirects,
+	// it is the URL of the redirecting request, omitted when redirecting from HTTPS to HTTP.
+	// This is the default, and the behaviour of http.Client.
+	ReferrerFull ReferrerPolicy = iota
+	// ReferrerOrigin only sends the scheme, host and port of the referring page.
+	ReferrerOrigin
+	// NoReferrer never sends a Referer header.
+	NoReferrer
+)
+
+// WithReferrerPolicy sets the Referer header sent on redirects and applies the policy to Referer
+// headers set on requests. When used with WithClient, it must come after it.
+func WithReferrerPolicy(policy ReferrerPolicy) Option {
+	return func(c *Client) {
+		c.ReferrerPolicy = policy
+		c.installRedirectHook("WithReferrerPolicy")
+	}
+}
+
+// apply rewrites the Referer header of the request according to the policy.
+func (p ReferrerPolicy) apply(req *http.Request) {
+	referer := req.Header.Get("Referer")
+	if referer == "" || p == ReferrerFull {
+		return
+	}
+
+	if p == NoReferrer {
+		req.Header.Del("Referer")
+		return
+	}
+
+	u, err := url.Parse(referer)
+	if err != nil || u.Host == "" {
+		req.Header.Del("Referer")
+		return
+	}
+
+	req.Header.Set("Referer", origin(u))
+}
+
+// applyRedirect sets the Referer header of a request redirected from the previous request.
+func (p ReferrerPolicy) applyRedirect(req, previous *http.Request) {
+	if p == ReferrerFull {
+		// http.Client has already set it.
+		return
+	}
+
+	if p == NoReferrer || previous.URL.Scheme == "https" && req.URL.Scheme == "http" {
+		req.Header.Del("Referer")
+		return
+	}
+
+	req.Header.Set("Referer", origin(previous.URL))
+}
+
+// origin returns the scheme, host and port of the URL, in the form of a Referer header.
+func origin(u *url.URL) string {
+	return u.Scheme + "://" + u.Host + "/"
+}
@@ -0,0 +1,88 @@
+package clink_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/davesavic/clink"
+)
+
+func TestWithReferrerPolicy(t *testing.T) {
+	testCases := []struct {
+		name     string
+		opts     []clink.Option
+		redirect bool
+		referer  string
+		expected func(origin string) string
+	}{
+		{
+			name:     "sends full url on redirects by default",
+			redirect: true,
+			expected: func(origin string) string { return origin + "/start?q=1" },
+		},
+		{
+			name:     "sends origin on redirects",
+			opts:     []clink.Option{clink.WithReferrerPolicy(clink.ReferrerOrigin)},
+			redirect: true,
+			expected: func(origin string) string { return origin + "/" },
+		},
+		{
+			name:     "sends no referer on redirects",
+			opts:     []clink.Option{clink.WithReferrerPolicy(clink.NoReferrer)},
+			redirect: true,
+			expected: func(string) string { return "" },
+		},
+		{
+			name:     "keeps request referer by default",
+			referer:  "https://example.com/page?secret=1",
+			expected: func(string) string { return "https://example.com/page?secret=1" },
+		},
+		{
+			name:     "reduces request referer to origin",
+			opts:     []clink.Option{clink.WithReferrerPolicy(clink.ReferrerOrigin)},
+			referer:  "https://example.com/page?secret=1",
+			expected: func(string) string { return "https://example.com/" },
+		},
+		{
+			name:     "removes request referer",
+			opts:     []clink.Option{clink.WithReferrerPolicy(clink.NoReferrer)},
+			referer:  "https://example.com/page",
+			expected: func(string) string { return "" },
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var received string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/start" {
+					http.Redirect(w, r, "/end", http.StatusFound)
+					return
+				}
+				received = r.Header.Get("Referer")
+			}))
+			defer server.Close()
+
+			path := "/end"
+			if tc.redirect {
+				path = "/start?q=1"
+			}
+
+			req, _ := http.NewRequest(http.MethodGet, server.URL+path, nil)
+			if tc.referer != "" {
+				req.Header.Set("Referer", tc.referer)
+			}
+
+			resp, err := clink.NewClient(tc.opts...).Do(req)
+			if err != nil {
+				t.Fatalf("failed to make request: %v", err)
+			}
+			_ = resp.Body.Close()
+
+			if expected := tc.expected(server.URL); received != expected {
+				t.Errorf("expected referer %q, got: %q", expected, received)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package clink
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// RequestOption configures the behaviour of the client for a single request.
+type RequestOption func(*requestOptions)
+
+type requestOptions struct {
+	bypassCache  bool
+	revalidate   bool
+	cacheTTL     time.Duration
+	retries      *retryOverride
+	tags         []string
+	unbuffered   bool
+	bodySource   BodySource
+	checksum     *expectedChecksum
+	digestHeader *bool
+	progress     ProgressReporter
+	accept       []string
+}
+
+type retryOverride struct {
+	count     int
+	retryFunc func(*http.Request, *http.Response, error) bool
+}
+
+type requestOptionsContextKey struct{}
+
+// ConfigureRequest returns a shallow copy of the request carrying the given per-request options,
+// in addition to any options the request already carries.
+func ConfigureRequest(req *http.Request, opts ...RequestOption) *http.Request {
+	o := *requestOptionsFrom(req.Context())
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return req.WithContext(context.WithValue(req.Context(), requestOptionsContextKey{}, &o))
+}
+
+// requestOptionsFrom returns the per-request options stored in the context, or the defaults.
+func requestOptionsFrom(ctx context.Context) *requestOptions {
+	if o, ok := ctx.Value(requestOptionsContextKey{}).(*requestOptions); ok {
+		return o
+	}
+	return &requestOptions{}
+}
+
+// BypassCache makes the request skip the HTTP cache: the response is neither read from nor stored in the cache.
+func BypassCache() RequestOption {
+	return func(o *requestOptions) {
+		o.bypassCache = true
+	}
+}
+
+// Revalidate forces a cached response to be revalidated with the server before it is used.
+func Revalidate() RequestOption {
+	return func(o *requestOptions) {
+		o.revalidate = true
+	}
+}
+
+// CacheTTL caches the response for the given duration, regardless of the freshness
+// information sent by the server. Responses marked no-store are still not cached.
+func CacheTTL(ttl time.Duration) RequestOption {
+	return func(o *requestOptions) {
+		o.cacheTTL = ttl
+	}
+}
+
+// Unbuffered makes the client hand over the response body as it is received, without reading it
+// into memory first: the response is neither cached nor shared with deduplicated requests.
+func Unbuffered() RequestOption {
+	return func(o *requestOptions) {
+		o.bypassCache = true
+		o.unbuffered = true
+	}
+}
+
+// Retries overrides the retry count and retry function of the client (see WithRetries) for the request.
+func Retries(count int, retryFunc func(*http.Request, *http.Response, error) bool) RequestOption {
+	return func(o *requestOptions) {
+		o.retries = &retryOverride{count: count, retryFunc: retryFunc}
+	}
+}
+
+// Tags tags the request with the given names. A request runs in the bulkhead (see WithBulkhead)
+// named by the first of its tags that has one.
+func Tags(tags ...string) RequestOption {
+	return func(o *requestOptions) {
+		o.tags = append(o.tags[:len(o.tags):len(o.tags)], tags...)
+	}
+}
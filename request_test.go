@@ -0,0 +1,41 @@
+package clink_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/davesavic/clink"
+)
+
+func TestConfigureRequest(t *testing.T) {
+	var requestCount int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestCount++
+	}))
+	defer server.Close()
+
+	client := clink.NewClient(clink.WithClient(server.Client()), clink.WithCache(clink.NewMemoryCache()))
+
+	original := mustRequest(t, http.MethodGet, server.URL)
+	configured := clink.ConfigureRequest(original, clink.CacheTTL(time.Minute))
+	configured = clink.ConfigureRequest(configured, clink.Revalidate())
+
+	if configured == original {
+		t.Fatalf("expected a copy of the request")
+	}
+
+	// The pinned ttl is kept when more options are added, so the response is cached.
+	if _, err := client.Do(configured); err != nil {
+		t.Fatalf("failed to make request: %v", err)
+	}
+
+	if _, err := client.Do(original); err != nil {
+		t.Fatalf("failed to make request: %v", err)
+	}
+
+	if requestCount != 1 {
+		t.Errorf("expected second request to be served from cache, got %d requests", requestCount)
+	}
+}
@@ -0,0 +1,40 @@
+package clink
+
+import (
+	"context"
+)
+
+// DefaultRequestIDHeader is the header used to send request IDs.
+const DefaultRequestIDHeader = "X-Request-ID"
+
+type requestIDContextKey struct{}
+
+// WithRequestID stamps every request with a unique ID sent in the given header.
+// If generator is nil, random UUIDs are used. If headerName is empty, DefaultRequestIDHeader is used.
+// The same ID is sent on every retry of a request and can be read back with RequestIDFromContext
+// using the context of the request (for example response.Request.Context()).
+func WithRequestID(generator func() string, headerName string) Option {
+	return func(c *Client) {
+		if generator == nil {
+			generator = newUUID
+		}
+
+		if headerName == "" {
+			headerName = DefaultRequestIDHeader
+		}
+
+		c.RequestIDGenerator = generator
+		c.RequestIDHeader = headerName
+	}
+}
+
+// RequestIDFromContext returns the request ID stored in the context by the client.
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(requestIDContextKey{}).(string)
+	return id, ok
+}
+
+// contextWithRequestID returns a copy of the context carrying the request ID.
+func contextWithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDContextKey{}, id)
+}
@@ -0,0 +1,102 @@
+package clink_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/davesavic/clink"
+)
+
+func TestRequestID(t *testing.T) {
+	testCases := []struct {
+		name       string
+		opts       []clink.Option
+		header     string
+		resultFunc func(ids []string, resp *http.Response) bool
+	}{
+		{
+			name: "default generator and header",
+			opts: []clink.Option{clink.WithRequestID(nil, "")},
+			resultFunc: func(ids []string, resp *http.Response) bool {
+				id, ok := clink.RequestIDFromContext(resp.Request.Context())
+				return ok && len(ids) == 2 && len(ids[0]) == 36 && ids[0] == ids[1] && ids[0] == id
+			},
+		},
+		{
+			name:   "custom generator and header",
+			opts:   []clink.Option{clink.WithRequestID(func() string { return "custom-id" }, "X-Correlation")},
+			header: "X-Correlation",
+			resultFunc: func(ids []string, resp *http.Response) bool {
+				id, _ := clink.RequestIDFromContext(resp.Request.Context())
+				return len(ids) == 2 && ids[0] == "custom-id" && ids[1] == "custom-id" && id == "custom-id"
+			},
+		},
+		{
+			name: "no request id without option",
+			resultFunc: func(ids []string, resp *http.Response) bool {
+				_, ok := clink.RequestIDFromContext(resp.Request.Context())
+				return !ok && len(ids) == 2 && ids[0] == ""
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			header := tc.header
+			if header == "" {
+				header = clink.DefaultRequestIDHeader
+			}
+
+			var ids []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				ids = append(ids, r.Header.Get(header))
+				if len(ids) == 1 {
+					w.WriteHeader(http.StatusServiceUnavailable)
+				}
+			}))
+			defer server.Close()
+
+			opts := append(tc.opts,
+				clink.WithClient(server.Client()),
+				clink.WithRetries(1, func(request *http.Request, response *http.Response, err error) bool {
+					return response.StatusCode == http.StatusServiceUnavailable
+				}),
+			)
+			client := clink.NewClient(opts...)
+
+			resp, err := client.Get(server.URL)
+			if err != nil {
+				t.Fatalf("failed to make request: %v", err)
+			}
+
+			if !tc.resultFunc(ids, resp) {
+				t.Errorf("unexpected request ids: %v", ids)
+			}
+		})
+	}
+}
+
+func TestRequestID_RequestReused(t *testing.T) {
+	var ids []string
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		ids = append(ids, r.Header.Get(clink.DefaultRequestIDHeader))
+	}))
+	defer server.Close()
+
+	client := clink.NewClient(clink.WithRequestID(nil, ""))
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	for range 2 {
+		if _, err := client.Do(req); err != nil {
+			t.Fatalf("failed to make request: %v", err)
+		}
+	}
+
+	if req.Header.Get(clink.DefaultRequestIDHeader) != "" {
+		t.Errorf("expected the request header to be left unchanged, got: %v", req.Header)
+	}
+	if len(ids) != 2 || ids[0] == "" || ids[0] == ids[1] {
+		t.Errorf("expected a new request ID for each send, got: %v", ids)
+	}
+}
@@ -0,0 +1,92 @@
+package clink
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// Response wraps an *http.Response with convenience methods.
+type Response struct {
+	*http.Response
+
+	duration time.Duration
+	attempts int
+	trace    *requestTrace
+	body     []byte
+	bodyErr  error
+	bodyRead bool
+}
+
+// maxDrainBytes is the maximum number of bytes read from a body by DrainAndClose.
+const maxDrainBytes = 256 << 10
+
+// DrainAndClose reads the remainder of the response body (up to a limit) and closes it,
+// so that the underlying connection can be reused. It is safe to call with a nil response or body.
+func DrainAndClose(resp *http.Response) error {
+	if resp == nil || resp.Body == nil {
+		return nil
+	}
+
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxDrainBytes))
+
+	return resp.Body.Close()
+}
+
+// IsSuccess reports whether the response has a 2xx status code.
+func (r *Response) IsSuccess() bool {
+	return isSuccessStatus(r.Response)
+}
+
+// Bytes reads and closes the response body and returns its contents.
+// The body is read once, subsequent calls return the same contents.
+func (r *Response) Bytes() ([]byte, error) {
+	if r.bodyRead {
+		return r.body, r.bodyErr
+	}
+	r.bodyRead = true
+
+	if r.Body == nil {
+		r.bodyErr = fmt.Errorf("response body is nil")
+		return nil, r.bodyErr
+	}
+
+	r.body, r.bodyErr = io.ReadAll(r.Body)
+	if err := r.Body.Close(); err != nil && r.bodyErr == nil {
+		r.bodyErr = err
+	}
+
+	if r.bodyErr != nil {
+		r.bodyErr = fmt.Errorf("failed to read response body: %w", r.bodyErr)
+	}
+
+	return r.body, r.bodyErr
+}
+
+// String returns the response body as a string, or an empty string if the body cannot be read.
+func (r *Response) String() string {
+	body, _ := r.Bytes()
+	return string(body)
+}
+
+// JSON decodes the JSON response body into the target.
+func (r *Response) JSON(target any, opts ...DecodeOption) error {
+	body, err := r.Bytes()
+	if err != nil {
+		return err
+	}
+
+	return decodeJSON(r.Response, bytes.NewReader(body), target, opts)
+}
+
+// Duration returns the time taken to receive the response, including rate limiting and retries.
+func (r *Response) Duration() time.Duration {
+	return r.duration
+}
+
+// Attempts returns the number of attempts made to receive the response.
+func (r *Response) Attempts() int {
+	return r.attempts
+}
@@ -0,0 +1,156 @@
+package clink_test
+
+import (
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/davesavic/clink"
+)
+
+func TestResponse(t *testing.T) {
+	var requestCount int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestCount++
+		if requestCount == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+		_, _ = w.Write([]byte(`{"key": "value"}`))
+	}))
+	defer server.Close()
+
+	client := clink.NewClient(
+		clink.WithClient(server.Client()),
+		clink.WithRetries(1, func(request *http.Request, response *http.Response, err error) bool {
+			return response != nil && response.StatusCode == http.StatusServiceUnavailable
+		}),
+	)
+
+	req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	if err != nil {
+		t.Fatalf("failed to create request: %v", err)
+	}
+
+	resp, err := client.DoWrapped(req)
+	if err != nil {
+		t.Fatalf("failed to make request: %v", err)
+	}
+
+	if !resp.IsSuccess() {
+		t.Errorf("expected response to be successful, got: %d", resp.StatusCode)
+	}
+
+	if resp.Attempts() != 2 {
+		t.Errorf("expected 2 attempts, got: %d", resp.Attempts())
+	}
+
+	if resp.Duration() < 10*time.Millisecond {
+		t.Errorf("expected duration to be at least 10ms, got: %s", resp.Duration())
+	}
+
+	if resp.String() != `{"key": "value"}` {
+		t.Errorf("unexpected body: %s", resp.String())
+	}
+
+	var target map[string]string
+	if err := resp.JSON(&target); err != nil || target["key"] != "value" {
+		t.Errorf("expected body to be decoded, got: %v, %v", target, err)
+	}
+
+	body, err := resp.Bytes()
+	if err != nil || string(body) != `{"key": "value"}` {
+		t.Errorf("expected body to be readable multiple times, got: %s, %v", body, err)
+	}
+}
+
+func TestResponse_IsSuccess(t *testing.T) {
+	testCases := []struct {
+		status   int
+		expected bool
+	}{
+		{status: http.StatusOK, expected: true},
+		{status: http.StatusNoContent, expected: true},
+		{status: http.StatusMovedPermanently, expected: false},
+		{status: http.StatusNotFound, expected: false},
+		{status: http.StatusInternalServerError, expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(http.StatusText(tc.status), func(t *testing.T) {
+			resp := &clink.Response{Response: &http.Response{StatusCode: tc.status}}
+			if resp.IsSuccess() != tc.expected {
+				t.Errorf("expected IsSuccess to be %v for status %d", tc.expected, tc.status)
+			}
+		})
+	}
+}
+
+type trackingBody struct {
+	io.Reader
+	closed bool
+}
+
+func (b *trackingBody) Close() error {
+	b.closed = true
+	return nil
+}
+
+func TestDrainAndClose(t *testing.T) {
+	body := &trackingBody{Reader: strings.NewReader("remaining body")}
+
+	if err := clink.DrainAndClose(&http.Response{Body: body}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !body.closed {
+		t.Errorf("expected body to be closed")
+	}
+
+	if n, _ := body.Read(make([]byte, 1)); n != 0 {
+		t.Errorf("expected body to be drained")
+	}
+
+	if err := clink.DrainAndClose(nil); err != nil {
+		t.Errorf("expected nil response to be ignored, got: %v", err)
+	}
+}
+
+func TestRetriedResponsesAreClosed(t *testing.T) {
+	var connections int32
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte("unavailable"))
+	}))
+	server.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			atomic.AddInt32(&connections, 1)
+		}
+	}
+	server.Start()
+	defer server.Close()
+
+	client := clink.NewClient(
+		clink.WithClient(server.Client()),
+		clink.WithRetries(2, func(request *http.Request, response *http.Response, err error) bool {
+			return response != nil && response.StatusCode == http.StatusServiceUnavailable
+		}),
+	)
+
+	_, err := client.Get(server.URL)
+	var httpErr *clink.HTTPError
+	if !errors.As(err, &httpErr) {
+		t.Fatalf("expected an HTTPError once the retries are exhausted, got: %v", err)
+	}
+
+	if n := atomic.LoadInt32(&connections); n != 1 {
+		t.Errorf("expected retries to reuse a single connection, got: %d", n)
+	}
+}
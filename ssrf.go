@@ -0,0 +1,125 @@
+package clink
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"strings"
+	"syscall"
+)
+
+// ErrBlockedAddress is returned when a request is refused because its destination resolves to a
+// blocked address (see WithSSRFProtection).
+var ErrBlockedAddress = errors.New("destination address is blocked")
+
+// blockedPrefixes are the ranges refused by WithSSRFProtection in addition to the loopback,
+// private, link-local and unspecified addresses.
+var blockedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+}
+
+// WithSSRFProtection refuses to connect to loopback, private (RFC 1918 and unique local),
+// link-local, shared (including cloud metadata services such as 169.254.169.254 and
+// 100.100.100.200) and other reserved addresses, for services fetching user-supplied URLs.
+// The address is checked when connecting, after the host name is resolved, so redirects and DNS
+// rebinding can't bypass it. Requests to a blocked address fail with ErrBlockedAddress.
+// Requests sent through a proxy, including one set with the HTTP_PROXY and HTTPS_PROXY environment
+// variables, are connected to their destination by the proxy: their host is resolved and checked
+// before each request and redirect is sent to the proxy instead, which can't rule out DNS rebinding
+// between the check and the lookup made by the proxy. The address of the proxy itself is checked
+// when connecting to it, so a proxy on a blocked address can't be used.
+// The option can't be used with WithClient or WithTransport.
+func WithSSRFProtection() Option {
+	return func(c *Client) {
+		if c.ownTransport("WithSSRFProtection") == nil {
+			return
+		}
+
+		c.dialer.Control = ssrfControl
+		c.ssrfProtection = true
+	}
+}
+
+// checkProxiedDestination returns an error wrapping ErrBlockedAddress if the request is sent
+// through a proxy and its host resolves to a blocked address. Requests sent without a proxy are
+// checked when connecting.
+func (c *Client) checkProxiedDestination(req *http.Request) error {
+	if !c.ssrfProtection || !c.proxied(req) {
+		return nil
+	}
+
+	host := req.URL.Hostname()
+
+	var addrs []string
+	if _, err := netip.ParseAddr(host); err == nil {
+		addrs = []string{host}
+	} else {
+		addrs, err = lookupHost(req.Context(), c.dialer.Resolver, host)
+		if err != nil {
+			return fmt.Errorf("failed to resolve %s: %w", host, err)
+		}
+	}
+
+	for _, a := range addrs {
+		addr, err := netip.ParseAddr(a)
+		if err != nil || blockedAddr(addr) {
+			return fmt.Errorf("%w: %s", ErrBlockedAddress, a)
+		}
+	}
+
+	return nil
+}
+
+// proxied reports whether the request is sent through a proxy by the client's own transport.
+func (c *Client) proxied(req *http.Request) bool {
+	if _, ok := c.HttpClient.Transport.(*proxyPoolTransport); ok {
+		return true
+	}
+
+	if c.transport == nil || c.transport.Proxy == nil {
+		return false
+	}
+
+	proxyURL, err := c.transport.Proxy(req)
+	return err == nil && proxyURL != nil
+}
+
+// ssrfControl is the net.Dialer Control function refusing connections to blocked addresses.
+func ssrfControl(network, address string, _ syscall.RawConn) error {
+	if !strings.HasPrefix(network, "tcp") {
+		return nil
+	}
+
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return fmt.Errorf("%w: invalid address %q", ErrBlockedAddress, address)
+	}
+
+	if blockedAddr(addrPort.Addr()) {
+		return fmt.Errorf("%w: %s", ErrBlockedAddress, addrPort.Addr())
+	}
+
+	return nil
+}
+
+func blockedAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if addr.IsLoopback() || addr.IsPrivate() || addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() ||
+		addr.IsInterfaceLocalMulticast() || addr.IsMulticast() || addr.IsUnspecified() {
+		return true
+	}
+
+	for _, prefix := range blockedPrefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,106 @@
+package clink_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/davesavic/clink"
+)
+
+func TestWithSSRFProtection(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	defer server.Close()
+
+	testCases := []struct {
+		name    string
+		url     string
+		blocked bool
+	}{
+		{name: "loopback", url: server.URL, blocked: true},
+		{name: "localhost", url: "http://localhost:1", blocked: true},
+		{name: "private", url: "http://10.0.0.1", blocked: true},
+		{name: "metadata service", url: "http://169.254.169.254/latest/meta-data/", blocked: true},
+		{name: "shared address space", url: "http://100.100.100.200", blocked: true},
+		{name: "ipv4-mapped ipv6 loopback", url: "http://[::ffff:127.0.0.1]:1", blocked: true},
+		{name: "ipv6 unique local", url: "http://[fd00::1]", blocked: true},
+		{name: "public", url: "http://192.0.2.1"},
+	}
+
+	c := clink.NewClient(clink.WithSSRFProtection())
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+
+			req, _ := http.NewRequestWithContext(ctx, http.MethodGet, tc.url, nil)
+			_, err := c.Do(req)
+
+			if errors.Is(err, clink.ErrBlockedAddress) != tc.blocked {
+				t.Errorf("expected blocked to be %v, got: %v", tc.blocked, err)
+			}
+		})
+	}
+}
+
+func TestWithSSRFProtection_Proxy(t *testing.T) {
+	testCases := []struct {
+		name    string
+		opts    []clink.Option
+		url     string
+		blocked bool
+	}{
+		{name: "private destination through proxy", opts: []clink.Option{clink.WithProxy("http://192.0.2.1:8080")}, url: "http://10.0.0.1", blocked: true},
+		{name: "localhost destination through proxy", opts: []clink.Option{clink.WithProxy("http://192.0.2.1:8080")}, url: "http://localhost:1", blocked: true},
+		{name: "private destination through proxy pool", opts: []clink.Option{clink.WithProxyPool([]string{"http://192.0.2.1:8080"}, clink.ProxyRoundRobin)}, url: "http://169.254.169.254", blocked: true},
+		{name: "public destination through proxy", opts: []clink.Option{clink.WithProxy("http://192.0.2.1:8080")}, url: "http://192.0.2.2"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := clink.NewClient(append(tc.opts, clink.WithSSRFProtection())...)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+
+			req, _ := http.NewRequestWithContext(ctx, http.MethodGet, tc.url, nil)
+			_, err := c.Do(req)
+
+			if errors.Is(err, clink.ErrBlockedAddress) != tc.blocked {
+				t.Errorf("expected blocked to be %v, got: %v", tc.blocked, err)
+			}
+		})
+	}
+}
+
+func TestWithSSRFProtection_CustomTransport(t *testing.T) {
+	testCases := []struct {
+		name   string
+		client *clink.Client
+	}{
+		{
+			name:   "preset with transport",
+			client: clink.ForScraping(clink.WithTransport(http.DefaultTransport)),
+		},
+		{
+			name:   "client after protection",
+			client: clink.NewClient(clink.WithSSRFProtection(), clink.WithClient(&http.Client{})),
+		},
+		{
+			name:   "transport after another transport setting",
+			client: clink.NewClient(clink.WithHTTP2(false), clink.WithTransport(http.DefaultTransport)),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := tc.client.Get("http://127.0.0.1"); !errors.Is(err, clink.ErrInvalidOption) {
+				t.Errorf("expected ErrInvalidOption, got: %v", err)
+			}
+		})
+	}
+}
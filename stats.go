@@ -0,0 +1,68 @@
+package clink
+
+import (
+	"io"
+	"sync/atomic"
+	"time"
+)
+
+// Stats holds aggregate counters of the requests sent by a client.
+type Stats struct {
+	// Requests is the number of finished requests, including failed ones.
+	Requests int64
+	// Errors is the number of requests that returned an error.
+	Errors int64
+	// Retries is the number of retried attempts.
+	Retries int64
+	// InFlight is the number of requests currently being sent.
+	InFlight int64
+	// BytesSent is the number of request body bytes sent, including retries.
+	BytesSent int64
+	// BytesReceived is the number of response body bytes read by the caller, excluding
+	// responses served from the cache without contacting the server.
+	BytesReceived int64
+	// AverageLatency is the average duration of finished requests.
+	AverageLatency time.Duration
+	// OpenConnections is the number of open connections dialed by the client's own transport.
+	// It is always zero for clients using a custom http.Client.
+	OpenConnections int64
+}
+
+type statsCounters struct {
+	requests, errors, retries, inFlight atomic.Int64
+	bytesSent, bytesReceived            atomic.Int64
+	latency                             atomic.Int64
+	openConns                           atomic.Int64
+}
+
+// Stats returns a snapshot of the client's aggregate counters.
+func (c *Client) Stats() Stats {
+	s := Stats{
+		Requests:        c.stats.requests.Load(),
+		Errors:          c.stats.errors.Load(),
+		Retries:         c.stats.retries.Load(),
+		InFlight:        c.stats.inFlight.Load(),
+		BytesSent:       c.stats.bytesSent.Load(),
+		BytesReceived:   c.stats.bytesReceived.Load(),
+		OpenConnections: c.stats.openConns.Load(),
+	}
+
+	if s.Requests > 0 {
+		s.AverageLatency = time.Duration(c.stats.latency.Load() / s.Requests)
+	}
+
+	return s
+}
+
+// countingBody counts the bytes read from a request or response body.
+type countingBody struct {
+	io.ReadCloser
+	count *atomic.Int64
+}
+
+func (b *countingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.count.Add(int64(n))
+
+	return n, err
+}
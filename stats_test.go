@@ -0,0 +1,73 @@
+package clink_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/davesavic/clink"
+)
+
+func TestClient_Stats(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.URL.Path == "/retry" && calls == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte("hello"))
+	}))
+	defer server.Close()
+
+	c := clink.NewClient(
+		clink.WithErrorOnStatus(nil),
+		clink.WithRetries(1, func(_ *http.Request, resp *http.Response, _ error) bool {
+			return resp != nil && resp.StatusCode >= 500
+		}),
+	)
+
+	resp, err := c.Post(server.URL+"/retry", strings.NewReader("body"))
+	if err != nil {
+		t.Fatalf("failed to make request: %v", err)
+	}
+	_, _ = io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+
+	if _, err := c.Get(server.URL + "/missing"); err == nil {
+		t.Fatalf("expected error for missing resource")
+	}
+
+	stats := c.Stats()
+
+	testCases := []struct {
+		name     string
+		value    int64
+		expected int64
+	}{
+		{name: "requests", value: stats.Requests, expected: 2},
+		{name: "errors", value: stats.Errors, expected: 1},
+		{name: "retries", value: stats.Retries, expected: 1},
+		{name: "in flight", value: stats.InFlight, expected: 0},
+		{name: "bytes sent", value: stats.BytesSent, expected: 8},
+		{name: "bytes received", value: stats.BytesReceived, expected: 5},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.value != tc.expected {
+				t.Errorf("expected %d, got: %d", tc.expected, tc.value)
+			}
+		})
+	}
+
+	if stats.AverageLatency <= 0 {
+		t.Errorf("expected positive average latency, got: %v", stats.AverageLatency)
+	}
+}
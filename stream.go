@@ -0,0 +1,130 @@
+package clink
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+)
+
+// StreamJSON returns an iterator that incrementally decodes the response body into values of type T.
+// The body can either contain newline-delimited JSON values (NDJSON) or a single top-level JSON array,
+// whose elements are yielded one at a time without buffering the whole body.
+// Iteration stops after the first error, and the response body is closed when iteration ends.
+func StreamJSON[T any](response *http.Response, opts ...DecodeOption) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+
+		if response == nil {
+			yield(zero, fmt.Errorf("response is nil"))
+			return
+		}
+
+		if response.Body == nil {
+			yield(zero, fmt.Errorf("response body is nil"))
+			return
+		}
+
+		defer func(Body io.ReadCloser) {
+			_ = Body.Close()
+		}(response.Body)
+
+		reader := bufio.NewReader(response.Body)
+
+		first, err := peekNonSpace(reader)
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			yield(zero, fmt.Errorf("failed to read response body: %w", err))
+			return
+		}
+
+		decoder := newJSONDecoder(reader, newDecodeOptions(opts))
+
+		if first == '[' {
+			if _, err := decoder.Token(); err != nil {
+				yield(zero, fmt.Errorf("failed to decode response: %w", err))
+				return
+			}
+		}
+
+		for first != '[' || decoder.More() {
+			var value T
+			err := decoder.Decode(&value)
+			if err == io.EOF && first != '[' {
+				return
+			}
+			if err != nil {
+				yield(zero, fmt.Errorf("failed to decode response: %w", err))
+				return
+			}
+
+			if !yield(value, nil) {
+				return
+			}
+		}
+
+		if _, err := decoder.Token(); err != nil {
+			yield(zero, fmt.Errorf("failed to decode response: %w", err))
+		}
+	}
+}
+
+// GetInto sends a GET request to the URL and copies the response body to w as it is received,
+// without buffering it in memory (see Unbuffered). It returns the number of bytes written.
+// Responses with a non-2xx status are returned as an *HTTPError and nothing is written.
+// The request options are applied to the request, for example to verify the download with
+// ExpectChecksum.
+func (c *Client) GetInto(ctx context.Context, url string, w io.Writer, opts ...RequestOption) (int64, error) {
+	n, _, err := c.getInto(ctx, url, w, opts)
+
+	return n, err
+}
+
+// getInto implements GetInto, and also returns the header of the response.
+func (c *Client) getInto(ctx context.Context, url string, w io.Writer, opts []RequestOption) (int64, http.Header, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.Do(ConfigureRequest(req, append(opts, Unbuffered())...))
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if !isSuccessStatus(resp) {
+		return 0, nil, newHTTPError(resp, c.config().Redactor)
+	}
+
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(resp.Body)
+
+	n, err := io.Copy(w, resp.Body)
+	if err != nil {
+		return n, resp.Header, fmt.Errorf("failed to copy response body: %w", err)
+	}
+
+	return n, resp.Header, nil
+}
+
+// peekNonSpace skips leading whitespace and returns the next byte without consuming it.
+func peekNonSpace(r *bufio.Reader) (byte, error) {
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+
+		switch b {
+		case ' ', '\t', '\r', '\n':
+			continue
+		}
+
+		return b, r.UnreadByte()
+	}
+}
@@ -0,0 +1,180 @@
+package clink_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/davesavic/clink"
+)
+
+func TestStreamJSON(t *testing.T) {
+	type item struct {
+		ID int `json:"id"`
+	}
+
+	collect := func(body string) ([]int, error) {
+		resp := &http.Response{Body: io.NopCloser(strings.NewReader(body))}
+
+		var ids []int
+		for value, err := range clink.StreamJSON[item](resp) {
+			if err != nil {
+				return ids, err
+			}
+			ids = append(ids, value.ID)
+		}
+		return ids, nil
+	}
+
+	testCases := []struct {
+		name       string
+		body       string
+		resultFunc func([]int, error) bool
+	}{
+		{
+			name: "newline delimited json",
+			body: "{\"id\": 1}\n{\"id\": 2}\n\n{\"id\": 3}\n",
+			resultFunc: func(ids []int, err error) bool {
+				return err == nil && len(ids) == 3 && ids[2] == 3
+			},
+		},
+		{
+			name: "top level array",
+			body: "  [{\"id\": 1}, {\"id\": 2}]",
+			resultFunc: func(ids []int, err error) bool {
+				return err == nil && len(ids) == 2 && ids[1] == 2
+			},
+		},
+		{
+			name: "empty array",
+			body: "[]",
+			resultFunc: func(ids []int, err error) bool {
+				return err == nil && len(ids) == 0
+			},
+		},
+		{
+			name: "empty body",
+			body: "",
+			resultFunc: func(ids []int, err error) bool {
+				return err == nil && len(ids) == 0
+			},
+		},
+		{
+			name: "invalid value stops iteration",
+			body: "{\"id\": 1}\n{\"id\": \n",
+			resultFunc: func(ids []int, err error) bool {
+				return err != nil && len(ids) == 1 && strings.Contains(err.Error(), "failed to decode response")
+			},
+		},
+		{
+			name: "unterminated array",
+			body: "[{\"id\": 1}",
+			resultFunc: func(ids []int, err error) bool {
+				return err != nil && len(ids) == 1
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ids, err := collect(tc.body)
+			if !tc.resultFunc(ids, err) {
+				t.Errorf("unexpected result: %v, %v", ids, err)
+			}
+		})
+	}
+}
+
+func TestStreamJSON_EarlyBreakClosesBody(t *testing.T) {
+	body := &trackingBody{Reader: strings.NewReader("[1, 2, 3]")}
+
+	for value, err := range clink.StreamJSON[int](&http.Response{Body: body}) {
+		if err != nil || value != 1 {
+			t.Fatalf("unexpected value: %v, %v", value, err)
+		}
+		break
+	}
+
+	if !body.closed {
+		t.Errorf("expected body to be closed")
+	}
+}
+
+func TestStreamJSON_NilResponse(t *testing.T) {
+	for _, err := range clink.StreamJSON[int](nil) {
+		if err == nil || err.Error() != "response is nil" {
+			t.Errorf("expected nil response error, got: %v", err)
+		}
+	}
+}
+
+// chunkWriter signals when the first write is received.
+type chunkWriter struct {
+	strings.Builder
+	first chan struct{}
+}
+
+func (w *chunkWriter) Write(p []byte) (int, error) {
+	if w.Len() == 0 {
+		close(w.first)
+	}
+	return w.Builder.Write(p)
+}
+
+func TestClient_GetInto(t *testing.T) {
+	var hits atomic.Int64
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte("not found"))
+			return
+		}
+
+		w.Header().Set("Cache-Control", "max-age=60")
+		_, _ = w.Write([]byte("first,"))
+		w.(http.Flusher).Flush()
+		<-release
+		_, _ = w.Write([]byte("second"))
+	}))
+	defer server.Close()
+
+	c := clink.NewClient(clink.WithMemoryCache(100, 1<<20), clink.WithRequestDeduplication())
+
+	w := &chunkWriter{first: make(chan struct{})}
+	go func() {
+		<-w.first
+		close(release)
+	}()
+
+	n, err := c.GetInto(context.Background(), server.URL, w)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if w.String() != "first,second" || n != int64(len("first,second")) {
+		t.Errorf("expected the body to be streamed to the writer, got %d bytes: %q", n, w.String())
+	}
+
+	_, _ = c.GetInto(context.Background(), server.URL, io.Discard)
+	if hits.Load() != 2 {
+		t.Errorf("expected unbuffered responses not to be cached, got %d hits", hits.Load())
+	}
+
+	var out strings.Builder
+	_, err = c.GetInto(context.Background(), server.URL+"/missing", &out)
+	var httpErr *clink.HTTPError
+	if !errors.As(err, &httpErr) || httpErr.StatusCode != http.StatusNotFound {
+		t.Errorf("expected an *HTTPError, got: %v", err)
+	}
+
+	if out.Len() != 0 {
+		t.Errorf("expected nothing to be written for a failure status, got: %q", out.String())
+	}
+}
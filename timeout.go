@@ -0,0 +1,47 @@
+package clink
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+)
+
+// WithTimeout sets an overall timeout for each call, covering rate limiter waits, all retry attempts
+// and the backoff between them. The timeout also applies to reading the response body, and is
+// released when the body is closed. A timeout of zero disables it.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		if timeout < 0 {
+			c.addConfigError("WithTimeout: timeout must not be negative, got %s", timeout)
+			return
+		}
+
+		c.Timeout = timeout
+	}
+}
+
+// withTimeout returns the request with the client timeout applied to its context, and the function
+// releasing it.
+func (c *Client) withTimeout(req *http.Request) (*http.Request, context.CancelFunc) {
+	if c.Timeout <= 0 {
+		return req, func() {}
+	}
+
+	ctx, cancel := context.WithTimeout(req.Context(), c.Timeout)
+
+	return req.WithContext(ctx), cancel
+}
+
+// cancelBody releases the context of a request when its response body is closed.
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+
+	return err
+}
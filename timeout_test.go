@@ -0,0 +1,79 @@
+package clink_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/davesavic/clink"
+)
+
+func TestWithTimeout(t *testing.T) {
+	testCases := []struct {
+		name       string
+		delay      time.Duration
+		opts       []clink.Option
+		resultFunc func(body []byte, err error, elapsed time.Duration) bool
+	}{
+		{
+			name:  "fails when server hangs",
+			delay: time.Second,
+			opts:  []clink.Option{clink.WithTimeout(50 * time.Millisecond)},
+			resultFunc: func(_ []byte, err error, elapsed time.Duration) bool {
+				return errors.Is(err, context.DeadlineExceeded) && elapsed < 500*time.Millisecond
+			},
+		},
+		{
+			name:  "covers all retry attempts",
+			delay: 30 * time.Millisecond,
+			opts: []clink.Option{
+				clink.WithTimeout(100 * time.Millisecond),
+				clink.WithRetries(5, func(_ *http.Request, _ *http.Response, _ error) bool { return true }),
+			},
+			resultFunc: func(_ []byte, err error, elapsed time.Duration) bool {
+				return errors.Is(err, context.DeadlineExceeded) && elapsed < 500*time.Millisecond
+			},
+		},
+		{
+			name:  "body can be read after a fast response",
+			delay: 0,
+			opts:  []clink.Option{clink.WithTimeout(time.Second)},
+			resultFunc: func(body []byte, err error, _ time.Duration) bool {
+				return err == nil && string(body) == "ok"
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				select {
+				case <-time.After(tc.delay):
+				case <-r.Context().Done():
+					return
+				}
+				_, _ = w.Write([]byte("ok"))
+			}))
+			defer server.Close()
+
+			c := clink.NewClient(tc.opts...)
+
+			start := time.Now()
+			resp, err := c.Get(server.URL)
+
+			var body []byte
+			if err == nil {
+				body, err = io.ReadAll(resp.Body)
+				_ = resp.Body.Close()
+			}
+
+			if !tc.resultFunc(body, err, time.Since(start)) {
+				t.Errorf("unexpected result: %q, %v after %v", body, err, time.Since(start))
+			}
+		})
+	}
+}
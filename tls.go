@@ -0,0 +1,186 @@
+package clink
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"log/slog"
+	"net/http"
+	"os"
+	"sync"
+)
+
+// WithTLSConfig sets the TLS configuration of the client's own transport, for example to set
+// cipher suites, the server name or the minimum TLS version. The configuration is cloned and
+// merged with the one set by earlier options: root CAs, client certificates (including those of
+// WithClientCertFiles) and WithInsecureSkipTLSVerify are kept unless the configuration sets its own.
+// The option can't be used with WithClient or WithTransport.
+func WithTLSConfig(config *tls.Config) Option {
+	return func(c *Client) {
+		if config == nil {
+			c.addConfigError("WithTLSConfig: config must not be nil")
+			return
+		}
+
+		transport := c.ownTransport("WithTLSConfig")
+		if transport == nil {
+			return
+		}
+
+		merged := config.Clone()
+		if previous := transport.TLSClientConfig; previous != nil {
+			merged.InsecureSkipVerify = merged.InsecureSkipVerify || previous.InsecureSkipVerify
+			if merged.RootCAs == nil {
+				merged.RootCAs = previous.RootCAs
+			}
+			if len(merged.Certificates) == 0 && merged.GetClientCertificate == nil {
+				merged.Certificates = previous.Certificates
+				merged.GetClientCertificate = previous.GetClientCertificate
+			}
+		}
+		if merged.InsecureSkipVerify && c.insecureWarning == nil {
+			c.insecureWarning = &sync.Once{}
+		}
+
+		transport.TLSClientConfig = merged
+		if c.rootCAs != nil && merged.RootCAs == nil {
+			c.applyRootCAs(transport)
+		}
+	}
+}
+
+// WithRootCAs trusts the PEM encoded CA certificates, in addition to the system roots unless
+// WithSystemRoots(false) is used. The option can be used multiple times.
+// It can't be used with WithClient or WithTransport.
+func WithRootCAs(pemCerts ...[]byte) Option {
+	return func(c *Client) {
+		transport := c.ownTransport("WithRootCAs")
+		if transport == nil {
+			return
+		}
+
+		for _, pemCert := range pemCerts {
+			if !x509.NewCertPool().AppendCertsFromPEM(pemCert) {
+				c.addConfigError("no certificates found in root CA PEM data")
+				return
+			}
+		}
+
+		c.rootCAs = c.rootCAsConfig()
+		c.rootCAs.pemCerts = append(c.rootCAs.pemCerts, pemCerts...)
+		c.applyRootCAs(transport)
+	}
+}
+
+// WithRootCAFiles trusts the CA certificates in the PEM files at the given paths, like WithRootCAs.
+func WithRootCAFiles(paths ...string) Option {
+	return func(c *Client) {
+		pemCerts := make([][]byte, 0, len(paths))
+		for _, path := range paths {
+			pemCert, err := os.ReadFile(path)
+			if err != nil {
+				c.addConfigError("failed to read root CA file: %v", err)
+				return
+			}
+			pemCerts = append(pemCerts, pemCert)
+		}
+
+		WithRootCAs(pemCerts...)(c)
+	}
+}
+
+// WithSystemRoots sets whether the system root CAs are trusted along with the CAs added by WithRootCAs.
+// They are trusted by default.
+func WithSystemRoots(enabled bool) Option {
+	return func(c *Client) {
+		transport := c.ownTransport("WithSystemRoots")
+		if transport == nil {
+			return
+		}
+
+		c.rootCAs = c.rootCAsConfig()
+		c.rootCAs.withoutSystem = !enabled
+		c.applyRootCAs(transport)
+	}
+}
+
+// rootCAs holds the root CAs configured with WithRootCAs and WithSystemRoots.
+type rootCAs struct {
+	pemCerts      [][]byte
+	withoutSystem bool
+}
+
+func (c *Client) rootCAsConfig() *rootCAs {
+	if c.rootCAs == nil {
+		return &rootCAs{}
+	}
+
+	return c.rootCAs
+}
+
+// applyRootCAs sets the root CAs of the transport from the client configuration.
+func (c *Client) applyRootCAs(transport *http.Transport) {
+	pool := x509.NewCertPool()
+	if !c.rootCAs.withoutSystem {
+		if system, err := x509.SystemCertPool(); err == nil {
+			pool = system
+		}
+	}
+
+	for _, pemCert := range c.rootCAs.pemCerts {
+		pool.AppendCertsFromPEM(pemCert)
+	}
+
+	tlsConfig(transport).RootCAs = pool
+}
+
+// tlsConfig returns the TLS configuration of the transport, creating it if needed.
+func tlsConfig(transport *http.Transport) *tls.Config {
+	if transport.TLSClientConfig == nil {
+		transport.TLSClientConfig = &tls.Config{}
+	}
+
+	return transport.TLSClientConfig
+}
+
+// ErrInsecureScheme is returned when a request, or one of its redirects, doesn't use HTTPS on a
+// client created with WithHTTPSOnly.
+var ErrInsecureScheme = errors.New("request must use https")
+
+// WithHTTPSOnly refuses requests that don't use HTTPS, including redirects downgrading to plain
+// HTTP, so that credentials are never sent in cleartext even if a caller passes an http:// URL.
+// Refused requests fail with ErrInsecureScheme. When used with WithClient, it must come after it.
+func WithHTTPSOnly() Option {
+	return func(c *Client) {
+		c.httpsOnly = true
+		c.installRedirectHook("WithHTTPSOnly")
+	}
+}
+
+// WithInsecureSkipTLSVerify disables verification of server certificates and host names.
+// It makes connections vulnerable to interception and is only meant for local development against
+// self-signed endpoints; a warning is logged on the first request of a client using it.
+// The option can't be used with WithClient or WithTransport.
+func WithInsecureSkipTLSVerify() Option {
+	return func(c *Client) {
+		transport := c.ownTransport("WithInsecureSkipTLSVerify")
+		if transport == nil {
+			return
+		}
+
+		tlsConfig(transport).InsecureSkipVerify = true
+		c.insecureWarning = &sync.Once{}
+	}
+}
+
+// warnInsecureTLS logs, once, that the client doesn't verify server certificates.
+func (c *Client) warnInsecureTLS() {
+	c.insecureWarning.Do(func() {
+		logger := c.Logger
+		if logger == nil {
+			logger = slog.Default()
+		}
+
+		logger.Warn("TLS certificate verification is disabled, connections are vulnerable to interception")
+	})
+}
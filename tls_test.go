@@ -0,0 +1,238 @@
+package clink_test
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/davesavic/clink"
+)
+
+func newTLSServer(t *testing.T, config *tls.Config) (*httptest.Server, *x509.CertPool) {
+	t.Helper()
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+	server.TLS = config
+	server.StartTLS()
+	t.Cleanup(server.Close)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(server.Certificate())
+
+	return server, pool
+}
+
+func TestWithTLSConfig(t *testing.T) {
+	testCases := []struct {
+		name       string
+		server     *tls.Config
+		opts       []clink.Option
+		config     func(pool *x509.CertPool) *tls.Config
+		shouldFail bool
+	}{
+		{
+			name: "trusts configured roots",
+			config: func(pool *x509.CertPool) *tls.Config {
+				return &tls.Config{RootCAs: pool}
+			},
+		},
+		{
+			name: "fails without configured roots",
+			config: func(_ *x509.CertPool) *tls.Config {
+				return &tls.Config{}
+			},
+			shouldFail: true,
+		},
+		{
+			name:   "enforces minimum version",
+			server: &tls.Config{MaxVersion: tls.VersionTLS12},
+			config: func(pool *x509.CertPool) *tls.Config {
+				return &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS13}
+			},
+			shouldFail: true,
+		},
+		{
+			name: "keeps insecure skip verify",
+			opts: []clink.Option{clink.WithInsecureSkipTLSVerify()},
+			config: func(_ *x509.CertPool) *tls.Config {
+				return &tls.Config{MinVersion: tls.VersionTLS12}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server, pool := newTLSServer(t, tc.server)
+
+			opts := append(tc.opts, clink.WithTLSConfig(tc.config(pool)))
+			resp, err := clink.NewClient(opts...).Get(server.URL)
+			if err == nil {
+				_ = resp.Body.Close()
+			}
+
+			if (err != nil) != tc.shouldFail {
+				t.Errorf("expected failure: %v, got: %v", tc.shouldFail, err)
+			}
+		})
+	}
+}
+
+func TestWithTLSConfig_CustomTransport(t *testing.T) {
+	c := clink.NewClient(clink.WithClient(&http.Client{}), clink.WithTLSConfig(&tls.Config{}))
+
+	if _, err := c.Get("https://example.invalid"); !errors.Is(err, clink.ErrInvalidOption) {
+		t.Errorf("expected invalid option error, got: %v", err)
+	}
+}
+
+func TestWithTLSConfig_Nil(t *testing.T) {
+	c := clink.NewClient(clink.WithTLSConfig(nil))
+
+	if _, err := c.Get("https://example.invalid"); !errors.Is(err, clink.ErrInvalidOption) {
+		t.Errorf("expected invalid option error, got: %v", err)
+	}
+}
+
+func TestWithRootCAs(t *testing.T) {
+	server, _ := newTLSServer(t, nil)
+	serverPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+
+	certFile := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(certFile, serverPEM, 0o600); err != nil {
+		t.Fatalf("failed to write ca file: %v", err)
+	}
+
+	testCases := []struct {
+		name       string
+		opts       []clink.Option
+		resultFunc func(err error) bool
+	}{
+		{
+			name: "trusts pem certificates",
+			opts: []clink.Option{clink.WithRootCAs(serverPEM)},
+			resultFunc: func(err error) bool {
+				return err == nil
+			},
+		},
+		{
+			name: "trusts certificate files",
+			opts: []clink.Option{clink.WithRootCAFiles(certFile)},
+			resultFunc: func(err error) bool {
+				return err == nil
+			},
+		},
+		{
+			name: "trusts certificates without system roots",
+			opts: []clink.Option{clink.WithSystemRoots(false), clink.WithRootCAs(serverPEM)},
+			resultFunc: func(err error) bool {
+				return err == nil
+			},
+		},
+		{
+			name: "keeps certificates when tls config is set later",
+			opts: []clink.Option{clink.WithRootCAs(serverPEM), clink.WithTLSConfig(&tls.Config{MinVersion: tls.VersionTLS12})},
+			resultFunc: func(err error) bool {
+				return err == nil
+			},
+		},
+		{
+			name: "rejects invalid pem data",
+			opts: []clink.Option{clink.WithRootCAs([]byte("not a certificate"))},
+			resultFunc: func(err error) bool {
+				return errors.Is(err, clink.ErrInvalidOption)
+			},
+		},
+		{
+			name: "rejects missing files",
+			opts: []clink.Option{clink.WithRootCAFiles(filepath.Join(t.TempDir(), "missing.pem"))},
+			resultFunc: func(err error) bool {
+				return errors.Is(err, clink.ErrInvalidOption)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, err := clink.NewClient(tc.opts...).Get(server.URL)
+			if err == nil {
+				_ = resp.Body.Close()
+			}
+
+			if !tc.resultFunc(err) {
+				t.Errorf("unexpected result: %v", err)
+			}
+		})
+	}
+}
+
+func TestWithInsecureSkipTLSVerify(t *testing.T) {
+	server, _ := newTLSServer(t, nil)
+
+	var buf bytes.Buffer
+	c := clink.NewClient(
+		clink.WithInsecureSkipTLSVerify(),
+		clink.WithLogger(slog.New(slog.NewTextHandler(&buf, nil))),
+	)
+
+	for i := 0; i < 2; i++ {
+		resp, err := c.Get(server.URL)
+		if err != nil {
+			t.Fatalf("failed to make request: %v", err)
+		}
+		_ = resp.Body.Close()
+	}
+
+	if count := strings.Count(buf.String(), "TLS certificate verification is disabled"); count != 1 {
+		t.Errorf("expected one warning, got %d: %s", count, buf.String())
+	}
+}
+
+func TestWithHTTPSOnly(t *testing.T) {
+	plain := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("plain"))
+	}))
+	defer plain.Close()
+
+	secure := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if to := r.URL.Query().Get("to"); to != "" {
+			http.Redirect(w, r, to, http.StatusFound)
+		}
+	}))
+	defer secure.Close()
+
+	testCases := []struct {
+		name     string
+		url      string
+		insecure bool
+	}{
+		{name: "https", url: secure.URL},
+		{name: "plain http", url: plain.URL, insecure: true},
+		{name: "downgrading redirect", url: secure.URL + "?to=" + plain.URL, insecure: true},
+	}
+
+	c := clink.NewClient(clink.WithClient(secure.Client()), clink.WithHTTPSOnly())
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, err := c.Get(tc.url)
+			if err == nil {
+				_ = resp.Body.Close()
+			}
+
+			if errors.Is(err, clink.ErrInsecureScheme) != tc.insecure {
+				t.Errorf("expected insecure to be %v, got: %v", tc.insecure, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package clink
+
+import (
+	"crypto/tls"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// Timings is the latency breakdown of a request, captured with net/http/httptrace.
+// Phases that didn't happen, such as DNS and connect on a reused connection, are zero.
+type Timings struct {
+	// DNS is the time spent resolving the host name.
+	DNS time.Duration
+	// Connect is the time spent establishing the TCP connection.
+	Connect time.Duration
+	// TLS is the time spent on the TLS handshake.
+	TLS time.Duration
+	// TTFB is the time from the request being written to the first response byte.
+	TTFB time.Duration
+	// Download is the time from the first response byte to the end of the body.
+	Download time.Duration
+	// Total is the time from requesting a connection to the end of the body.
+	Total time.Duration
+	// ConnReused reports whether the request was sent on a reused connection.
+	ConnReused bool
+	// RemoteAddr is the address of the server the request was sent to.
+	RemoteAddr string
+}
+
+// WithTracing captures the latency breakdown of each request, available from Response.Timings.
+// If fn is not nil, it is called with the timings once the response body has been read or closed,
+// or when the request fails. Only the final attempt of a retried request is measured.
+func WithTracing(fn func(*http.Request, Timings)) Option {
+	return func(c *Client) {
+		c.Tracing = true
+		c.TraceFunc = fn
+	}
+}
+
+// Timings returns the latency breakdown of the request if tracing is enabled.
+// Download and Total are only set once the body has been read or closed.
+func (r *Response) Timings() Timings {
+	if r.trace == nil {
+		return Timings{}
+	}
+
+	return r.trace.timings()
+}
+
+// requestTrace records the httptrace events of a request.
+type requestTrace struct {
+	mu     sync.Mutex
+	once   sync.Once
+	events traceEvents
+}
+
+// traceEvents are the times of the events of a single attempt.
+type traceEvents struct {
+	getConn                   time.Time
+	dnsStart, dnsDone         time.Time
+	connectStart, connectDone time.Time
+	tlsStart, tlsDone         time.Time
+	wroteRequest, firstByte   time.Time
+	done                      time.Time
+	reused                    bool
+	remoteAddr                string
+}
+
+// withTrace returns the request with a trace attached to its context.
+func withTrace(req *http.Request) (*http.Request, *requestTrace) {
+	t := &requestTrace{}
+
+	ctx := httptrace.WithClientTrace(req.Context(), &httptrace.ClientTrace{
+		GetConn: func(string) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			// A new attempt starts, so discard the events of the previous one.
+			t.events = traceEvents{getConn: time.Now()}
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.events.reused = info.Reused
+			t.events.remoteAddr = info.Conn.RemoteAddr().String()
+		},
+		DNSStart:             func(httptrace.DNSStartInfo) { t.record(&t.events.dnsStart) },
+		DNSDone:              func(httptrace.DNSDoneInfo) { t.record(&t.events.dnsDone) },
+		ConnectStart:         func(string, string) { t.record(&t.events.connectStart) },
+		ConnectDone:          func(string, string, error) { t.record(&t.events.connectDone) },
+		TLSHandshakeStart:    func() { t.record(&t.events.tlsStart) },
+		TLSHandshakeDone:     func(tls.ConnectionState, error) { t.record(&t.events.tlsDone) },
+		WroteRequest:         func(httptrace.WroteRequestInfo) { t.record(&t.events.wroteRequest) },
+		GotFirstResponseByte: func() { t.record(&t.events.firstByte) },
+	})
+
+	return req.WithContext(ctx), t
+}
+
+func (t *requestTrace) record(at *time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	*at = time.Now()
+}
+
+func (t *requestTrace) timings() Timings {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	e := t.events
+	return Timings{
+		DNS:        since(e.dnsStart, e.dnsDone),
+		Connect:    since(e.connectStart, e.connectDone),
+		TLS:        since(e.tlsStart, e.tlsDone),
+		TTFB:       since(e.wroteRequest, e.firstByte),
+		Download:   since(e.firstByte, e.done),
+		Total:      since(e.getConn, e.done),
+		ConnReused: e.reused,
+		RemoteAddr: e.remoteAddr,
+	}
+}
+
+// finish records the end of the request and calls fn with the timings, once.
+func (t *requestTrace) finish(req *http.Request, fn func(*http.Request, Timings)) {
+	t.once.Do(func() {
+		t.record(&t.events.done)
+		if fn != nil {
+			fn(req, t.timings())
+		}
+	})
+}
+
+// since returns the duration between start and end, or zero if either is unset.
+func since(start, end time.Time) time.Duration {
+	if start.IsZero() || end.IsZero() {
+		return 0
+	}
+
+	return end.Sub(start)
+}
+
+// tracedBody finishes the trace when the body reaches EOF or is closed.
+type tracedBody struct {
+	io.ReadCloser
+	finish func()
+}
+
+func (b *tracedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if err == io.EOF {
+		b.finish()
+	}
+
+	return n, err
+}
+
+func (b *tracedBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.finish()
+
+	return err
+}
@@ -0,0 +1,94 @@
+package clink_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/davesavic/clink"
+)
+
+func TestWithTracing(t *testing.T) {
+	testCases := []struct {
+		name       string
+		tls        bool
+		resultFunc func(resp *clink.Response, callback []clink.Timings) bool
+	}{
+		{
+			name: "captures timings of a plain request",
+			resultFunc: func(resp *clink.Response, callback []clink.Timings) bool {
+				timings := resp.Timings()
+				return timings.Connect > 0 && timings.TTFB >= 20*time.Millisecond &&
+					timings.TLS == 0 && timings.Total >= timings.TTFB &&
+					len(callback) == 1 && callback[0] == timings
+			},
+		},
+		{
+			name: "captures tls handshake",
+			tls:  true,
+			resultFunc: func(resp *clink.Response, callback []clink.Timings) bool {
+				return resp.Timings().TLS > 0 && len(callback) == 1
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				time.Sleep(20 * time.Millisecond)
+				_, _ = w.Write([]byte("ok"))
+			})
+
+			var server *httptest.Server
+			if tc.tls {
+				server = httptest.NewTLSServer(handler)
+			} else {
+				server = httptest.NewServer(handler)
+			}
+			defer server.Close()
+
+			var callback []clink.Timings
+			c := clink.NewClient(
+				clink.WithClient(server.Client()),
+				clink.WithTracing(func(_ *http.Request, timings clink.Timings) {
+					callback = append(callback, timings)
+				}),
+			)
+
+			req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+			resp, err := c.DoWrapped(req)
+			if err != nil {
+				t.Fatalf("failed to make request: %v", err)
+			}
+
+			if len(callback) != 0 {
+				t.Errorf("expected callback to wait for the body to be read")
+			}
+
+			if _, err := resp.Bytes(); err != nil {
+				t.Fatalf("failed to read body: %v", err)
+			}
+
+			if !tc.resultFunc(resp, callback) {
+				t.Errorf("unexpected timings: %+v, callback: %+v", resp.Timings(), callback)
+			}
+		})
+	}
+}
+
+func TestResponse_TimingsWithoutTracing(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {}))
+	defer server.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	resp, err := clink.NewClient().DoWrapped(req)
+	if err != nil {
+		t.Fatalf("failed to make request: %v", err)
+	}
+	_ = resp.Body.Close()
+
+	if resp.Timings() != (clink.Timings{}) {
+		t.Errorf("expected zero timings, got: %+v", resp.Timings())
+	}
+}
@@ -0,0 +1,155 @@
+package clink
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Default settings of the transport built by NewClient. They are not used when the client is
+// created with WithClient.
+const (
+	DefaultDialTimeout           = 10 * time.Second
+	DefaultKeepAlive             = 30 * time.Second
+	DefaultTLSHandshakeTimeout   = 10 * time.Second
+	DefaultResponseHeaderTimeout = 30 * time.Second
+	DefaultExpectContinueTimeout = 1 * time.Second
+	DefaultIdleConnTimeout       = 90 * time.Second
+	DefaultMaxIdleConns          = 100
+	DefaultMaxIdleConnsPerHost   = 10
+)
+
+// newTransport returns a dedicated transport with the default settings whose connections,
+// dialed with dial, are counted in openConns.
+func newTransport(dial dialFunc, openConns *atomic.Int64) *http.Transport {
+	return &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           trackConns(dial, openConns),
+		ForceAttemptHTTP2:     true,
+		TLSHandshakeTimeout:   DefaultTLSHandshakeTimeout,
+		ResponseHeaderTimeout: DefaultResponseHeaderTimeout,
+		ExpectContinueTimeout: DefaultExpectContinueTimeout,
+		IdleConnTimeout:       DefaultIdleConnTimeout,
+		MaxIdleConns:          DefaultMaxIdleConns,
+		MaxIdleConnsPerHost:   DefaultMaxIdleConnsPerHost,
+	}
+}
+
+func newDialer() *net.Dialer {
+	return &net.Dialer{
+		Timeout:   DefaultDialTimeout,
+		KeepAlive: DefaultKeepAlive,
+	}
+}
+
+// dialFunc dials a connection, like net.Dialer.DialContext.
+type dialFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+// trackConns wraps the dial function to count the open connections it dials in openConns.
+func trackConns(dial dialFunc, openConns *atomic.Int64) dialFunc {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		conn, err := dial(ctx, network, addr)
+		if err != nil {
+			return nil, err
+		}
+
+		openConns.Add(1)
+		return &trackedConn{Conn: conn, openConns: openConns}, nil
+	}
+}
+
+// trackedConn decrements the open connection count when it is closed.
+type trackedConn struct {
+	net.Conn
+	openConns *atomic.Int64
+	closeOnce sync.Once
+}
+
+func (c *trackedConn) Close() error {
+	c.closeOnce.Do(func() {
+		c.openConns.Add(-1)
+	})
+
+	return c.Conn.Close()
+}
+
+// WithTransport sets the transport used to send requests, replacing the client's own transport
+// and its default timeouts. The http.Client is copied, so a client passed to WithClient is not modified.
+// Like WithClient, it can't be used after options configuring the client's own transport.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(c *Client) {
+		c.replaceTransport("WithTransport")
+
+		client := *c.HttpClient
+		client.Transport = transport
+
+		c.HttpClient = &client
+		c.dialer = nil
+		c.transport = nil
+	}
+}
+
+// ownTransport returns the client's own transport, or records a configuration error for the
+// named option and returns nil if the client uses a custom transport.
+func (c *Client) ownTransport(option string) *http.Transport {
+	if c.transport == nil {
+		c.addConfigError("%s cannot be used with a custom client or transport", option)
+		return nil
+	}
+
+	if !slices.Contains(c.transportOptions, option) {
+		c.transportOptions = append(slices.Clip(c.transportOptions), option)
+	}
+
+	return c.transport
+}
+
+// replaceTransport records a configuration error for the named option replacing the client's own
+// transport if earlier options configured it, since their settings, such as WithSSRFProtection,
+// would be silently dropped.
+func (c *Client) replaceTransport(option string) {
+	if len(c.transportOptions) > 0 {
+		c.addConfigError("%s cannot be used after %s", option, strings.Join(c.transportOptions, ", "))
+	}
+}
+
+// WithHTTP2 sets whether the client's own transport negotiates HTTP/2 with servers supporting it.
+// It is enabled by default; disabling it forces HTTP/1.1, which some middleboxes require.
+// The option can't be used with WithClient or WithTransport.
+func WithHTTP2(enabled bool) Option {
+	return func(c *Client) {
+		transport := c.ownTransport("WithHTTP2")
+		if transport == nil {
+			return
+		}
+
+		transport.ForceAttemptHTTP2 = enabled
+		if enabled {
+			transport.TLSNextProto = nil
+		} else {
+			// A non-nil empty map disables HTTP/2.
+			transport.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+		}
+	}
+}
+
+// WithUnixSocket dials every connection to the Unix domain socket at the given path, for services
+// such as the Docker daemon. Request URLs keep a logical host (http://docker/v1.43/info), which is
+// sent in the Host header. Proxies are not used. The option can't be used with WithClient or WithTransport.
+func WithUnixSocket(path string) Option {
+	return func(c *Client) {
+		transport := c.ownTransport("WithUnixSocket")
+		if transport == nil {
+			return
+		}
+
+		transport.Proxy = nil
+		c.unixSocket = path
+	}
+}
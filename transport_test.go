@@ -0,0 +1,196 @@
+package clink_test
+
+import (
+	"encoding/pem"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/davesavic/clink"
+)
+
+func TestNewClient_Transport(t *testing.T) {
+	testCases := []struct {
+		name       string
+		opts       []clink.Option
+		resultFunc func(*clink.Client) bool
+	}{
+		{
+			name: "uses a dedicated client and transport",
+			resultFunc: func(c *clink.Client) bool {
+				transport, ok := c.HttpClient.Transport.(*http.Transport)
+				return c.HttpClient != http.DefaultClient && ok && transport != http.DefaultTransport
+			},
+		},
+		{
+			name: "transport has default timeouts",
+			resultFunc: func(c *clink.Client) bool {
+				transport := c.HttpClient.Transport.(*http.Transport)
+				return transport.TLSHandshakeTimeout == clink.DefaultTLSHandshakeTimeout &&
+					transport.ResponseHeaderTimeout == clink.DefaultResponseHeaderTimeout &&
+					transport.IdleConnTimeout == clink.DefaultIdleConnTimeout &&
+					transport.MaxIdleConnsPerHost == clink.DefaultMaxIdleConnsPerHost
+			},
+		},
+		{
+			name: "with client replaces the transport",
+			opts: []clink.Option{clink.WithClient(http.DefaultClient)},
+			resultFunc: func(c *clink.Client) bool {
+				return c.HttpClient == http.DefaultClient
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if !tc.resultFunc(clink.NewClient(tc.opts...)) {
+				t.Errorf("unexpected client configuration")
+			}
+		})
+	}
+}
+
+func TestClient_StatsOpenConnections(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	c := clink.NewClient()
+
+	resp, err := c.Get(server.URL)
+	if err != nil {
+		t.Fatalf("failed to make request: %v", err)
+	}
+	_, _ = io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+
+	if open := c.Stats().OpenConnections; open != 1 {
+		t.Errorf("expected 1 open connection, got: %d", open)
+	}
+
+	c.HttpClient.CloseIdleConnections()
+
+	if open := c.Stats().OpenConnections; open != 0 {
+		t.Errorf("expected 0 open connections after closing idle connections, got: %d", open)
+	}
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestWithTransport(t *testing.T) {
+	var called bool
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		called = true
+		return &http.Response{StatusCode: http.StatusTeapot, Body: http.NoBody, Request: req}, nil
+	})
+
+	shared := &http.Client{}
+	c := clink.NewClient(clink.WithClient(shared), clink.WithTransport(transport))
+
+	resp, err := c.Get("http://example.invalid")
+	if err != nil {
+		t.Fatalf("failed to make request: %v", err)
+	}
+
+	if !called || resp.StatusCode != http.StatusTeapot {
+		t.Errorf("expected custom transport to be used")
+	}
+
+	if shared.Transport != nil {
+		t.Errorf("expected shared client to be unchanged")
+	}
+}
+
+func TestWithHTTP2(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.Proto))
+	}))
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	defer server.Close()
+
+	serverPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+
+	testCases := []struct {
+		name     string
+		opts     []clink.Option
+		expected string
+	}{
+		{
+			name:     "negotiates http/2 by default",
+			expected: "HTTP/2.0",
+		},
+		{
+			name:     "falls back to http/1.1 when disabled",
+			opts:     []clink.Option{clink.WithHTTP2(false)},
+			expected: "HTTP/1.1",
+		},
+		{
+			name:     "can be enabled again",
+			opts:     []clink.Option{clink.WithHTTP2(false), clink.WithHTTP2(true)},
+			expected: "HTTP/2.0",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := clink.NewClient(append(tc.opts, clink.WithRootCAs(serverPEM))...)
+
+			resp, err := c.Get(server.URL)
+			if err != nil {
+				t.Fatalf("failed to make request: %v", err)
+			}
+
+			body, _ := io.ReadAll(resp.Body)
+			_ = resp.Body.Close()
+
+			if string(body) != tc.expected {
+				t.Errorf("expected %s, got: %s", tc.expected, body)
+			}
+		})
+	}
+}
+
+func TestWithUnixSocket(t *testing.T) {
+	dir, err := os.MkdirTemp("", "clink")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	socket := filepath.Join(dir, "api.sock")
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatalf("failed to listen on unix socket: %v", err)
+	}
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.Host + r.URL.Path))
+	}))
+	server.Listener = listener
+	server.Start()
+	defer server.Close()
+
+	c := clink.NewClient(clink.WithUnixSocket(socket))
+
+	resp, err := c.Get("http://docker/v1.43/info")
+	if err != nil {
+		t.Fatalf("failed to make request: %v", err)
+	}
+
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+
+	if string(body) != "docker/v1.43/info" {
+		t.Errorf("expected request to reach socket with logical host, got: %s", body)
+	}
+}
@@ -0,0 +1,224 @@
+package clink
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ErrBodyConsumed is returned when a single-use body source (see ReaderBody) is opened again.
+var ErrBodyConsumed = errors.New("request body has already been consumed")
+
+// BodySource is a request body that is opened for each attempt of a request, so that streamed
+// uploads can be retried. Open must return the body from its start.
+type BodySource interface {
+	Open() (io.ReadCloser, error)
+}
+
+// BodySourceFunc adapts a function to a BodySource.
+type BodySourceFunc func() (io.ReadCloser, error)
+
+func (f BodySourceFunc) Open() (io.ReadCloser, error) {
+	return f()
+}
+
+// StreamBody sends the request body from the source as it is read, with chunked transfer encoding,
+// instead of buffering it in memory. The body of the request itself is ignored.
+func StreamBody(source BodySource) RequestOption {
+	return func(o *requestOptions) {
+		o.bodySource = source
+	}
+}
+
+// FileBody returns a BodySource reading the file at path.
+func FileBody(path string) BodySource {
+	return fileBody(path)
+}
+
+type fileBody string
+
+func (f fileBody) Open() (io.ReadCloser, error) {
+	return os.Open(string(f))
+}
+
+// size returns the size of the file, for WithMaxRequestBytes.
+func (f fileBody) size() (int64, error) {
+	info, err := os.Stat(string(f))
+	if err != nil {
+		return 0, err
+	}
+
+	return info.Size(), nil
+}
+
+// ReaderBody returns a single-use BodySource reading r, for bodies of unknown length that can't be
+// read again. Requests using it are not retried.
+func ReaderBody(r io.Reader) BodySource {
+	return &readerBody{r: r}
+}
+
+type readerBody struct {
+	r    io.Reader
+	once sync.Once
+}
+
+func (b *readerBody) Open() (io.ReadCloser, error) {
+	var body io.ReadCloser
+	b.once.Do(func() {
+		body = io.NopCloser(b.r)
+	})
+
+	if body == nil {
+		return nil, ErrBodyConsumed
+	}
+
+	return body, nil
+}
+
+// BodyWriter returns a BodySource whose content is written by write, called in a new goroutine for
+// each attempt. Writes are buffered and sent as a chunk when the buffer is full or when the writer
+// is flushed, so that write controls when data is sent. The body ends when write returns, and an
+// error returned by write aborts the request.
+func BodyWriter(write func(w *bufio.Writer) error) BodySource {
+	return BodySourceFunc(func() (io.ReadCloser, error) {
+		pr, pw := io.Pipe()
+
+		go func() {
+			w := bufio.NewWriter(pw)
+			err := write(w)
+			if err == nil {
+				err = w.Flush()
+			}
+			_ = pw.CloseWithError(err)
+		}()
+
+		return pr, nil
+	})
+}
+
+// openBody sets the body of the request to a new reader of the source for the next attempt.
+func (c *Client) openBody(req *http.Request, source BodySource) error {
+	body, err := source.Open()
+	if err != nil {
+		return err
+	}
+
+	if c.MaxRequestBytes > 0 {
+		limit := c.MaxRequestBytes
+		body = &limitedBody{ReadCloser: body, remaining: limit, tooLarge: &RequestTooLargeError{Limit: limit}}
+	}
+
+	req.Body = &countingBody{ReadCloser: body, count: &c.stats.bytesSent}
+	req.ContentLength = -1
+	req.GetBody = source.Open
+
+	return nil
+}
+
+// UploadFile sends a POST request to the URL with a multipart/form-data body holding the file at
+// path in the fieldName field, and the extra fields. The file is streamed without being loaded in
+// memory, and is read again if the request is retried. Its Content-Type is detected from its
+// extension, or from its content if the extension is unknown.
+func (c *Client) UploadFile(ctx context.Context, url, fieldName, path string, extraFields map[string]string) (*http.Response, error) {
+	contentType, err := detectFileContentType(path)
+	if err != nil {
+		return nil, err
+	}
+
+	boundary := multipart.NewWriter(io.Discard).Boundary()
+	source := BodyWriter(func(w *bufio.Writer) error {
+		return writeMultipartFile(w, boundary, fieldName, path, contentType, extraFields)
+	})
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "multipart/form-data; boundary="+boundary)
+
+	return c.Do(ConfigureRequest(req, StreamBody(source)))
+}
+
+func writeMultipartFile(w io.Writer, boundary, fieldName, path, contentType string, extraFields map[string]string) error {
+	mw := multipart.NewWriter(w)
+	if err := mw.SetBoundary(boundary); err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(extraFields))
+	for name := range extraFields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if err := mw.WriteField(name, extraFields[name]); err != nil {
+			return fmt.Errorf("failed to write form field: %w", err)
+		}
+	}
+
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`,
+		escapeQuotes(fieldName), escapeQuotes(filepath.Base(path))))
+	header.Set("Content-Type", contentType)
+
+	part, err := mw.CreatePart(header)
+	if err != nil {
+		return fmt.Errorf("failed to write form file: %w", err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open file: %w", err)
+	}
+	defer func(file *os.File) {
+		_ = file.Close()
+	}(file)
+
+	if _, err := io.Copy(part, file); err != nil {
+		return fmt.Errorf("failed to write form file: %w", err)
+	}
+
+	return mw.Close()
+}
+
+// detectFileContentType returns the media type of the file from its extension, or by sniffing
+// its first bytes.
+func detectFileContentType(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open file: %w", err)
+	}
+	defer func(file *os.File) {
+		_ = file.Close()
+	}(file)
+
+	if contentType := mime.TypeByExtension(filepath.Ext(path)); contentType != "" {
+		return contentType, nil
+	}
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return "", fmt.Errorf("failed to read file: %w", err)
+	}
+
+	return http.DetectContentType(head[:n]), nil
+}
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+func escapeQuotes(s string) string {
+	return quoteEscaper.Replace(s)
+}
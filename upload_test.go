@@ -0,0 +1,256 @@
+package clink_test
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/davesavic/clink"
+)
+
+func TestStreamBody(t *testing.T) {
+	retryOn503 := func(_ *http.Request, resp *http.Response, err error) bool {
+		return err == nil && resp.StatusCode == http.StatusServiceUnavailable
+	}
+
+	path := filepath.Join(t.TempDir(), "upload.txt")
+	if err := os.WriteFile(path, []byte("file content"), 0o600); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	testCases := []struct {
+		name       string
+		source     clink.BodySource
+		resultFunc func(*testing.T, *http.Response, error, []string, []string)
+	}{
+		{
+			name:   "file body is reopened for retries",
+			source: clink.FileBody(path),
+			resultFunc: func(t *testing.T, resp *http.Response, err error, bodies, encodings []string) {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+
+				if resp.StatusCode != http.StatusOK {
+					t.Errorf("expected status 200 after the retry, got: %d", resp.StatusCode)
+				}
+
+				if len(bodies) != 2 || bodies[0] != "file content" || bodies[1] != "file content" {
+					t.Errorf("expected the whole body for each attempt, got: %q", bodies)
+				}
+
+				if encodings[0] != "chunked" {
+					t.Errorf("expected chunked transfer encoding, got: %q", encodings[0])
+				}
+			},
+		},
+		{
+			name:   "reader body is not retried",
+			source: clink.ReaderBody(strings.NewReader("reader content")),
+			resultFunc: func(t *testing.T, resp *http.Response, err error, bodies, _ []string) {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+
+				if resp.StatusCode != http.StatusServiceUnavailable {
+					t.Errorf("expected the status of the single attempt, got: %d", resp.StatusCode)
+				}
+
+				if len(bodies) != 1 || bodies[0] != "reader content" {
+					t.Errorf("expected a single attempt with the body, got: %q", bodies)
+				}
+			},
+		},
+		{
+			name: "body writer",
+			source: clink.BodyWriter(func(w *bufio.Writer) error {
+				_, err := w.WriteString("written content")
+				return err
+			}),
+			resultFunc: func(t *testing.T, resp *http.Response, err error, bodies, _ []string) {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+
+				if len(bodies) != 2 || bodies[1] != "written content" {
+					t.Errorf("expected the written body for each attempt, got: %q", bodies)
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var bodies, encodings []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+
+				mu.Lock()
+				defer mu.Unlock()
+				bodies = append(bodies, string(body))
+				encodings = append(encodings, strings.Join(r.TransferEncoding, ","))
+				if len(bodies) == 1 {
+					w.WriteHeader(http.StatusServiceUnavailable)
+				}
+			}))
+			defer server.Close()
+
+			c := clink.NewClient(clink.WithRetries(1, retryOn503))
+
+			req, _ := http.NewRequest(http.MethodPut, server.URL, nil)
+			resp, err := c.Do(clink.ConfigureRequest(req, clink.StreamBody(tc.source)))
+			if resp != nil {
+				_ = resp.Body.Close()
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			tc.resultFunc(t, resp, err, bodies, encodings)
+		})
+	}
+}
+
+func TestBodyWriter_Flush(t *testing.T) {
+	firstChunk := make(chan string)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		buf := make([]byte, 64)
+		n, _ := r.Body.Read(buf)
+		firstChunk <- string(buf[:n])
+		_, _ = io.Copy(io.Discard, r.Body)
+	}))
+	defer server.Close()
+
+	c := clink.NewClient()
+	source := clink.BodyWriter(func(w *bufio.Writer) error {
+		_, _ = w.WriteString("first")
+		if err := w.Flush(); err != nil {
+			return err
+		}
+
+		// The server must receive the flushed chunk before the rest is written.
+		if chunk := <-firstChunk; chunk != "first" {
+			return errors.New("unexpected first chunk: " + chunk)
+		}
+
+		_, err := w.WriteString("second")
+		return err
+	})
+
+	req, _ := http.NewRequest(http.MethodPost, server.URL, nil)
+	resp, err := c.Do(clink.ConfigureRequest(req, clink.StreamBody(source)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_ = resp.Body.Close()
+
+	if sent := c.Stats().BytesSent; sent != int64(len("firstsecond")) {
+		t.Errorf("expected the streamed bytes to be counted, got: %d", sent)
+	}
+}
+
+func TestClient_UploadFile(t *testing.T) {
+	dir := t.TempDir()
+	writeFile := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+		return path
+	}
+
+	testCases := []struct {
+		name       string
+		path       string
+		resultFunc func(*testing.T, *http.Request, string, error)
+	}{
+		{
+			name: "content type from extension",
+			path: writeFile("report.json", `{"ok":true}`),
+			resultFunc: func(t *testing.T, r *http.Request, content string, err error) {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+
+				if r.MultipartForm.File["document"][0].Filename != "report.json" {
+					t.Errorf("unexpected filename: %s", r.MultipartForm.File["document"][0].Filename)
+				}
+
+				if ct := r.MultipartForm.File["document"][0].Header.Get("Content-Type"); ct != "application/json" {
+					t.Errorf("expected application/json, got: %s", ct)
+				}
+
+				if content != `{"ok":true}` {
+					t.Errorf("unexpected file content: %s", content)
+				}
+
+				if r.FormValue("title") != "Q3" || r.FormValue("owner") != "ann" {
+					t.Errorf("expected the extra fields, got: %v", r.MultipartForm.Value)
+				}
+			},
+		},
+		{
+			name: "content type from content",
+			path: writeFile("page", "<html><body>hello</body></html>"),
+			resultFunc: func(t *testing.T, r *http.Request, _ string, err error) {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+
+				if ct := r.MultipartForm.File["document"][0].Header.Get("Content-Type"); !strings.HasPrefix(ct, "text/html") {
+					t.Errorf("expected text/html, got: %s", ct)
+				}
+			},
+		},
+		{
+			name: "missing file",
+			path: filepath.Join(dir, "missing.txt"),
+			resultFunc: func(t *testing.T, r *http.Request, _ string, err error) {
+				if err == nil {
+					t.Error("expected an error")
+				}
+
+				if r != nil {
+					t.Error("expected no request to be sent")
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var received *http.Request
+			var content string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if err := r.ParseMultipartForm(1 << 20); err != nil {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+
+				file, _, err := r.FormFile("document")
+				if err == nil {
+					data, _ := io.ReadAll(file)
+					content = string(data)
+				}
+				received = r
+			}))
+			defer server.Close()
+
+			resp, err := clink.NewClient().UploadFile(context.Background(), server.URL, "document", tc.path,
+				map[string]string{"title": "Q3", "owner": "ann"})
+			if resp != nil {
+				_ = resp.Body.Close()
+			}
+
+			tc.resultFunc(t, received, content, err)
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package clink
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ErrInvalidPathSegment is returned when a path segment would traverse the URL path.
+var ErrInvalidPathSegment = errors.New("invalid path segment")
+
+// JoinURL appends the given path segments to the base URL.
+// Each segment is escaped, so characters such as "/", "?" and "#" cannot change the structure of the URL,
+// and segments equal to "." or ".." are rejected to prevent path traversal.
+// Empty segments are skipped and duplicate slashes between the base and the segments are removed.
+func JoinURL(base string, segments ...string) (string, error) {
+	u, err := url.Parse(base)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse base url: %w", err)
+	}
+
+	joined, err := joinURL(u, segments)
+	if err != nil {
+		return "", err
+	}
+
+	return joined.String(), nil
+}
+
+// WithBaseURL sets the base URL for the client.
+// Requests with a relative URL (for example client.Get("/users")) are resolved against the base URL.
+func WithBaseURL(base string) Option {
+	return func(c *Client) {
+		c.BaseURL = base
+	}
+}
+
+func joinURL(base *url.URL, segments []string) (*url.URL, error) {
+	u := *base
+	rawPath := strings.TrimRight(u.EscapedPath(), "/")
+
+	for _, segment := range segments {
+		if segment == "" {
+			continue
+		}
+
+		if segment == "." || segment == ".." {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidPathSegment, segment)
+		}
+
+		rawPath += "/" + url.PathEscape(segment)
+	}
+
+	path, err := url.PathUnescape(rawPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unescape path: %w", err)
+	}
+
+	u.Path = path
+	u.RawPath = rawPath
+
+	return &u, nil
+}
+
+// resolveURL resolves a relative request URL against the base URL.
+func resolveURL(base string, ref *url.URL) (*url.URL, error) {
+	u, err := url.Parse(base)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse base url: %w", err)
+	}
+
+	var segments []string
+	for _, segment := range strings.Split(ref.EscapedPath(), "/") {
+		segment, err = url.PathUnescape(segment)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unescape path: %w", err)
+		}
+		segments = append(segments, segment)
+	}
+
+	resolved, err := joinURL(u, segments)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.HasSuffix(ref.Path, "/") {
+		resolved.Path += "/"
+		resolved.RawPath += "/"
+	}
+
+	switch {
+	case resolved.RawQuery == "":
+		resolved.RawQuery = ref.RawQuery
+	case ref.RawQuery != "":
+		resolved.RawQuery += "&" + ref.RawQuery
+	}
+	resolved.Fragment = ref.Fragment
+
+	return resolved, nil
+}
@@ -0,0 +1,109 @@
+package clink_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/davesavic/clink"
+)
+
+func TestJoinURL(t *testing.T) {
+	testCases := []struct {
+		name     string
+		base     string
+		segments []string
+		expected string
+		err      error
+	}{
+		{
+			name:     "joins segments",
+			base:     "https://api.example.com/v1",
+			segments: []string{"users", "123"},
+			expected: "https://api.example.com/v1/users/123",
+		},
+		{
+			name:     "removes duplicate slashes",
+			base:     "https://api.example.com/v1/",
+			segments: []string{"", "users"},
+			expected: "https://api.example.com/v1/users",
+		},
+		{
+			name:     "escapes segments",
+			base:     "https://api.example.com",
+			segments: []string{"files", "a/b?c#d", "hello world"},
+			expected: "https://api.example.com/files/a%2Fb%3Fc%23d/hello%20world",
+		},
+		{
+			name:     "keeps base query",
+			base:     "https://api.example.com/v1?key=value",
+			segments: []string{"users"},
+			expected: "https://api.example.com/v1/users?key=value",
+		},
+		{
+			name:     "rejects traversal",
+			base:     "https://api.example.com/v1",
+			segments: []string{"users", ".."},
+			err:      clink.ErrInvalidPathSegment,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := clink.JoinURL(tc.base, tc.segments...)
+			if tc.err != nil {
+				if !errors.Is(err, tc.err) {
+					t.Errorf("expected error %v, got: %v", tc.err, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if result != tc.expected {
+				t.Errorf("expected %s, got: %s", tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestBaseURL(t *testing.T) {
+	var requestURI string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestURI = r.RequestURI
+	}))
+	defer server.Close()
+
+	client := clink.NewClient(
+		clink.WithClient(server.Client()),
+		clink.WithBaseURL(server.URL+"/v1/"),
+	)
+
+	testCases := []struct {
+		url      string
+		expected string
+	}{
+		{url: "/users", expected: "/v1/users"},
+		{url: "users/a%2Fb?page=2", expected: "/v1/users/a%2Fb?page=2"},
+		{url: server.URL + "/absolute", expected: "/absolute"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.url, func(t *testing.T) {
+			if _, err := client.Get(tc.url); err != nil {
+				t.Fatalf("failed to make request: %v", err)
+			}
+
+			if requestURI != tc.expected {
+				t.Errorf("expected request uri %s, got: %s", tc.expected, requestURI)
+			}
+		})
+	}
+
+	if _, err := client.Get("../admin"); !errors.Is(err, clink.ErrInvalidPathSegment) {
+		t.Errorf("expected traversal to be rejected, got: %v", err)
+	}
+}
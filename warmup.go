@@ -0,0 +1,98 @@
+package clink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// Warmup opens a connection to each host ahead of the first request, so that the DNS lookup, TCP
+// and TLS handshakes don't add to the latency of the first requests, for example after a deploy.
+// Hosts are URLs like "https://api.example.com", or host names to which HTTPS is used. If no host
+// is given, the base URL and the endpoints of the client are warmed up. A connection is opened by
+// sending a HEAD request to the root of the host, whose response doesn't matter; the connection is
+// then kept in the client's pool. Warmup returns the errors of the hosts that couldn't be reached.
+func (c *Client) Warmup(ctx context.Context, hosts ...string) error {
+	c = c.config()
+
+	if err := c.configError(); err != nil {
+		return err
+	}
+
+	if len(hosts) == 0 {
+		hosts = c.warmupHosts()
+	}
+
+	client := *c.HttpClient
+	client.Jar = nil
+	client.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+
+	errs := make([]error, len(hosts))
+
+	var wg sync.WaitGroup
+	for i, host := range hosts {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			if err := c.warmup(ctx, &client, host); err != nil {
+				errs[i] = fmt.Errorf("failed to warm up %s: %w", host, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// warmupHosts returns the base URL and the endpoints of the client.
+func (c *Client) warmupHosts() []string {
+	var hosts []string
+	if c.BaseURL != "" {
+		hosts = append(hosts, c.BaseURL)
+	}
+
+	if c.endpoints != nil {
+		for _, e := range c.endpoints.list() {
+			if e.url != c.BaseURL {
+				hosts = append(hosts, e.url)
+			}
+		}
+	}
+
+	return hosts
+}
+
+func (c *Client) warmup(ctx context.Context, client *http.Client, host string) error {
+	if !strings.Contains(host, "://") {
+		host = "https://" + host
+	}
+
+	u, err := url.Parse(host)
+	if err != nil {
+		return fmt.Errorf("invalid host: %w", err)
+	}
+
+	if err := c.checkDestination(u); err != nil {
+		return err
+	}
+
+	root := url.URL{Scheme: u.Scheme, Host: u.Host, Path: "/"}
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, root.String(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	return DrainAndClose(resp)
+}
@@ -0,0 +1,93 @@
+package clink_test
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/davesavic/clink"
+)
+
+func TestClient_Warmup(t *testing.T) {
+	testCases := []struct {
+		name       string
+		hosts      func(server *httptest.Server) []string
+		resultFunc func(conns int32, err error) bool
+	}{
+		{
+			name: "url",
+			hosts: func(server *httptest.Server) []string {
+				return []string{server.URL}
+			},
+			resultFunc: func(conns int32, err error) bool {
+				return err == nil && conns == 1
+			},
+		},
+		{
+			name: "host name",
+			hosts: func(server *httptest.Server) []string {
+				return []string{strings.TrimPrefix(server.URL, "https://")}
+			},
+			resultFunc: func(conns int32, err error) bool {
+				return err == nil && conns == 1
+			},
+		},
+		{
+			name: "base url",
+			hosts: func(*httptest.Server) []string {
+				return nil
+			},
+			resultFunc: func(conns int32, err error) bool {
+				return err == nil && conns == 1
+			},
+		},
+		{
+			name: "unreachable host",
+			hosts: func(*httptest.Server) []string {
+				return []string{"https://127.0.0.1:1"}
+			},
+			resultFunc: func(conns int32, err error) bool {
+				return err != nil && strings.Contains(err.Error(), "127.0.0.1:1")
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var conns atomic.Int32
+			server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+			server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+				if state == http.StateNew {
+					conns.Add(1)
+				}
+			}
+			server.StartTLS()
+			defer server.Close()
+
+			c := clink.NewClient(clink.WithClient(server.Client()), clink.WithBaseURL(server.URL))
+
+			err := c.Warmup(context.Background(), tc.hosts(server)...)
+			if !tc.resultFunc(conns.Load(), err) {
+				t.Fatalf("unexpected result: %d connections, %v", conns.Load(), err)
+			}
+
+			if err != nil {
+				return
+			}
+
+			resp, err := c.Get("/")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			_ = resp.Body.Close()
+
+			if conns.Load() != 1 {
+				t.Errorf("expected the warmed up connection to be reused, got %d connections", conns.Load())
+			}
+		})
+	}
+}